@@ -14,12 +14,19 @@ limitations under the License.
 package main
 
 import (
-	"fmt"
 	"os"
+	"runtime"
 
 	"github.com/adalton/teleport-exercise/pkg/command"
 )
 
+// init locks the main goroutine to its thread.  This application adds that
+// thread to cgroups, which for cgroup v1 "tasks" files moves only that thread,
+// so the command must be started from it.
+func init() {
+	runtime.LockOSThread()
+}
+
 // main is the entrypoint of the cgexec application.  The application accepts
 // arguments in the form:
 //
@@ -33,11 +40,19 @@ import (
 //
 // If no "--" is found, then all arguments are treated as the command and
 // arguments to the program to exec.
+//
+// If the first argument is "--join=<pid>", the command is run in the
+// namespaces of the process with the given pid, and the exit status of this
+// application is that of the command.
+//
+// If the first argument is "--init", this application runs the command as its
+// child and acts as the init process of the command's PID namespace: it reaps
+// orphaned processes, forwards signals to the command, and exits with the
+// command's exit status.
+//
+// The jobmanager server provides the same functionality through its hidden
+// "init" subcommand, through which it re-executes itself to start jobs.  This
+// application remains for use with the CGEXEC_PATH environment variable.
 func main() {
-	if err := command.Cgexec(os.Args); err != nil {
-		fmt.Printf("cgexec failed: %v", err)
-	}
-
-	// Cgexec shouldn't return in a non-error case
-	os.Exit(1)
+	command.CgexecMain(os.Args)
 }
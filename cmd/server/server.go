@@ -17,18 +17,26 @@ import (
 	"context"
 	"net"
 	"os/signal"
+	"runtime"
 	"syscall"
 
 	"github.com/adalton/teleport-exercise/certs"
 	"github.com/adalton/teleport-exercise/pkg/command"
+	"github.com/adalton/teleport-exercise/pkg/config"
 
 	"github.com/spf13/cobra"
 )
 
 var (
-	argAddress string
+	argAddress               string
+	argRequireAllControllers bool
+	argInitProcess           bool
 )
 
+// initCommandName is the name of the hidden subcommand through which the
+// server re-executes itself to start jobs.
+const initCommandName = "init"
+
 var rootCmd = &cobra.Command{
 	Use:   "jobmanager",
 	Short: "Run the job manager server",
@@ -37,13 +45,47 @@ var rootCmd = &cobra.Command{
 	},
 }
 
+// initCmd provides the functionality of the cgexec application; see
+// command.Cgexec.  Its arguments are passed to cgexec as they are.
+var initCmd = &cobra.Command{
+	Use:                initCommandName + " [<cgtskfile> ...] -- <command> [<arg> ...]",
+	Short:              "Start a job's command (for internal use by the server)",
+	Hidden:             true,
+	DisableFlagParsing: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		// The job's command must be started from the thread that joins
+		// the job's cgroups; see command.Cgexec.
+		runtime.LockOSThread()
+
+		// Cgexec may re-execute itself through this subcommand
+		config.UseSelfExec(initCommandName)
+
+		command.CgexecMain(append([]string{cmd.CommandPath()}, args...))
+	},
+}
+
 func init() {
+	rootCmd.AddCommand(initCmd)
+
 	rootCmd.PersistentFlags().StringVarP(
 		&argAddress,
 		"address",
 		"a",
 		":24482",
 		"The <address>:<port> on which this server should listen for incoming requests")
+
+	rootCmd.PersistentFlags().BoolVar(
+		&argRequireAllControllers,
+		"requireAllControllers",
+		false,
+		"Refuse to start if any cgroup controller needed to enforce job limits is unavailable")
+
+	rootCmd.PersistentFlags().BoolVar(
+		&argInitProcess,
+		"initProcess",
+		config.CgexecInit,
+		"Run an init process in each job's PID namespace that reaps orphaned processes and "+
+			"forwards signals to the job's program (uses a few hundred KiB of the job's memory limit)")
 }
 
 func runServer(ctx context.Context) error {
@@ -56,12 +98,16 @@ func runServer(ctx context.Context) error {
 	}
 	defer listener.Close()
 
-	err = command.RunJobmanagerServer(
+	config.UseSelfExec(initCommandName)
+	config.CgexecInit = argInitProcess
+
+	err = command.RunJobmanagerServerDetailed(
 		ctx,
 		listener,
 		certs.CACert,
 		certs.ServerCert,
 		certs.ServerKey,
+		argRequireAllControllers,
 	)
 
 	if err != nil {
@@ -19,6 +19,12 @@ import (
 
 type FileMode = goos.FileMode
 
+type DirEntry = goos.DirEntry
+
+type FileInfo = goos.FileInfo
+
+type File = goos.File
+
 var Args = goos.Args
 
 // Adapter serves as a shim between between callers of standard os.* APIs
@@ -31,6 +37,10 @@ type Adapter struct {
 	MkdirAllFn  func(path string, perm goos.FileMode) error
 	RemoveFn    func(name string) error
 	WriteFileFn func(name string, data []byte, perm goos.FileMode) error
+	ReadFileFn  func(name string) ([]byte, error)
+	ReadDirFn   func(name string) ([]goos.DirEntry, error)
+	StatFn      func(name string) (goos.FileInfo, error)
+	OpenFn      func(name string) (*goos.File, error)
 	GetpidFn    func() int
 	EnvironFn   func() []string
 }
@@ -65,6 +75,46 @@ func (a *Adapter) WriteFile(name string, data []byte, perm goos.FileMode) error
 	return fn(name, data, perm)
 }
 
+func (a *Adapter) ReadFile(name string) ([]byte, error) {
+	fn := goos.ReadFile
+
+	if a != nil && a.ReadFileFn != nil {
+		fn = a.ReadFileFn
+	}
+
+	return fn(name)
+}
+
+func (a *Adapter) ReadDir(name string) ([]goos.DirEntry, error) {
+	fn := goos.ReadDir
+
+	if a != nil && a.ReadDirFn != nil {
+		fn = a.ReadDirFn
+	}
+
+	return fn(name)
+}
+
+func (a *Adapter) Stat(name string) (goos.FileInfo, error) {
+	fn := goos.Stat
+
+	if a != nil && a.StatFn != nil {
+		fn = a.StatFn
+	}
+
+	return fn(name)
+}
+
+func (a *Adapter) Open(name string) (*goos.File, error) {
+	fn := goos.Open
+
+	if a != nil && a.OpenFn != nil {
+		fn = a.OpenFn
+	}
+
+	return fn(name)
+}
+
 func (a *Adapter) Getpid() int {
 	fn := goos.Getpid
 
@@ -0,0 +1,32 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ostest
+
+import goos "os"
+
+// ReadFileMock is a component that provides a mock implementation of the
+// os.ReadFile() function.  The implementation returns the content configured
+// for the requested file name in Files.  If there is no such entry, it returns
+// an error that satisfies os.IsNotExist().
+type ReadFileMock struct {
+	Files map[string]string
+}
+
+func (r *ReadFileMock) ReadFile(name string) ([]byte, error) {
+	if content, exists := r.Files[name]; exists {
+		return []byte(content), nil
+	}
+
+	return nil, &goos.PathError{Op: "open", Path: name, Err: goos.ErrNotExist}
+}
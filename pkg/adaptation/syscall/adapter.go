@@ -13,7 +13,11 @@ limitations under the License.
 
 package syscall
 
-import gosyscall "syscall"
+import (
+	gosyscall "syscall"
+
+	"golang.org/x/sys/unix"
+)
 
 // Adapter serves as a shim between between callers of standard syscall.* APIs
 // and the functions themselves.  The default behavior is simply to dispatch
@@ -23,6 +27,15 @@ import gosyscall "syscall"
 // dispatch to that function instead.
 type Adapter struct {
 	ExecFn func(argv0 string, argv []string, envv []string) (err error)
+	StatFn func(path string, stat *gosyscall.Stat_t) (err error)
+	KillFn func(pid int, sig gosyscall.Signal) (err error)
+
+	GettidFn func() (tid int)
+
+	SetnsFn    func(fd int, nstype int) (err error)
+	UnshareFn  func(flags int) (err error)
+	ForkExecFn func(argv0 string, argv []string, attr *gosyscall.ProcAttr) (pid int, err error)
+	Wait4Fn    func(pid int, wstatus *gosyscall.WaitStatus, options int, rusage *gosyscall.Rusage) (wpid int, err error)
 }
 
 func (a *Adapter) Exec(argv0 string, argv []string, envv []string) (err error) {
@@ -34,3 +47,78 @@ func (a *Adapter) Exec(argv0 string, argv []string, envv []string) (err error) {
 
 	return fn(argv0, argv, envv)
 }
+
+func (a *Adapter) Stat(path string, stat *gosyscall.Stat_t) (err error) {
+	fn := gosyscall.Stat
+
+	if a != nil && a.StatFn != nil {
+		fn = a.StatFn
+	}
+
+	return fn(path, stat)
+}
+
+func (a *Adapter) Kill(pid int, sig gosyscall.Signal) (err error) {
+	fn := gosyscall.Kill
+
+	if a != nil && a.KillFn != nil {
+		fn = a.KillFn
+	}
+
+	return fn(pid, sig)
+}
+
+func (a *Adapter) Gettid() (tid int) {
+	fn := gosyscall.Gettid
+
+	if a != nil && a.GettidFn != nil {
+		fn = a.GettidFn
+	}
+
+	return fn()
+}
+
+func (a *Adapter) Setns(fd int, nstype int) (err error) {
+	fn := unix.Setns
+
+	if a != nil && a.SetnsFn != nil {
+		fn = a.SetnsFn
+	}
+
+	return fn(fd, nstype)
+}
+
+func (a *Adapter) Unshare(flags int) (err error) {
+	fn := unix.Unshare
+
+	if a != nil && a.UnshareFn != nil {
+		fn = a.UnshareFn
+	}
+
+	return fn(flags)
+}
+
+func (a *Adapter) ForkExec(argv0 string, argv []string, attr *gosyscall.ProcAttr) (pid int, err error) {
+	fn := gosyscall.ForkExec
+
+	if a != nil && a.ForkExecFn != nil {
+		fn = a.ForkExecFn
+	}
+
+	return fn(argv0, argv, attr)
+}
+
+func (a *Adapter) Wait4(
+	pid int,
+	wstatus *gosyscall.WaitStatus,
+	options int,
+	rusage *gosyscall.Rusage,
+) (wpid int, err error) {
+	fn := gosyscall.Wait4
+
+	if a != nil && a.Wait4Fn != nil {
+		fn = a.Wait4Fn
+	}
+
+	return fn(pid, wstatus, options, rusage)
+}
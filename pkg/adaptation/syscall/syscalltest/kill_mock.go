@@ -0,0 +1,38 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syscalltest
+
+import "syscall"
+
+type KillRecord struct {
+	Pid    int
+	Signal syscall.Signal
+}
+
+// KillMock is a mock implementation of the Kill system call wrapper.  The
+// implementation records the parameters received and returns the configured
+// NextError.
+type KillMock struct {
+	Events    []*KillRecord
+	NextError error
+}
+
+func (k *KillMock) Kill(pid int, sig syscall.Signal) error {
+	k.Events = append(k.Events, &KillRecord{
+		Pid:    pid,
+		Signal: sig,
+	})
+
+	return k.NextError
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syscalltest
+
+import (
+	"os"
+	"syscall"
+)
+
+// StatMock is a mock implementation of the Stat system call wrapper.
+// Stat populates the given Stat_t with the entry in Files that is associated
+// with the given path.  If there is no such entry, Stat returns an error
+// for which os.IsNotExist is true.
+type StatMock struct {
+	Files map[string]syscall.Stat_t
+}
+
+func (s *StatMock) Stat(path string, stat *syscall.Stat_t) error {
+	entry, exists := s.Files[path]
+	if !exists {
+		return &os.PathError{Op: "stat", Path: path, Err: os.ErrNotExist}
+	}
+
+	*stat = entry
+
+	return nil
+}
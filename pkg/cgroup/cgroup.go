@@ -0,0 +1,74 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroup
+
+import "io"
+
+// Controller defines the interface to a cgroup controller.  Both
+// cgroupv1.Controller and cgroupv2.Controller satisfy this interface, but a
+// given controller must only be used with a Hierarchy of the matching Version.
+type Controller interface {
+	// Name returns the name of the controller
+	Name() string
+
+	// Apply applies this controller's configuration to the cgroup at the
+	// given path.
+	Apply(path string) error
+}
+
+// Set defines the interface to a collection of cgroup controllers that are
+// created and removed together for a single job.
+type Set interface {
+	// Create creates the cgroups for all registered controllers.
+	Create() error
+
+	// Destroy removes the cgroups for all registered controllers.
+	Destroy() error
+
+	// TaskFiles returns the files to which a process must write its PID to
+	// add itself to the cgroups in this set.
+	TaskFiles() []string
+
+	// ReadFile returns the content of the file with the given name in the
+	// job's cgroup for the named controller (e.g., "pids.events").
+	ReadFile(controllerName, filename string) ([]byte, error)
+
+	// WatchOom calls notify each time the job's memory cgroup may have
+	// reported an out-of-memory event, until the returned Closer is closed.
+	// Notifications are hints; callers should check the OOM kill count.
+	WatchOom(notify func()) (io.Closer, error)
+
+	// Freeze and Thaw freeze and thaw the processes in the job's cgroups.
+	// Freezing is asynchronous; Frozen reports when it is complete.  For
+	// v1, Freeze fails unless the set includes a freezer controller.
+	Freeze() error
+	Thaw() error
+	Frozen() (bool, error)
+
+	// Pids returns the processes in the job's cgroups.
+	Pids() ([]int, error)
+}
+
+// Group defines the interface to the cgroups that a collection of processes
+// share, such as the jobs of one user or the server itself.  The Sets of the
+// jobs in a group are created with the group's name.
+type Group interface {
+	// Create creates the group's cgroups, if they do not already exist, and
+	// applies the configuration of its controllers.
+	Create() error
+
+	// AddProcess moves the process with the given pid into the group's
+	// cgroups.
+	AddProcess(pid int) error
+}
@@ -22,15 +22,24 @@ import (
 const (
 	BlkioThrottleReadBpsDevice  = "blkio.throttle.read_bps_device"
 	BlkioThrottleWriteBpsDevice = "blkio.throttle.write_bps_device"
+
+	BlkioThrottleReadIopsDevice  = "blkio.throttle.read_iops_device"
+	BlkioThrottleWriteIopsDevice = "blkio.throttle.write_iops_device"
 )
 
 // BlockIOController implements the BlockIOController cgroup controller.
-// Both ReadBpsDevice and WriteBpsDevice are the strings that are written to
-// the cgroup files.  Their format is: "<major>:<minor> <bytesPerSecond>"
+// Each entry in ReadBpsDevices, WriteBpsDevices, ReadIopsDevices and
+// WriteIopsDevices is a string that is written to the corresponding cgroup
+// file.  Their format is: "<major>:<minor> <bytesPerSecond>" for the Bps
+// limits and "<major>:<minor> <operationsPerSecond>" for the Iops limits.
+// The kernel keeps a separate limit for each device, so the entries are
+// written one at a time.
 type BlockIOController struct {
-	OsAdapter      *os.Adapter
-	ReadBpsDevice  string
-	WriteBpsDevice string
+	OsAdapter        *os.Adapter
+	ReadBpsDevices   []string
+	WriteBpsDevices  []string
+	ReadIopsDevices  []string
+	WriteIopsDevices []string
 }
 
 func (BlockIOController) Name() string {
@@ -40,18 +49,28 @@ func (BlockIOController) Name() string {
 // Apply applies this cgroup controller configuration to the blkio cgroup
 // at the given path.
 func (b *BlockIOController) Apply(path string) error {
-	if b.ReadBpsDevice != "" {
-		filename := fmt.Sprintf("%s/%s", path, BlkioThrottleReadBpsDevice)
+	if err := b.writeLimits(path, BlkioThrottleReadBpsDevice, b.ReadBpsDevices); err != nil {
+		return err
+	}
 
-		if err := b.OsAdapter.WriteFile(filename, []byte(b.ReadBpsDevice), os.FileMode(0644)); err != nil {
-			return err
-		}
+	if err := b.writeLimits(path, BlkioThrottleWriteBpsDevice, b.WriteBpsDevices); err != nil {
+		return err
 	}
 
-	if b.WriteBpsDevice != "" {
-		filename := fmt.Sprintf("%s/%s", path, BlkioThrottleWriteBpsDevice)
+	if err := b.writeLimits(path, BlkioThrottleReadIopsDevice, b.ReadIopsDevices); err != nil {
+		return err
+	}
+
+	return b.writeLimits(path, BlkioThrottleWriteIopsDevice, b.WriteIopsDevices)
+}
+
+// writeLimits writes each of the given deviceLimits to the file with the
+// given name in the cgroup at the given path.
+func (b *BlockIOController) writeLimits(path, name string, deviceLimits []string) error {
+	filename := fmt.Sprintf("%s/%s", path, name)
 
-		if err := b.OsAdapter.WriteFile(filename, []byte(b.WriteBpsDevice), os.FileMode(0644)); err != nil {
+	for _, deviceLimit := range deviceLimits {
+		if err := b.OsAdapter.WriteFile(filename, []byte(deviceLimit), os.FileMode(0644)); err != nil {
 			return err
 		}
 	}
@@ -35,9 +35,9 @@ func Test_blkio_Apply(t *testing.T) {
 	writeBps := "1:3 900M"
 
 	blkio := cgroupv1.BlockIOController{
-		OsAdapter:      adapter,
-		ReadBpsDevice:  readBps,
-		WriteBpsDevice: writeBps,
+		OsAdapter:       adapter,
+		ReadBpsDevices:  []string{readBps},
+		WriteBpsDevices: []string{writeBps},
 	}
 
 	blkio.Apply(path)
@@ -49,3 +49,55 @@ func Test_blkio_Apply(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("%s/%s", path, cgroupv1.BlkioThrottleWriteBpsDevice), writeRecorder.Events[1].Name)
 	assert.Equal(t, []byte(writeBps), writeRecorder.Events[1].Data)
 }
+
+func Test_blkio_Apply_MultipleDevices(t *testing.T) {
+	path := "/sys/fs/cgroup/jobs/889f7cc2-9935-4773-aaa1-b94478abc923"
+	writeRecorder := ostest.WriteFileMock{}
+	adapter := &os.Adapter{
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	blkio := cgroupv1.BlockIOController{
+		OsAdapter:       adapter,
+		ReadBpsDevices:  []string{"8:0 1048576", "259:0 2097152"},
+		WriteBpsDevices: []string{"259:0 4194304"},
+	}
+
+	err := blkio.Apply(path)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(writeRecorder.Events))
+
+	readFilename := fmt.Sprintf("%s/%s", path, cgroupv1.BlkioThrottleReadBpsDevice)
+	assert.Equal(t, readFilename, writeRecorder.Events[0].Name)
+	assert.Equal(t, []byte("8:0 1048576"), writeRecorder.Events[0].Data)
+	assert.Equal(t, readFilename, writeRecorder.Events[1].Name)
+	assert.Equal(t, []byte("259:0 2097152"), writeRecorder.Events[1].Data)
+
+	assert.Equal(t, fmt.Sprintf("%s/%s", path, cgroupv1.BlkioThrottleWriteBpsDevice), writeRecorder.Events[2].Name)
+	assert.Equal(t, []byte("259:0 4194304"), writeRecorder.Events[2].Data)
+}
+
+func Test_blkio_Apply_Iops(t *testing.T) {
+	path := "/sys/fs/cgroup/jobs/889f7cc2-9935-4773-aaa1-b94478abc923"
+	writeRecorder := ostest.WriteFileMock{}
+	adapter := &os.Adapter{
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	blkio := cgroupv1.BlockIOController{
+		OsAdapter:        adapter,
+		ReadIopsDevices:  []string{"8:0 100"},
+		WriteIopsDevices: []string{"8:0 50"},
+	}
+
+	err := blkio.Apply(path)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(writeRecorder.Events))
+	assert.Equal(t, fmt.Sprintf("%s/%s", path, cgroupv1.BlkioThrottleReadIopsDevice), writeRecorder.Events[0].Name)
+	assert.Equal(t, []byte("8:0 100"), writeRecorder.Events[0].Data)
+
+	assert.Equal(t, fmt.Sprintf("%s/%s", path, cgroupv1.BlkioThrottleWriteIopsDevice), writeRecorder.Events[1].Name)
+	assert.Equal(t, []byte("8:0 50"), writeRecorder.Events[1].Data)
+}
@@ -22,23 +22,29 @@ import (
 const (
 	CpuPeriodFilename = "cpu.cfs_period_us"
 	CpuQuotaFilename  = "cpu.cfs_quota_us"
+	CpuSharesFilename = "cpu.shares"
 
 	defaultPeriodUs = 100000
 )
 
-var defaultPeriodBytes = []byte(fmt.Sprintf("%d", defaultPeriodUs))
-
-// CpuController implements cgroup v2 control using CFS Bandwidth Control.
+// CpuController implements cgroup v1 control using CFS Bandwidth Control.
 // See doc/Documentation/scheduler/sched-bwc.txt in the kernel source tree for
 // additional information.
 //
 // This implementation exposes that functionality in terms of how much of the
 // available CPU resources a collection of processes can use (0.5 = half a CPU,
 // 1.0 = 1 CPU, 1.5 = 1 and a half CPUs, ...).
-// The period is defaultPeriodUs and the quota is cpus*period.
+// The period is PeriodUs (defaultPeriodUs if zero) and the quota is
+// cpus*period.  If Cpus is zero, no quota is set.
+//
+// Shares, if non-zero, sets the relative weight of the cgroup when CPUs are
+// contended (the kernel's default is 1024).  Unlike the quota, shares do not
+// prevent the cgroup from using idle CPU capacity.
 type CpuController struct {
 	OsAdapter *os.Adapter
 	Cpus      float64
+	PeriodUs  uint64
+	Shares    uint64
 }
 
 func (CpuController) Name() string {
@@ -47,18 +53,31 @@ func (CpuController) Name() string {
 
 func (c *CpuController) Apply(path string) error {
 	if c.Cpus != 0 {
+		periodUs := c.PeriodUs
+		if periodUs == 0 {
+			periodUs = defaultPeriodUs
+		}
 
 		filename := fmt.Sprintf("%s/%s", path, CpuPeriodFilename)
-		if err := c.OsAdapter.WriteFile(filename, defaultPeriodBytes, os.FileMode(0644)); err != nil {
+		period := fmt.Sprintf("%d", periodUs)
+		if err := c.OsAdapter.WriteFile(filename, []byte(period), os.FileMode(0644)); err != nil {
 			return err
 		}
 
 		filename = fmt.Sprintf("%s/%s", path, CpuQuotaFilename)
-		quota := fmt.Sprintf("%d", int(c.Cpus*defaultPeriodUs))
+		quota := fmt.Sprintf("%d", int(c.Cpus*float64(periodUs)))
 		if err := c.OsAdapter.WriteFile(filename, []byte(quota), os.FileMode(0644)); err != nil {
 			return err
 		}
 	}
 
+	if c.Shares != 0 {
+		filename := fmt.Sprintf("%s/%s", path, CpuSharesFilename)
+		shares := fmt.Sprintf("%d", c.Shares)
+		if err := c.OsAdapter.WriteFile(filename, []byte(shares), os.FileMode(0644)); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
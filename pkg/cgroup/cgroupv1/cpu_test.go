@@ -41,3 +41,41 @@ func Test_cpu_Apply(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("%s/%s", path, cgroupv1.CpuQuotaFilename), writeRecorder.Events[1].Name)
 	assert.Equal(t, []byte("200000"), writeRecorder.Events[1].Data)
 }
+
+func Test_cpu_Apply_PeriodAndShares(t *testing.T) {
+	path := "/sys/fs/cgroup/jobs/889f7cc2-9935-4773-aaa1-b94478abc923"
+	writeRecorder := ostest.WriteFileMock{}
+	adapter := &os.Adapter{
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	cpu := cgroupv1.CpuController{OsAdapter: adapter, Cpus: 0.5, PeriodUs: 20000, Shares: 2048}
+	err := cpu.Apply(path)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(writeRecorder.Events))
+	assert.Equal(t, fmt.Sprintf("%s/%s", path, cgroupv1.CpuPeriodFilename), writeRecorder.Events[0].Name)
+	assert.Equal(t, []byte("20000"), writeRecorder.Events[0].Data)
+
+	assert.Equal(t, fmt.Sprintf("%s/%s", path, cgroupv1.CpuQuotaFilename), writeRecorder.Events[1].Name)
+	assert.Equal(t, []byte("10000"), writeRecorder.Events[1].Data)
+
+	assert.Equal(t, fmt.Sprintf("%s/%s", path, cgroupv1.CpuSharesFilename), writeRecorder.Events[2].Name)
+	assert.Equal(t, []byte("2048"), writeRecorder.Events[2].Data)
+}
+
+func Test_cpu_Apply_SharesOnly(t *testing.T) {
+	path := "/sys/fs/cgroup/jobs/889f7cc2-9935-4773-aaa1-b94478abc923"
+	writeRecorder := ostest.WriteFileMock{}
+	adapter := &os.Adapter{
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	cpu := cgroupv1.CpuController{OsAdapter: adapter, Shares: 512}
+	err := cpu.Apply(path)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(writeRecorder.Events))
+	assert.Equal(t, fmt.Sprintf("%s/%s", path, cgroupv1.CpuSharesFilename), writeRecorder.Events[0].Name)
+	assert.Equal(t, []byte("512"), writeRecorder.Events[0].Data)
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv1
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+)
+
+const (
+	CpusetCpusFilename = "cpuset.cpus"
+	CpusetMemsFilename = "cpuset.mems"
+)
+
+// CpusetController configures the cpuset cgroup controller.  Cpus and Mems
+// are lists of CPUs and memory nodes in the kernel's list format (e.g.,
+// "0-3,8").  An empty list gives the cgroup all of the CPUs or memory nodes
+// of its parent.
+//
+// In cgroup v1, a newly-created cpuset cgroup has empty cpuset.cpus and
+// cpuset.mems files and no process can join it until both are set.  Apply
+// therefore initializes the ancestors (e.g., the jobs and group cgroups) from
+// their own parents if necessary before configuring the job's cgroup.
+type CpusetController struct {
+	OsAdapter *os.Adapter
+	Cpus      string
+	Mems      string
+}
+
+func (CpusetController) Name() string {
+	return "cpuset"
+}
+
+func (c *CpusetController) Apply(cgroupPath string) error {
+	if err := c.apply(cgroupPath, CpusetCpusFilename, c.Cpus); err != nil {
+		return err
+	}
+
+	return c.apply(cgroupPath, CpusetMemsFilename, c.Mems)
+}
+
+// apply writes the given value to the file with the given name in the cgroup
+// at cgroupPath.  If value is empty, the value of the parent cgroup is used.
+func (c *CpusetController) apply(cgroupPath, filename, value string) error {
+	parentValue, err := c.initialize(path.Dir(cgroupPath), filename)
+	if err != nil {
+		return err
+	}
+
+	if value == "" {
+		value = parentValue
+	}
+
+	return c.OsAdapter.WriteFile(
+		fmt.Sprintf("%s/%s", cgroupPath, filename), []byte(value), os.FileMode(0644))
+}
+
+// initialize ensures that the file with the given name in the cgroup at dir
+// is not empty by copying the value from the parent cgroup if necessary,
+// initializing the parent first if it is also empty.  It returns the
+// resulting value.
+func (c *CpusetController) initialize(dir, filename string) (string, error) {
+	filePath := fmt.Sprintf("%s/%s", dir, filename)
+
+	content, err := c.OsAdapter.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	value := strings.TrimSpace(string(content))
+	if value != "" {
+		return value, nil
+	}
+
+	// The root cgroup is never empty, so the recursion ends there at the
+	// latest.
+	value, err = c.initialize(path.Dir(dir), filename)
+	if err != nil {
+		return "", err
+	}
+
+	if err := c.OsAdapter.WriteFile(filePath, []byte(value), os.FileMode(0644)); err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv1_test
+
+import (
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os/ostest"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	cpusetRoot = "/sys/fs/cgroup/cpuset"
+	cpusetJobs = cpusetRoot + "/jobs"
+	cpusetJob  = cpusetJobs + "/889f7cc2-9935-4773-aaa1-b94478abc923"
+)
+
+func Test_cpuset_Apply_InitializesParent(t *testing.T) {
+	readFileMock := &ostest.ReadFileMock{
+		Files: map[string]string{
+			cpusetRoot + "/cpuset.cpus": "0-7\n",
+			cpusetRoot + "/cpuset.mems": "0\n",
+			cpusetJobs + "/cpuset.cpus": "\n",
+			cpusetJobs + "/cpuset.mems": "\n",
+		},
+	}
+	writeRecorder := ostest.WriteFileMock{}
+	adapter := &os.Adapter{
+		ReadFileFn:  readFileMock.ReadFile,
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	cpuset := &cgroupv1.CpusetController{OsAdapter: adapter, Cpus: "2-3"}
+	err := cpuset.Apply(cpusetJob)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(writeRecorder.Events))
+	assert.Equal(t, cpusetJobs+"/cpuset.cpus", writeRecorder.Events[0].Name)
+	assert.Equal(t, []byte("0-7"), writeRecorder.Events[0].Data)
+	assert.Equal(t, cpusetJob+"/cpuset.cpus", writeRecorder.Events[1].Name)
+	assert.Equal(t, []byte("2-3"), writeRecorder.Events[1].Data)
+	assert.Equal(t, cpusetJobs+"/cpuset.mems", writeRecorder.Events[2].Name)
+	assert.Equal(t, []byte("0"), writeRecorder.Events[2].Data)
+	assert.Equal(t, cpusetJob+"/cpuset.mems", writeRecorder.Events[3].Name)
+	assert.Equal(t, []byte("0"), writeRecorder.Events[3].Data)
+}
+
+func Test_cpuset_Apply_ParentInitialized(t *testing.T) {
+	readFileMock := &ostest.ReadFileMock{
+		Files: map[string]string{
+			cpusetJobs + "/cpuset.cpus": "0-7\n",
+			cpusetJobs + "/cpuset.mems": "0-1\n",
+		},
+	}
+	writeRecorder := ostest.WriteFileMock{}
+	adapter := &os.Adapter{
+		ReadFileFn:  readFileMock.ReadFile,
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	cpuset := &cgroupv1.CpusetController{OsAdapter: adapter, Mems: "1"}
+	err := cpuset.Apply(cpusetJob)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(writeRecorder.Events))
+	assert.Equal(t, cpusetJob+"/cpuset.cpus", writeRecorder.Events[0].Name)
+	assert.Equal(t, []byte("0-7"), writeRecorder.Events[0].Data)
+	assert.Equal(t, cpusetJob+"/cpuset.mems", writeRecorder.Events[1].Name)
+	assert.Equal(t, []byte("1"), writeRecorder.Events[1].Data)
+}
+
+func Test_cpuset_Apply_ReadFailure(t *testing.T) {
+	readFileMock := &ostest.ReadFileMock{}
+	writeRecorder := ostest.WriteFileMock{}
+	adapter := &os.Adapter{
+		ReadFileFn:  readFileMock.ReadFile,
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	cpuset := &cgroupv1.CpusetController{OsAdapter: adapter, Cpus: "0"}
+	err := cpuset.Apply(cpusetJob)
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, len(writeRecorder.Events))
+}
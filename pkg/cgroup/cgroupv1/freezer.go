@@ -0,0 +1,134 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv1
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+)
+
+const (
+	FreezerStateFilename = "freezer.state"
+	FreezerStateFrozen   = "FROZEN"
+	FreezerStateThawed   = "THAWED"
+)
+
+// FreezerController enables the freezer cgroup controller, which the Set
+// uses to stop the job's processes from forking while they are being killed
+// (see Set.Freeze).  It imposes no limit; Apply ensures that the newly-created
+// cgroup is thawed.
+type FreezerController struct {
+	OsAdapter *os.Adapter
+}
+
+func (FreezerController) Name() string {
+	return "freezer"
+}
+
+func (f *FreezerController) Apply(path string) error {
+	filename := fmt.Sprintf("%s/%s", path, FreezerStateFilename)
+
+	return f.OsAdapter.WriteFile(filename, []byte(FreezerStateThawed), os.FileMode(0644))
+}
+
+// Freeze freezes the processes in the job's freezer cgroup.  Freezing is
+// asynchronous; see Frozen.  If the set has no freezer controller, Freeze
+// returns an error.
+func (s *Set) Freeze() error {
+	return s.writeFreezerState(FreezerStateFrozen)
+}
+
+// Thaw thaws the processes in the job's freezer cgroup.  In cgroup v1, a
+// frozen process cannot act on any signal, including SIGKILL, until it is
+// thawed.
+func (s *Set) Thaw() error {
+	return s.writeFreezerState(FreezerStateThawed)
+}
+
+// Frozen returns true if all of the processes in the job's freezer cgroup
+// are frozen, false otherwise.
+func (s *Set) Frozen() (bool, error) {
+	content, err := s.ReadFile("freezer", FreezerStateFilename)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.TrimSpace(string(content)) == FreezerStateFrozen, nil
+}
+
+// Pids returns the processes in all of the job's cgroups, without
+// duplicates.
+func (s *Set) Pids() ([]int, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	var pids []int
+	seen := make(map[int]bool)
+	read := make(map[string]bool)
+
+	for i := range s.controllers {
+		filename := fmt.Sprintf("%s/%s", s.cgroupDir(s.jobID, s.controllers[i].Name()), ProcsFilename)
+
+		if read[filename] {
+			continue
+		}
+		read[filename] = true
+
+		content, err := s.osAdapter.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		for scanner.Scan() {
+			pid, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+			if err != nil {
+				continue
+			}
+
+			if !seen[pid] {
+				seen[pid] = true
+				pids = append(pids, pid)
+			}
+		}
+	}
+
+	return pids, nil
+}
+
+func (s *Set) writeFreezerState(state string) error {
+	if s == nil || !s.hasController("freezer") {
+		return fmt.Errorf("no cgroup for controller freezer")
+	}
+
+	filename := fmt.Sprintf("%s/%s", s.cgroupDir(s.jobID, "freezer"), FreezerStateFilename)
+
+	return s.osAdapter.WriteFile(filename, []byte(state), os.FileMode(0644))
+}
+
+func (s *Set) hasController(name string) bool {
+	for i := range s.controllers {
+		if s.controllers[i].Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
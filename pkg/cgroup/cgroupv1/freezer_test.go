@@ -0,0 +1,115 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv1_test
+
+import (
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os/ostest"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1/cgroupv1test"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const freezerJobID = "0b5183b8-b572-49c7-90c4-fffc775b7d7b"
+
+func Test_freezer_Apply(t *testing.T) {
+	path := "/sys/fs/cgroup/freezer/jobs/" + freezerJobID
+	writeRecorder := ostest.WriteFileMock{}
+	adapter := &os.Adapter{
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	freezer := &cgroupv1.FreezerController{OsAdapter: adapter}
+	err := freezer.Apply(path)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(writeRecorder.Events))
+	assert.Equal(t, path+"/"+cgroupv1.FreezerStateFilename, writeRecorder.Events[0].Name)
+	assert.Equal(t, []byte(cgroupv1.FreezerStateThawed), writeRecorder.Events[0].Data)
+}
+
+func Test_Set_Freeze(t *testing.T) {
+	writeRecorder := ostest.WriteFileMock{}
+	readFileMock := &ostest.ReadFileMock{
+		Files: map[string]string{
+			"/cg/freezer/jobs/" + freezerJobID + "/" + cgroupv1.FreezerStateFilename: "FREEZING\n",
+		},
+	}
+	adapter := &os.Adapter{
+		WriteFileFn: writeRecorder.WriteFile,
+		ReadFileFn:  readFileMock.ReadFile,
+	}
+
+	set := cgroupv1.NewSetDetailed(adapter, "/cg", "jobs", uuid.MustParse(freezerJobID),
+		&cgroupv1test.ControllerMock{ControllerName: "memory"},
+		&cgroupv1test.ControllerMock{ControllerName: "freezer"},
+	)
+
+	assert.Nil(t, set.Freeze())
+	frozen, err := set.Frozen()
+	assert.Nil(t, err)
+	assert.False(t, frozen)
+
+	readFileMock.Files["/cg/freezer/jobs/"+freezerJobID+"/"+cgroupv1.FreezerStateFilename] = "FROZEN\n"
+	frozen, err = set.Frozen()
+	assert.Nil(t, err)
+	assert.True(t, frozen)
+
+	assert.Nil(t, set.Thaw())
+
+	assert.Equal(t, 2, len(writeRecorder.Events))
+	assert.Equal(t, "/cg/freezer/jobs/"+freezerJobID+"/"+cgroupv1.FreezerStateFilename, writeRecorder.Events[0].Name)
+	assert.Equal(t, []byte(cgroupv1.FreezerStateFrozen), writeRecorder.Events[0].Data)
+	assert.Equal(t, []byte(cgroupv1.FreezerStateThawed), writeRecorder.Events[1].Data)
+}
+
+func Test_Set_Freeze_NoFreezer(t *testing.T) {
+	writeRecorder := ostest.WriteFileMock{}
+	adapter := &os.Adapter{
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	set := cgroupv1.NewSetDetailed(adapter, "/cg", "jobs", uuid.MustParse(freezerJobID),
+		&cgroupv1test.ControllerMock{ControllerName: "memory"},
+	)
+
+	assert.NotNil(t, set.Freeze())
+	assert.Equal(t, 0, len(writeRecorder.Events))
+}
+
+func Test_Set_Pids(t *testing.T) {
+	readFileMock := &ostest.ReadFileMock{
+		Files: map[string]string{
+			"/cg/cpu/jobs/" + freezerJobID + "/" + cgroupv1.ProcsFilename:    "10\n11\n",
+			"/cg/memory/jobs/" + freezerJobID + "/" + cgroupv1.ProcsFilename: "11\n12\n",
+		},
+	}
+	adapter := &os.Adapter{
+		ReadFileFn: readFileMock.ReadFile,
+	}
+
+	set := cgroupv1.NewSetDetailed(adapter, "/cg", "jobs", uuid.MustParse(freezerJobID),
+		&cgroupv1test.ControllerMock{ControllerName: "cpu"},
+		&cgroupv1test.ControllerMock{ControllerName: "memory"},
+	)
+
+	pids, err := set.Pids()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []int{10, 11, 12}, pids)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv1
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+)
+
+// Group maintains the cgroups that a collection of processes share, such as
+// all of the jobs of one user, or the server itself.  The group's
+// controllers limit the aggregate resource usage of all of the processes in
+// the group, including those in its descendant cgroups.  Jobs' own cgroups
+// are created in a group's cgroups by a Set whose parent is the group's path.
+type Group struct {
+	osAdapter   *os.Adapter
+	basePath    string
+	path        string
+	controllers []Controller
+}
+
+// NewGroup creates a new cgroup (v1) group with the given name within the
+// default jobs cgroups.  This assumes that the cgroup filesystem is mounted
+// at /sys/fs/cgroup.
+func NewGroup(name string, controllers ...Controller) *Group {
+	return NewGroupDetailed(nil, DefaultBasePath, path.Join(DefaultJobsParent, name), controllers...)
+}
+
+// NewGroupDetailed creates a new cgroup (v1) group for the cgroups at the
+// given path (e.g., "jobs/user1") within each controller's hierarchy rooted
+// at the given basePath.
+func NewGroupDetailed(
+	osAdapter *os.Adapter,
+	basePath string,
+	path string,
+	controllers ...Controller,
+) *Group {
+
+	return &Group{
+		osAdapter:   osAdapter,
+		basePath:    basePath,
+		path:        path,
+		controllers: controllers,
+	}
+}
+
+// Create creates the group's cgroups for all registered controllers, if they
+// do not already exist, and applies the controllers' configuration.  Create
+// may be called each time a job joins the group; the group's cgroups are not
+// removed when its jobs terminate.
+func (g *Group) Create() error {
+	if g == nil {
+		// If the group is nil, then Create is vacuously successful
+		return nil
+	}
+
+	for i := range g.controllers {
+		dir := groupDir(g.basePath, g.controllers[i].Name(), g.path)
+
+		if err := g.osAdapter.MkdirAll(dir, defaultDirectoryPerms); err != nil {
+			return err
+		}
+
+		if err := g.controllers[i].Apply(dir); err != nil {
+			return fmt.Errorf("failed to configure group cgroup %s: %w", dir, err)
+		}
+	}
+
+	return nil
+}
+
+// AddProcess moves the process with the given pid, and all of its threads,
+// into the group's cgroups for all registered controllers.
+func (g *Group) AddProcess(pid int) error {
+	if g == nil {
+		return nil
+	}
+
+	for i := range g.controllers {
+		filename := fmt.Sprintf("%s/%s", groupDir(g.basePath, g.controllers[i].Name(), g.path), ProcsFilename)
+
+		if err := g.osAdapter.WriteFile(filename, []byte(strconv.Itoa(pid)), os.FileMode(0644)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// groupDir returns the directory of the cgroup at the given path within the
+// given controller's hierarchy.
+func groupDir(basePath, controllerName, relPath string) string {
+	if relPath = path.Clean("/" + relPath); relPath == "/" {
+		return fmt.Sprintf("%s/%s", basePath, controllerName)
+	}
+
+	return fmt.Sprintf("%s/%s%s", basePath, controllerName, relPath)
+}
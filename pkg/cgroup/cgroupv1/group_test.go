@@ -0,0 +1,121 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv1_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os/ostest"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1/cgroupv1test"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Group_Create_Success(t *testing.T) {
+	mkdirAllRecorder := ostest.MkdirAllMock{}
+
+	adapter := &os.Adapter{
+		MkdirAllFn: mkdirAllRecorder.MkdirAll,
+	}
+
+	controller := &cgroupv1test.ControllerMock{ControllerName: "nil"}
+	group := cgroupv1.NewGroupDetailed(adapter, cgroupv1.DefaultBasePath, "jobs/user1", controller)
+
+	err := group.Create()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(mkdirAllRecorder.Events))
+	assert.Equal(t,
+		fmt.Sprintf("%s/%s/jobs/user1", cgroupv1.DefaultBasePath, controller.Name()),
+		mkdirAllRecorder.Events[0].Path)
+}
+
+func Test_Group_Create_Failure(t *testing.T) {
+	adapter := &os.Adapter{
+		MkdirAllFn: (&ostest.MkdirAllMock{}).MkdirAll,
+	}
+
+	expectedError := fmt.Errorf("injected error")
+	controller := &cgroupv1test.ControllerMock{
+		ControllerName:   "nil",
+		ApplyReturnValue: expectedError,
+	}
+	group := cgroupv1.NewGroupDetailed(adapter, cgroupv1.DefaultBasePath, "jobs/user1", controller)
+
+	err := group.Create()
+
+	assert.ErrorIs(t, err, expectedError)
+}
+
+func Test_Group_Create_Nil(t *testing.T) {
+	var group *cgroupv1.Group
+
+	assert.Nil(t, group.Create())
+}
+
+func Test_Set_Create_InGroup(t *testing.T) {
+	jobID := uuid.MustParse("0b5183b8-b572-49c7-90c4-fffc775b7d7b")
+	mkdirAllRecorder := ostest.MkdirAllMock{}
+
+	adapter := &os.Adapter{
+		MkdirAllFn: mkdirAllRecorder.MkdirAll,
+	}
+
+	controller := &cgroupv1test.ControllerMock{ControllerName: "nil"}
+	set := cgroupv1.NewSetDetailed(adapter, cgroupv1.DefaultBasePath, "jobs/user1", jobID, controller)
+
+	err := set.Create()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(mkdirAllRecorder.Events))
+	assert.Equal(t,
+		fmt.Sprintf("%s/%s/jobs/user1/%s",
+			cgroupv1.DefaultBasePath,
+			controller.Name(),
+			jobID.String(),
+		),
+		mkdirAllRecorder.Events[0].Path)
+	assert.Equal(t,
+		fmt.Sprintf("%s/%s/jobs/user1/%s/tasks",
+			cgroupv1.DefaultBasePath,
+			controller.Name(),
+			jobID.String(),
+		),
+		set.TaskFiles()[0])
+}
+
+func Test_Group_AddProcess(t *testing.T) {
+	writeRecorder := ostest.WriteFileMock{}
+
+	adapter := &os.Adapter{
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	group := cgroupv1.NewGroupDetailed(adapter, "/cg", "system.slice/jm.service/server",
+		&cgroupv1test.ControllerMock{ControllerName: "cpu"},
+		&cgroupv1test.ControllerMock{ControllerName: "memory"},
+	)
+
+	err := group.AddProcess(1234)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(writeRecorder.Events))
+	assert.Equal(t, "/cg/cpu/system.slice/jm.service/server/"+cgroupv1.ProcsFilename, writeRecorder.Events[0].Name)
+	assert.Equal(t, "/cg/memory/system.slice/jm.service/server/"+cgroupv1.ProcsFilename, writeRecorder.Events[1].Name)
+	assert.Equal(t, []byte("1234"), writeRecorder.Events[1].Data)
+}
@@ -20,13 +20,29 @@ import (
 )
 
 const (
-	MemoryLimitInBytesFilename = "memory.limit_in_bytes"
+	MemoryLimitInBytesFilename      = "memory.limit_in_bytes"
+	MemoryMemswLimitInBytesFilename = "memory.memsw.limit_in_bytes"
+	MemorySoftLimitInBytesFilename  = "memory.soft_limit_in_bytes"
+	MemoryOomControlFilename        = "memory.oom_control"
+	MemoryMaxUsageInBytesFilename   = "memory.max_usage_in_bytes"
 )
 
 // MemoryController configures the MemoryController cgroup controller.
 type MemoryController struct {
 	OsAdapter *os.Adapter
 	Limit     string
+
+	// MemswLimit, if non-empty, limits the sum of memory and swap usage.  It
+	// must not be less than Limit.
+	MemswLimit string
+
+	// SoftLimit, if non-empty, is the usage above which the cgroup's memory
+	// is reclaimed first when the system is under memory pressure.
+	SoftLimit string
+
+	// OomKillDisable, if true, pauses the cgroup's tasks when they reach
+	// their limit instead of invoking the OOM killer.
+	OomKillDisable bool
 }
 
 func (MemoryController) Name() string {
@@ -34,9 +50,30 @@ func (MemoryController) Name() string {
 }
 
 func (m *MemoryController) Apply(path string) error {
-	if m.Limit != "" {
-		filename := fmt.Sprintf("%s/%s", path, MemoryLimitInBytesFilename)
-		if err := m.OsAdapter.WriteFile(filename, []byte(m.Limit), os.FileMode(0644)); err != nil {
+	oomControl := ""
+	if m.OomKillDisable {
+		oomControl = "1"
+	}
+
+	// The memory limit must be written before the memory+swap limit, since
+	// the kernel rejects a memory+swap limit below the memory limit.
+	limits := []struct {
+		filename string
+		value    string
+	}{
+		{MemoryLimitInBytesFilename, m.Limit},
+		{MemoryMemswLimitInBytesFilename, m.MemswLimit},
+		{MemorySoftLimitInBytesFilename, m.SoftLimit},
+		{MemoryOomControlFilename, oomControl},
+	}
+
+	for _, limit := range limits {
+		if limit.value == "" {
+			continue
+		}
+
+		filename := fmt.Sprintf("%s/%s", path, limit.filename)
+		if err := m.OsAdapter.WriteFile(filename, []byte(limit.value), os.FileMode(0644)); err != nil {
 			return err
 		}
 	}
@@ -38,3 +38,31 @@ func Test_memory_Apply(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("%s/%s", path, cgroupv1.MemoryLimitInBytesFilename), writeRecorder.Events[0].Name)
 	assert.Equal(t, []byte(limit), writeRecorder.Events[0].Data)
 }
+
+func Test_memory_Apply_AllLimits(t *testing.T) {
+	path := "/sys/fs/cgroup/jobs/889f7cc2-9935-4773-aaa1-b94478abc923"
+	writeRecorder := ostest.WriteFileMock{}
+	adapter := &os.Adapter{
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	mem := &cgroupv1.MemoryController{
+		OsAdapter:      adapter,
+		Limit:          "1G",
+		MemswLimit:     "2G",
+		SoftLimit:      "512M",
+		OomKillDisable: true,
+	}
+	err := mem.Apply(path)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(writeRecorder.Events))
+	assert.Equal(t, fmt.Sprintf("%s/%s", path, cgroupv1.MemoryLimitInBytesFilename), writeRecorder.Events[0].Name)
+	assert.Equal(t, []byte("1G"), writeRecorder.Events[0].Data)
+	assert.Equal(t, fmt.Sprintf("%s/%s", path, cgroupv1.MemoryMemswLimitInBytesFilename), writeRecorder.Events[1].Name)
+	assert.Equal(t, []byte("2G"), writeRecorder.Events[1].Data)
+	assert.Equal(t, fmt.Sprintf("%s/%s", path, cgroupv1.MemorySoftLimitInBytesFilename), writeRecorder.Events[2].Name)
+	assert.Equal(t, []byte("512M"), writeRecorder.Events[2].Data)
+	assert.Equal(t, fmt.Sprintf("%s/%s", path, cgroupv1.MemoryOomControlFilename), writeRecorder.Events[3].Name)
+	assert.Equal(t, []byte("1"), writeRecorder.Events[3].Data)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv1
+
+import (
+	"fmt"
+	"io"
+	goos "os"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	EventControlFilename = "cgroup.event_control"
+)
+
+// WatchOom calls notify each time the kernel reports an out-of-memory event
+// in the job's memory cgroup, until the returned Closer is closed.  It
+// registers an eventfd for memory.oom_control with cgroup.event_control.
+// The kernel also signals the eventfd when the cgroup is removed, so notify
+// may be called after the job has terminated.
+func (s *Set) WatchOom(notify func()) (io.Closer, error) {
+	if s == nil {
+		return nil, fmt.Errorf("no cgroup for controller memory")
+	}
+
+	path := s.cgroupDir(s.jobID, "memory")
+
+	oomControl, err := s.osAdapter.Open(fmt.Sprintf("%s/%s", path, MemoryOomControlFilename))
+	if err != nil {
+		return nil, err
+	}
+	// The registration does not need the file to remain open
+	defer oomControl.Close()
+
+	efd, err := unix.Eventfd(0, unix.EFD_CLOEXEC|unix.EFD_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create eventfd: %w", err)
+	}
+	events := goos.NewFile(uintptr(efd), "oom-eventfd")
+
+	registration := fmt.Sprintf("%d %d", efd, oomControl.Fd())
+	filename := fmt.Sprintf("%s/%s", path, EventControlFilename)
+
+	if err := s.osAdapter.WriteFile(filename, []byte(registration), os.FileMode(0644)); err != nil {
+		events.Close()
+		return nil, err
+	}
+
+	go func() {
+		// Each read returns the number of events since the previous read
+		counter := make([]byte, 8)
+
+		for {
+			if _, err := events.Read(counter); err != nil {
+				return
+			}
+			notify()
+		}
+	}()
+
+	return events, nil
+}
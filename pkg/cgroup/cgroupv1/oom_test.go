@@ -0,0 +1,104 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv1_test
+
+import (
+	"fmt"
+	goos "os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os/ostest"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1/cgroupv1test"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func Test_Set_WatchOom(t *testing.T) {
+	basePath := t.TempDir()
+	jobID := uuid.MustParse("0b5183b8-b572-49c7-90c4-fffc775b7d7b")
+	path := fmt.Sprintf("%s/memory/jobs/%s", basePath, jobID.String())
+
+	require.Nil(t, goos.MkdirAll(path, 0755))
+	require.Nil(t, goos.WriteFile(path+"/"+cgroupv1.MemoryOomControlFilename, nil, 0644))
+
+	writeRecorder := ostest.WriteFileMock{}
+	adapter := &os.Adapter{
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	controller := &cgroupv1test.ControllerMock{ControllerName: "memory"}
+	set := cgroupv1.NewSetDetailed(adapter, basePath, "jobs", jobID, controller)
+
+	notified := make(chan struct{}, 1)
+	watcher, err := set.WatchOom(func() { notified <- struct{}{} })
+	require.Nil(t, err)
+	defer watcher.Close()
+
+	require.Equal(t, 1, len(writeRecorder.Events))
+	assert.Equal(t, path+"/"+cgroupv1.EventControlFilename, writeRecorder.Events[0].Name)
+
+	// Signal the registered eventfd as the kernel would on an OOM event
+	fields := strings.Fields(string(writeRecorder.Events[0].Data))
+	require.Equal(t, 2, len(fields))
+
+	efd, err := strconv.Atoi(fields[0])
+	require.Nil(t, err)
+
+	_, err = unix.Write(efd, []byte{1, 0, 0, 0, 0, 0, 0, 0})
+	require.Nil(t, err)
+
+	select {
+	case <-notified:
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "notify was not called")
+	}
+}
+
+func Test_Set_WatchOom_OpenFailed(t *testing.T) {
+	jobID := uuid.MustParse("0b5183b8-b572-49c7-90c4-fffc775b7d7b")
+	openErr := fmt.Errorf("injected open error")
+
+	writeRecorder := ostest.WriteFileMock{}
+	adapter := &os.Adapter{
+		OpenFn: func(name string) (*goos.File, error) {
+			return nil, openErr
+		},
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	controller := &cgroupv1test.ControllerMock{ControllerName: "memory"}
+	set := cgroupv1.NewSetDetailed(adapter, t.TempDir(), "jobs", jobID, controller)
+
+	_, err := set.WatchOom(func() {})
+
+	assert.ErrorIs(t, err, openErr)
+	assert.Equal(t, 0, len(writeRecorder.Events))
+}
+
+func Test_Set_WatchOom_NoMemoryCgroup(t *testing.T) {
+	jobID := uuid.MustParse("0b5183b8-b572-49c7-90c4-fffc775b7d7b")
+	set := cgroupv1.NewSetDetailed(nil, t.TempDir(), "", jobID)
+
+	_, err := set.WatchOom(func() {})
+
+	assert.NotNil(t, err)
+}
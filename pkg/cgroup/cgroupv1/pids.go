@@ -0,0 +1,50 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv1
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+)
+
+const (
+	PidsMaxFilename = "pids.max"
+)
+
+// PidsController configures the pids cgroup controller.  Limit is the
+// maximum number of processes and threads that may exist in the cgroup at
+// any one time; attempts to fork or clone beyond it fail with EAGAIN.  A
+// zero Limit leaves the cgroup unlimited.
+type PidsController struct {
+	OsAdapter *os.Adapter
+	Limit     uint64
+}
+
+func (PidsController) Name() string {
+	return "pids"
+}
+
+func (p *PidsController) Apply(path string) error {
+	if p.Limit > 0 {
+		filename := fmt.Sprintf("%s/%s", path, PidsMaxFilename)
+		value := strconv.FormatUint(p.Limit, 10)
+		if err := p.OsAdapter.WriteFile(filename, []byte(value), os.FileMode(0644)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
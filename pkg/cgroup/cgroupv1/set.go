@@ -16,6 +16,8 @@ package cgroupv1
 import (
 	"fmt"
 	"log"
+	goos "os"
+	"path"
 	"strings"
 
 	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
@@ -25,29 +27,39 @@ import (
 
 const (
 	DefaultBasePath                   = "/sys/fs/cgroup"
+	DefaultJobsParent                 = "jobs"
+	ProcsFilename                     = "cgroup.procs"
 	defaultDirectoryPerms os.FileMode = 0755
 )
 
 // Set maintains a collection of 0 or more cgroup controllers that should be
-// created/removed at the same time.
+// created/removed at the same time.  More than one controller may have the
+// same name (e.g., a MemoryController and a memory KnobController); such
+// controllers share a cgroup.
 type Set struct {
 	osAdapter   *os.Adapter
 	basePath    string
+	parent      string
 	jobID       uuid.UUID
 	controllers []Controller
 }
 
-// NewSet creates a new cgroup (v1) set for the given jobID.  This assumes that
+// NewSet creates a new cgroup (v1) set for the given jobID within the given
+// group (see Group) of the default jobs cgroups.  If group is empty, the
+// job's cgroups are created directly in the jobs cgroups.  This assumes that
 // the cgroup filesystem is mounted at /sys/fs/cgroup.
-func NewSet(jobID uuid.UUID, controllers ...Controller) *Set {
-	return NewSetDetailed(nil, DefaultBasePath, jobID, controllers...)
+func NewSet(group string, jobID uuid.UUID, controllers ...Controller) *Set {
+	return NewSetDetailed(nil, DefaultBasePath, path.Join(DefaultJobsParent, group), jobID, controllers...)
 }
 
 // NewSetDetailed creates a new cgroup (v1) set for the given jobID rooted
-// at the given basePath.
+// at the given basePath.  The job's cgroups are created in the parent
+// cgroups at the given path (e.g., "jobs/user1") within each controller's
+// hierarchy.
 func NewSetDetailed(
 	osAdapter *os.Adapter,
 	basePath string,
+	parent string,
 	jobID uuid.UUID,
 	controllers ...Controller,
 ) *Set {
@@ -55,6 +67,7 @@ func NewSetDetailed(
 	return &Set{
 		osAdapter:   osAdapter,
 		basePath:    basePath,
+		parent:      parent,
 		jobID:       jobID,
 		controllers: controllers,
 	}
@@ -87,9 +100,16 @@ func (s *Set) Create() (retErr error) {
 		}
 	}
 
+	removed := make(map[string]bool)
+
 	for i := failPoint; i >= 0; i-- {
 		path := s.cgroupDir(s.jobID, s.controllers[i].Name())
 
+		if removed[path] {
+			continue
+		}
+		removed[path] = true
+
 		if err := s.osAdapter.Remove(path); err != nil {
 			log.Printf("Failed to backout cgroup %s: %v", s.controllers[i].Name(), err)
 			// Intentionally not returning an error here
@@ -100,6 +120,8 @@ func (s *Set) Create() (retErr error) {
 }
 
 // Destroy removes the cgroup v1 directories for all registered controllers.
+// Directories that do not exist, such as those removed by an earlier call
+// that failed part way, are not a failure.
 func (s *Set) Destroy() error {
 	if s == nil {
 		// If the set is nil, then Delete is vacuously successful
@@ -107,11 +129,17 @@ func (s *Set) Destroy() error {
 	}
 
 	var failedCgroups []string
+	removed := make(map[string]bool)
 
 	for i := len(s.controllers) - 1; i >= 0; i-- {
 		path := s.cgroupDir(s.jobID, s.controllers[i].Name())
 
-		if err := s.osAdapter.Remove(path); err != nil {
+		if removed[path] {
+			continue
+		}
+		removed[path] = true
+
+		if err := s.osAdapter.Remove(path); err != nil && !goos.IsNotExist(err) {
 			failedCgroups = append(failedCgroups, path)
 		}
 	}
@@ -131,18 +159,30 @@ func (s *Set) TaskFiles() []string {
 	}
 
 	taskFiles := make([]string, 0, len(s.controllers))
+	seen := make(map[string]bool)
 
 	for i := range s.controllers {
-		taskFiles = append(taskFiles, fmt.Sprintf(
-			"%s/%s/jobs/%s/tasks",
-			s.basePath,
-			s.controllers[i].Name(),
-			s.jobID.String()))
+		taskFile := fmt.Sprintf("%s/tasks", s.cgroupDir(s.jobID, s.controllers[i].Name()))
+
+		if !seen[taskFile] {
+			seen[taskFile] = true
+			taskFiles = append(taskFiles, taskFile)
+		}
 	}
 
 	return taskFiles
 }
 
+// ReadFile returns the content of the file with the given name in the job's
+// cgroup for the given controller.
+func (s *Set) ReadFile(controllerName, filename string) ([]byte, error) {
+	if s == nil {
+		return nil, fmt.Errorf("no cgroup for controller %s", controllerName)
+	}
+
+	return s.osAdapter.ReadFile(fmt.Sprintf("%s/%s", s.cgroupDir(s.jobID, controllerName), filename))
+}
+
 func (s *Set) cgroupDir(jobID uuid.UUID, controllerName string) string {
-	return fmt.Sprintf("%s/%s/jobs/%s", s.basePath, controllerName, jobID.String())
+	return fmt.Sprintf("%s/%s", groupDir(s.basePath, controllerName, s.parent), jobID.String())
 }
@@ -15,10 +15,12 @@ package cgroupv1_test
 
 import (
 	"fmt"
+	goos "os"
 	"testing"
 
 	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
 	"github.com/adalton/teleport-exercise/pkg/adaptation/os/ostest"
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
 	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
 	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1/cgroupv1test"
 
@@ -37,7 +39,7 @@ func Test_Set_Create_Success(t *testing.T) {
 	}
 
 	controller := &cgroupv1test.ControllerMock{ControllerName: "nil"}
-	set := cgroupv1.NewSetDetailed(adapter, cgroupv1.DefaultBasePath, jobID, controller)
+	set := cgroupv1.NewSetDetailed(adapter, cgroupv1.DefaultBasePath, "jobs", jobID, controller)
 
 	err := set.Create()
 
@@ -68,7 +70,7 @@ func Test_Set_Create_Failure(t *testing.T) {
 		ControllerName:   "nil",
 		ApplyReturnValue: expectedError,
 	}
-	set := cgroupv1.NewSetDetailed(adapter, cgroupv1.DefaultBasePath, jobID, controller)
+	set := cgroupv1.NewSetDetailed(adapter, cgroupv1.DefaultBasePath, "jobs", jobID, controller)
 
 	err := set.Create()
 
@@ -92,7 +94,7 @@ func Test_Set_Destroy_Success(t *testing.T) {
 	}
 
 	controller := &cgroupv1test.ControllerMock{ControllerName: "nil"}
-	set := cgroupv1.NewSetDetailed(adapter, cgroupv1.DefaultBasePath, jobID, controller)
+	set := cgroupv1.NewSetDetailed(adapter, cgroupv1.DefaultBasePath, "jobs", jobID, controller)
 
 	err := set.Destroy()
 
@@ -119,7 +121,7 @@ func Test_Set_Destroy_Failure(t *testing.T) {
 	}
 
 	controller := &cgroupv1test.ControllerMock{ControllerName: "nil"}
-	set := cgroupv1.NewSetDetailed(adapter, cgroupv1.DefaultBasePath, jobID, controller)
+	set := cgroupv1.NewSetDetailed(adapter, cgroupv1.DefaultBasePath, "jobs", jobID, controller)
 
 	err := set.Destroy()
 
@@ -127,11 +129,35 @@ func Test_Set_Destroy_Failure(t *testing.T) {
 	assert.Equal(t, 1, len(removeRecorder.Events))
 }
 
+func Test_Set_Destroy_Twice(t *testing.T) {
+	jobID := uuid.MustParse("0b5183b8-b572-49c7-90c4-fffc775b7d7b")
+	removed := make(map[string]bool)
+
+	adapter := &os.Adapter{
+		RemoveFn: func(path string) error {
+			if removed[path] {
+				return &goos.PathError{Op: "remove", Path: path, Err: goos.ErrNotExist}
+			}
+			removed[path] = true
+			return nil
+		},
+	}
+
+	controller := &cgroupv1test.ControllerMock{ControllerName: "nil"}
+	set := cgroupv1.NewSetDetailed(adapter, cgroupv1.DefaultBasePath, "jobs", jobID, controller)
+
+	assert.Nil(t, set.Destroy())
+
+	// The cgroups no longer exist, which is not a failure
+	assert.Nil(t, set.Destroy())
+	assert.Equal(t, 1, len(removed))
+}
+
 func Test_Set_TaskFiles(t *testing.T) {
 	jobID := uuid.MustParse("0b5183b8-b572-49c7-90c4-fffc775b7d7b")
 
 	controller := &cgroupv1test.ControllerMock{ControllerName: "nil"}
-	set := cgroupv1.NewSet(jobID, controller)
+	set := cgroupv1.NewSet("", jobID, controller)
 
 	taskFiles := set.TaskFiles()
 
@@ -144,3 +170,49 @@ func Test_Set_TaskFiles(t *testing.T) {
 		),
 		taskFiles[0])
 }
+
+func Test_Set_ReadFile(t *testing.T) {
+	const basePath = "/sys/fs/cgroup"
+	jobID := uuid.MustParse("0b5183b8-b572-49c7-90c4-fffc775b7d7b")
+
+	readFileMock := &ostest.ReadFileMock{
+		Files: map[string]string{
+			fmt.Sprintf("%s/pids/jobs/%s/pids.events", basePath, jobID.String()): "max 3\n",
+		},
+	}
+	adapter := &os.Adapter{
+		ReadFileFn: readFileMock.ReadFile,
+	}
+
+	controller := &cgroupv1test.ControllerMock{ControllerName: "pids"}
+	set := cgroupv1.NewSetDetailed(adapter, basePath, "jobs", jobID, controller)
+
+	content, err := set.ReadFile("pids", "pids.events")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("max 3\n"), content)
+}
+
+func Test_Set_SharedCgroup(t *testing.T) {
+	jobID := uuid.MustParse("0b5183b8-b572-49c7-90c4-fffc775b7d7b")
+	removeRecorder := ostest.RemoveMock{}
+
+	adapter := &os.Adapter{
+		RemoveFn: removeRecorder.Remove,
+	}
+
+	set := cgroupv1.NewSetDetailed(adapter, cgroupv1.DefaultBasePath, "jobs", jobID,
+		&cgroupv1.MemoryController{},
+		&cgroup.KnobController{Controller: "memory"},
+	)
+
+	assert.Equal(t, 1, len(set.TaskFiles()))
+
+	err := set.Destroy()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(removeRecorder.Events))
+	assert.Equal(t,
+		fmt.Sprintf("%s/memory/jobs/%s", cgroupv1.DefaultBasePath, jobID.String()),
+		removeRecorder.Events[0].Path)
+}
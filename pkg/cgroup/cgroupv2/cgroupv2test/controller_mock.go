@@ -0,0 +1,30 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv2test
+
+// ControllerMock provides a mock implementation of the cgroupv2.Controller interface
+// for use in unit test.  This implementation does not modify any actual
+// cgroup.
+type ControllerMock struct {
+	ControllerName   string
+	ApplyReturnValue error
+}
+
+func (d *ControllerMock) Name() string {
+	return d.ControllerName
+}
+
+func (d *ControllerMock) Apply(string) error {
+	return d.ApplyReturnValue
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv2
+
+import (
+	"fmt"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+)
+
+const (
+	CpuMaxFilename    = "cpu.max"
+	CpuWeightFilename = "cpu.weight"
+
+	defaultPeriodUs = 100000
+)
+
+// CpuController implements cgroup v2 control using CFS Bandwidth Control.
+// See Documentation/admin-guide/cgroup-v2.rst in the kernel source tree for
+// additional information.
+//
+// This implementation exposes that functionality in terms of how much of the
+// available CPU resources a collection of processes can use (0.5 = half a CPU,
+// 1.0 = 1 CPU, 1.5 = 1 and a half CPUs, ...).
+// The period is PeriodUs (defaultPeriodUs if zero) and the quota is
+// cpus*period.  If Cpus is zero, no quota is set.
+//
+// Weight, if non-zero, sets the relative weight of the cgroup when CPUs are
+// contended, in the range [1, 10000] (the kernel's default is 100).  Unlike
+// the quota, the weight does not prevent the cgroup from using idle CPU
+// capacity.
+type CpuController struct {
+	OsAdapter *os.Adapter
+	Cpus      float64
+	PeriodUs  uint64
+	Weight    uint64
+}
+
+func (CpuController) Name() string {
+	return "cpu"
+}
+
+func (c *CpuController) Apply(path string) error {
+	if c.Cpus != 0 {
+		periodUs := c.PeriodUs
+		if periodUs == 0 {
+			periodUs = defaultPeriodUs
+		}
+
+		filename := fmt.Sprintf("%s/%s", path, CpuMaxFilename)
+		value := fmt.Sprintf("%d %d", int(c.Cpus*float64(periodUs)), periodUs)
+
+		if err := c.OsAdapter.WriteFile(filename, []byte(value), os.FileMode(0644)); err != nil {
+			return err
+		}
+	}
+
+	if c.Weight != 0 {
+		filename := fmt.Sprintf("%s/%s", path, CpuWeightFilename)
+		weight := fmt.Sprintf("%d", c.Weight)
+
+		if err := c.OsAdapter.WriteFile(filename, []byte(weight), os.FileMode(0644)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv2
+
+import (
+	"fmt"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+)
+
+const (
+	CpusetCpusFilename = "cpuset.cpus"
+	CpusetMemsFilename = "cpuset.mems"
+)
+
+// CpusetController configures the cpuset cgroup controller.  Cpus and Mems
+// are lists of CPUs and memory nodes in the kernel's list format (e.g.,
+// "0-3,8").  An empty list leaves the corresponding file empty, in which case
+// the cgroup uses the CPUs or memory nodes of its parent.
+type CpusetController struct {
+	OsAdapter *os.Adapter
+	Cpus      string
+	Mems      string
+}
+
+func (CpusetController) Name() string {
+	return "cpuset"
+}
+
+func (c *CpusetController) Apply(path string) error {
+	if c.Cpus != "" {
+		filename := fmt.Sprintf("%s/%s", path, CpusetCpusFilename)
+		if err := c.OsAdapter.WriteFile(filename, []byte(c.Cpus), os.FileMode(0644)); err != nil {
+			return err
+		}
+	}
+
+	if c.Mems != "" {
+		filename := fmt.Sprintf("%s/%s", path, CpusetMemsFilename)
+		if err := c.OsAdapter.WriteFile(filename, []byte(c.Mems), os.FileMode(0644)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cgroupv2 provides a simple abstraction over the cgroup v2 (unified
+// hierarchy) interface.  It mirrors the abstractions in the cgroupv1 package
+// so that hosts that boot with only the unified hierarchy mounted can still
+// run jobs.
+//
+// Unlike v1, there is a single hierarchy in which all controllers live; each
+// job gets one directory and each controller writes its own files into that
+// directory.
+package cgroupv2
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv2
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+)
+
+const (
+	FreezeFilename = "cgroup.freeze"
+	EventsFilename = "cgroup.events"
+)
+
+// Freeze freezes the processes in the job's cgroup.  Every non-root cgroup
+// in the unified hierarchy has a freezer; no controller need be enabled.
+// Freezing is asynchronous; see Frozen.
+func (s *Set) Freeze() error {
+	return s.writeFreeze("1")
+}
+
+// Thaw thaws the processes in the job's cgroup.
+func (s *Set) Thaw() error {
+	return s.writeFreeze("0")
+}
+
+// Frozen returns true if all of the processes in the job's cgroup are
+// frozen, false otherwise.
+func (s *Set) Frozen() (bool, error) {
+	content, err := s.ReadFile("freezer", EventsFilename)
+	if err != nil {
+		return false, err
+	}
+
+	return parseEvent(content, "frozen") == "1", nil
+}
+
+// Pids returns the processes in the job's cgroup.
+func (s *Set) Pids() ([]int, error) {
+	content, err := s.ReadFile("pids", ProcsFilename)
+	if err != nil {
+		return nil, err
+	}
+
+	var pids []int
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		pid, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+		if err == nil {
+			pids = append(pids, pid)
+		}
+	}
+
+	return pids, nil
+}
+
+func (s *Set) writeFreeze(value string) error {
+	if s == nil {
+		return fmt.Errorf("no cgroup for controller freezer")
+	}
+
+	filename := fmt.Sprintf("%s/%s", s.cgroupDir(), FreezeFilename)
+
+	return s.osAdapter.WriteFile(filename, []byte(value), os.FileMode(0644))
+}
+
+// parseEvent returns the value of the named entry of a flat-keyed file such
+// as cgroup.events, or "" if there is no such entry.
+func parseEvent(content []byte, name string) string {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == name {
+			return fields[1]
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv2_test
+
+import (
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os/ostest"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const freezerJobID = "0b5183b8-b572-49c7-90c4-fffc775b7d7b"
+
+func Test_Set_Freeze(t *testing.T) {
+	dir := "/cg/jobs/" + freezerJobID
+	writeRecorder := ostest.WriteFileMock{}
+	readFileMock := &ostest.ReadFileMock{
+		Files: map[string]string{
+			dir + "/" + cgroupv2.EventsFilename: "populated 1\nfrozen 0\n",
+		},
+	}
+	adapter := &os.Adapter{
+		WriteFileFn: writeRecorder.WriteFile,
+		ReadFileFn:  readFileMock.ReadFile,
+	}
+
+	// Every cgroup has a freezer; no controller is required
+	set := cgroupv2.NewSetDetailed(adapter, "/cg", "jobs", uuid.MustParse(freezerJobID))
+
+	assert.Nil(t, set.Freeze())
+	frozen, err := set.Frozen()
+	assert.Nil(t, err)
+	assert.False(t, frozen)
+
+	readFileMock.Files[dir+"/"+cgroupv2.EventsFilename] = "populated 1\nfrozen 1\n"
+	frozen, err = set.Frozen()
+	assert.Nil(t, err)
+	assert.True(t, frozen)
+
+	assert.Nil(t, set.Thaw())
+
+	assert.Equal(t, 2, len(writeRecorder.Events))
+	assert.Equal(t, dir+"/"+cgroupv2.FreezeFilename, writeRecorder.Events[0].Name)
+	assert.Equal(t, []byte("1"), writeRecorder.Events[0].Data)
+	assert.Equal(t, []byte("0"), writeRecorder.Events[1].Data)
+}
+
+func Test_Set_Pids(t *testing.T) {
+	readFileMock := &ostest.ReadFileMock{
+		Files: map[string]string{
+			"/cg/jobs/" + freezerJobID + "/" + cgroupv2.ProcsFilename: "10\n11\n",
+		},
+	}
+	adapter := &os.Adapter{
+		ReadFileFn: readFileMock.ReadFile,
+	}
+
+	set := cgroupv2.NewSetDetailed(adapter, "/cg", "jobs", uuid.MustParse(freezerJobID))
+
+	pids, err := set.Pids()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []int{10, 11}, pids)
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv2
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+)
+
+// Group maintains a cgroup that a collection of processes share, such as all
+// of the jobs of one user, or the server itself.  The group's controllers
+// limit the aggregate resource usage of all of the processes in the group,
+// including those in its descendant cgroups.  Jobs' own cgroups are created
+// in a group's cgroup by a Set whose parent is the group's path.
+type Group struct {
+	osAdapter   *os.Adapter
+	basePath    string
+	path        string
+	controllers []Controller
+}
+
+// NewGroup creates a new cgroup (v2) group with the given name within the
+// default jobs cgroup.  This assumes that the unified cgroup filesystem is
+// mounted at /sys/fs/cgroup.
+func NewGroup(name string, controllers ...Controller) *Group {
+	return NewGroupDetailed(nil, DefaultBasePath, path.Join(DefaultJobsParent, name), controllers...)
+}
+
+// NewGroupDetailed creates a new cgroup (v2) group for the cgroup at the
+// given path relative to the given basePath (e.g., "jobs/user1").
+func NewGroupDetailed(
+	osAdapter *os.Adapter,
+	basePath string,
+	path string,
+	controllers ...Controller,
+) *Group {
+
+	return &Group{
+		osAdapter:   osAdapter,
+		basePath:    basePath,
+		path:        path,
+		controllers: controllers,
+	}
+}
+
+// Create creates the group's cgroup, if it does not already exist, enables
+// the registered controllers for it, and applies their configuration.  Create
+// may be called each time a job joins the group; the group's cgroup is not
+// removed when its jobs terminate.
+func (g *Group) Create() error {
+	if g == nil {
+		// If the group is nil, then Create is vacuously successful
+		return nil
+	}
+
+	dirs := ancestorDirs(g.basePath, g.path)
+
+	for i, dir := range dirs {
+		if err := g.osAdapter.MkdirAll(dir, defaultDirectoryPerms); err != nil {
+			return err
+		}
+
+		// The group's controllers must be enabled in each of its ancestors
+		// for their interface files to exist in the group's cgroup.
+		if i < len(dirs)-1 {
+			if err := enableControllers(g.osAdapter, dir, g.controllers); err != nil {
+				return err
+			}
+		}
+	}
+
+	dir := groupDir(g.basePath, g.path)
+
+	for i := range g.controllers {
+		if err := g.controllers[i].Apply(dir); err != nil {
+			return fmt.Errorf("failed to configure group cgroup %s: %w", dir, err)
+		}
+	}
+
+	return nil
+}
+
+// AddProcess moves the process with the given pid, and all of its threads,
+// into the group's cgroup.
+func (g *Group) AddProcess(pid int) error {
+	if g == nil {
+		return nil
+	}
+
+	filename := fmt.Sprintf("%s/%s", groupDir(g.basePath, g.path), ProcsFilename)
+
+	return g.osAdapter.WriteFile(filename, []byte(strconv.Itoa(pid)), os.FileMode(0644))
+}
+
+// groupDir returns the directory of the cgroup at the given path relative to
+// basePath.
+func groupDir(basePath, relPath string) string {
+	if relPath = path.Clean("/" + relPath); relPath == "/" {
+		return basePath
+	}
+
+	return basePath + relPath
+}
+
+// ancestorDirs returns the directories of the cgroup at the given path
+// relative to basePath and of each of its ancestors, from the root of the
+// hierarchy down.
+func ancestorDirs(basePath, relPath string) []string {
+	dirs := []string{basePath}
+	dir := basePath
+
+	for _, element := range strings.Split(strings.Trim(path.Clean("/"+relPath), "/"), "/") {
+		if element != "" {
+			dir = dir + "/" + element
+			dirs = append(dirs, dir)
+		}
+	}
+
+	return dirs
+}
+
+// enableControllers enables each of the given controllers for the children of
+// the cgroup at the given path.
+func enableControllers(osAdapter *os.Adapter, path string, controllers []Controller) error {
+	filename := fmt.Sprintf("%s/%s", path, SubtreeControlFilename)
+
+	for i := range controllers {
+		value := []byte("+" + controllers[i].Name())
+
+		if err := osAdapter.WriteFile(filename, value, os.FileMode(0644)); err != nil {
+			return fmt.Errorf("failed to enable controller %s in %s: %w",
+				controllers[i].Name(), path, err)
+		}
+	}
+
+	return nil
+}
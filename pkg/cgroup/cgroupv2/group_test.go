@@ -0,0 +1,159 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv2_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os/ostest"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2/cgroupv2test"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Group_Create_Success(t *testing.T) {
+	mkdirAllRecorder := ostest.MkdirAllMock{}
+	writeRecorder := ostest.WriteFileMock{}
+
+	adapter := &os.Adapter{
+		MkdirAllFn:  mkdirAllRecorder.MkdirAll,
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	controller := &cgroupv2test.ControllerMock{ControllerName: "memory"}
+	group := cgroupv2.NewGroupDetailed(adapter, cgroupv2.DefaultBasePath, "jobs/user1", controller)
+
+	err := group.Create()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(mkdirAllRecorder.Events))
+	assert.Equal(t,
+		fmt.Sprintf("%s/jobs/user1", cgroupv2.DefaultBasePath),
+		mkdirAllRecorder.Events[2].Path)
+
+	// The controller is enabled in the ancestors, but not in the group itself
+	assert.Equal(t, 2, len(writeRecorder.Events))
+	assert.Equal(t,
+		fmt.Sprintf("%s/%s", cgroupv2.DefaultBasePath, cgroupv2.SubtreeControlFilename),
+		writeRecorder.Events[0].Name)
+	assert.Equal(t,
+		fmt.Sprintf("%s/jobs/%s", cgroupv2.DefaultBasePath, cgroupv2.SubtreeControlFilename),
+		writeRecorder.Events[1].Name)
+	assert.Equal(t, []byte("+memory"), writeRecorder.Events[1].Data)
+}
+
+func Test_Group_Create_Failure(t *testing.T) {
+	adapter := &os.Adapter{
+		MkdirAllFn:  (&ostest.MkdirAllMock{}).MkdirAll,
+		WriteFileFn: (&ostest.WriteFileMock{}).WriteFile,
+	}
+
+	expectedError := fmt.Errorf("injected error")
+	controller := &cgroupv2test.ControllerMock{
+		ControllerName:   "memory",
+		ApplyReturnValue: expectedError,
+	}
+	group := cgroupv2.NewGroupDetailed(adapter, cgroupv2.DefaultBasePath, "jobs/user1", controller)
+
+	err := group.Create()
+
+	assert.ErrorIs(t, err, expectedError)
+}
+
+func Test_Group_Create_Nil(t *testing.T) {
+	var group *cgroupv2.Group
+
+	assert.Nil(t, group.Create())
+}
+
+func Test_Set_Create_InGroup(t *testing.T) {
+	jobID := uuid.MustParse("0b5183b8-b572-49c7-90c4-fffc775b7d7b")
+	mkdirAllRecorder := ostest.MkdirAllMock{}
+	writeRecorder := ostest.WriteFileMock{}
+
+	adapter := &os.Adapter{
+		MkdirAllFn:  mkdirAllRecorder.MkdirAll,
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	controller := &cgroupv2test.ControllerMock{ControllerName: "pids"}
+	set := cgroupv2.NewSetDetailed(adapter, cgroupv2.DefaultBasePath, "jobs/user1", jobID, controller)
+
+	err := set.Create()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(mkdirAllRecorder.Events))
+	assert.Equal(t,
+		fmt.Sprintf("%s/jobs/user1/%s", cgroupv2.DefaultBasePath, jobID.String()),
+		mkdirAllRecorder.Events[3].Path)
+
+	// The job's controllers must be enabled in the group as well
+	assert.Equal(t, 3, len(writeRecorder.Events))
+	assert.Equal(t,
+		fmt.Sprintf("%s/jobs/user1/%s", cgroupv2.DefaultBasePath, cgroupv2.SubtreeControlFilename),
+		writeRecorder.Events[2].Name)
+	assert.Equal(t, []byte("+pids"), writeRecorder.Events[2].Data)
+}
+
+func Test_Group_Create_Nested(t *testing.T) {
+	mkdirAllRecorder := ostest.MkdirAllMock{}
+	writeRecorder := ostest.WriteFileMock{}
+
+	adapter := &os.Adapter{
+		MkdirAllFn:  mkdirAllRecorder.MkdirAll,
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	controller := &cgroupv2test.ControllerMock{ControllerName: "memory"}
+	group := cgroupv2.NewGroupDetailed(adapter, "/cg", "system.slice/jm.service/jobs", controller)
+
+	err := group.Create()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"/cg",
+		"/cg/system.slice",
+		"/cg/system.slice/jm.service",
+		"/cg/system.slice/jm.service/jobs",
+	}, []string{
+		mkdirAllRecorder.Events[0].Path,
+		mkdirAllRecorder.Events[1].Path,
+		mkdirAllRecorder.Events[2].Path,
+		mkdirAllRecorder.Events[3].Path,
+	})
+	assert.Equal(t, 3, len(writeRecorder.Events))
+	assert.Equal(t, "/cg/system.slice/jm.service/"+cgroupv2.SubtreeControlFilename, writeRecorder.Events[2].Name)
+}
+
+func Test_Group_AddProcess(t *testing.T) {
+	writeRecorder := ostest.WriteFileMock{}
+
+	adapter := &os.Adapter{
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	group := cgroupv2.NewGroupDetailed(adapter, "/cg", "jobmanager",
+		&cgroupv2test.ControllerMock{ControllerName: "memory"})
+
+	err := group.AddProcess(1234)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(writeRecorder.Events))
+	assert.Equal(t, "/cg/jobmanager/"+cgroupv2.ProcsFilename, writeRecorder.Events[0].Name)
+	assert.Equal(t, []byte("1234"), writeRecorder.Events[0].Data)
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+)
+
+const (
+	IoMaxFilename = "io.max"
+)
+
+// IOController implements the io cgroup controller.
+// Entries in ReadBpsDevices, WriteBpsDevices, ReadIopsDevices and
+// WriteIopsDevices use the same format as the cgroup v1 blkio controller:
+// "<major>:<minor> <value>".  Apply translates them into the
+// "<major>:<minor> <key>=<value>" lines that io.max expects, where key is
+// rbps, wbps, riops or wiops, respectively.
+type IOController struct {
+	OsAdapter        *os.Adapter
+	ReadBpsDevices   []string
+	WriteBpsDevices  []string
+	ReadIopsDevices  []string
+	WriteIopsDevices []string
+}
+
+func (IOController) Name() string {
+	return "io"
+}
+
+// Apply applies this cgroup controller configuration to the cgroup at the
+// given path.
+func (c *IOController) Apply(path string) error {
+	filename := fmt.Sprintf("%s/%s", path, IoMaxFilename)
+
+	limits := []struct {
+		key          string
+		deviceLimits []string
+	}{
+		{"rbps", c.ReadBpsDevices},
+		{"wbps", c.WriteBpsDevices},
+		{"riops", c.ReadIopsDevices},
+		{"wiops", c.WriteIopsDevices},
+	}
+
+	for _, limit := range limits {
+		for _, deviceLimit := range limit.deviceLimits {
+			if err := c.writeLimit(filename, limit.key, deviceLimit); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeLimit converts the given "<major>:<minor> <value>" deviceLimit into
+// an io.max entry for the given key and writes it to filename.  Each write to
+// io.max updates only the keys that it names, so limits can be written one at
+// a time.
+func (c *IOController) writeLimit(filename, key, deviceLimit string) error {
+	fields := strings.Fields(deviceLimit)
+	if len(fields) != 2 {
+		return fmt.Errorf("malformed device limit '%s'", deviceLimit)
+	}
+
+	value := fmt.Sprintf("%s %s=%s", fields[0], key, fields[1])
+
+	return c.OsAdapter.WriteFile(filename, []byte(value), os.FileMode(0644))
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv2_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os/ostest"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_io_Apply(t *testing.T) {
+	path := "/sys/fs/cgroup/jobs/889f7cc2-9935-4773-aaa1-b94478abc923"
+	writeRecorder := ostest.WriteFileMock{}
+	adapter := &os.Adapter{
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	io := cgroupv2.IOController{
+		OsAdapter:       adapter,
+		ReadBpsDevices:  []string{"1:2 1048576"},
+		WriteBpsDevices: []string{"1:3 2097152"},
+	}
+
+	err := io.Apply(path)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(writeRecorder.Events))
+	assert.Equal(t, fmt.Sprintf("%s/%s", path, cgroupv2.IoMaxFilename), writeRecorder.Events[0].Name)
+	assert.Equal(t, []byte("1:2 rbps=1048576"), writeRecorder.Events[0].Data)
+
+	assert.Equal(t, fmt.Sprintf("%s/%s", path, cgroupv2.IoMaxFilename), writeRecorder.Events[1].Name)
+	assert.Equal(t, []byte("1:3 wbps=2097152"), writeRecorder.Events[1].Data)
+}
+
+func Test_io_Apply_MultipleDevices(t *testing.T) {
+	path := "/sys/fs/cgroup/jobs/889f7cc2-9935-4773-aaa1-b94478abc923"
+	writeRecorder := ostest.WriteFileMock{}
+	adapter := &os.Adapter{
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	io := cgroupv2.IOController{
+		OsAdapter:       adapter,
+		ReadBpsDevices:  []string{"8:0 1048576", "259:0 2097152"},
+		WriteBpsDevices: []string{"259:0 4194304"},
+	}
+
+	err := io.Apply(path)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(writeRecorder.Events))
+	assert.Equal(t, []byte("8:0 rbps=1048576"), writeRecorder.Events[0].Data)
+	assert.Equal(t, []byte("259:0 rbps=2097152"), writeRecorder.Events[1].Data)
+	assert.Equal(t, []byte("259:0 wbps=4194304"), writeRecorder.Events[2].Data)
+}
+
+func Test_io_Apply_Iops(t *testing.T) {
+	path := "/sys/fs/cgroup/jobs/889f7cc2-9935-4773-aaa1-b94478abc923"
+	writeRecorder := ostest.WriteFileMock{}
+	adapter := &os.Adapter{
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	io := cgroupv2.IOController{
+		OsAdapter:        adapter,
+		ReadIopsDevices:  []string{"8:0 100"},
+		WriteIopsDevices: []string{"8:0 50"},
+	}
+
+	err := io.Apply(path)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(writeRecorder.Events))
+	assert.Equal(t, []byte("8:0 riops=100"), writeRecorder.Events[0].Data)
+	assert.Equal(t, []byte("8:0 wiops=50"), writeRecorder.Events[1].Data)
+}
+
+func Test_io_Apply_MalformedLimit(t *testing.T) {
+	path := "/sys/fs/cgroup/jobs/889f7cc2-9935-4773-aaa1-b94478abc923"
+	writeRecorder := ostest.WriteFileMock{}
+	adapter := &os.Adapter{
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	io := cgroupv2.IOController{
+		OsAdapter:      adapter,
+		ReadBpsDevices: []string{"1:2"},
+	}
+
+	err := io.Apply(path)
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, len(writeRecorder.Events))
+}
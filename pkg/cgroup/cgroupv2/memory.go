@@ -0,0 +1,86 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv2
+
+import (
+	"fmt"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+)
+
+const (
+	MemoryMaxFilename      = "memory.max"
+	MemoryHighFilename     = "memory.high"
+	MemoryLowFilename      = "memory.low"
+	MemorySwapMaxFilename  = "memory.swap.max"
+	MemoryOomGroupFilename = "memory.oom.group"
+	MemoryPeakFilename     = "memory.peak"
+)
+
+// MemoryController configures the memory cgroup controller.
+type MemoryController struct {
+	OsAdapter *os.Adapter
+	Limit     string
+
+	// High, if non-empty, is the usage above which the cgroup's tasks are
+	// throttled and put under heavy reclaim pressure.
+	High string
+
+	// Low, if non-empty, is the usage below which the cgroup's memory is
+	// protected from reclaim unless no unprotected memory is available.
+	Low string
+
+	// SwapLimit, if non-empty, limits the cgroup's swap usage.  Unlike
+	// cgroup v1, the limit excludes memory usage.
+	SwapLimit string
+
+	// OomGroup, if true, causes the OOM killer to kill all of the cgroup's
+	// tasks together rather than a single task.
+	OomGroup bool
+}
+
+func (MemoryController) Name() string {
+	return "memory"
+}
+
+func (m *MemoryController) Apply(path string) error {
+	oomGroup := ""
+	if m.OomGroup {
+		oomGroup = "1"
+	}
+
+	limits := []struct {
+		filename string
+		value    string
+	}{
+		{MemoryMaxFilename, m.Limit},
+		{MemoryHighFilename, m.High},
+		{MemoryLowFilename, m.Low},
+		{MemorySwapMaxFilename, m.SwapLimit},
+		{MemoryOomGroupFilename, oomGroup},
+	}
+
+	for _, limit := range limits {
+		if limit.value == "" {
+			continue
+		}
+
+		filename := fmt.Sprintf("%s/%s", path, limit.filename)
+		if err := m.OsAdapter.WriteFile(filename, []byte(limit.value), os.FileMode(0644)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv2
+
+import (
+	"fmt"
+	"io"
+	goos "os"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	MemoryEventsFilename = "memory.events"
+)
+
+// WatchOom calls notify each time the job's memory.events file changes,
+// which includes each out-of-memory event in the job's cgroup, until the
+// returned Closer is closed.  The file is watched with inotify; callers
+// should read memory.events to determine which event occurred.
+func (s *Set) WatchOom(notify func()) (io.Closer, error) {
+	if s == nil {
+		return nil, fmt.Errorf("no cgroup for controller memory")
+	}
+
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create inotify instance: %w", err)
+	}
+	events := goos.NewFile(uintptr(fd), "oom-inotify")
+
+	filename := fmt.Sprintf("%s/%s", s.cgroupDir(), MemoryEventsFilename)
+
+	if _, err := unix.InotifyAddWatch(fd, filename, unix.IN_MODIFY); err != nil {
+		events.Close()
+		return nil, fmt.Errorf("failed to watch %s: %w", filename, err)
+	}
+
+	go func() {
+		// Large enough for several events; events for a watched file
+		// carry no name.
+		buffer := make([]byte, 4096)
+
+		for {
+			if _, err := events.Read(buffer); err != nil {
+				return
+			}
+			notify()
+		}
+	}()
+
+	return events, nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv2_test
+
+import (
+	"fmt"
+	goos "os"
+	"testing"
+	"time"
+
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2/cgroupv2test"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Set_WatchOom(t *testing.T) {
+	basePath := t.TempDir()
+	jobID := uuid.MustParse("0b5183b8-b572-49c7-90c4-fffc775b7d7b")
+	path := fmt.Sprintf("%s/jobs/%s", basePath, jobID.String())
+	filename := path + "/" + cgroupv2.MemoryEventsFilename
+
+	require.Nil(t, goos.MkdirAll(path, 0755))
+	require.Nil(t, goos.WriteFile(filename, []byte("oom_kill 0\n"), 0644))
+
+	controller := &cgroupv2test.ControllerMock{ControllerName: "memory"}
+	set := cgroupv2.NewSetDetailed(nil, basePath, "jobs", jobID, controller)
+
+	notified := make(chan struct{}, 1)
+	watcher, err := set.WatchOom(func() { notified <- struct{}{} })
+	require.Nil(t, err)
+	defer watcher.Close()
+
+	// Modify memory.events as the kernel would on an OOM event
+	require.Nil(t, goos.WriteFile(filename, []byte("oom_kill 1\n"), 0644))
+
+	select {
+	case <-notified:
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "notify was not called")
+	}
+}
+
+func Test_Set_WatchOom_NoMemoryEvents(t *testing.T) {
+	jobID := uuid.MustParse("0b5183b8-b572-49c7-90c4-fffc775b7d7b")
+	set := cgroupv2.NewSetDetailed(nil, t.TempDir(), "", jobID)
+
+	_, err := set.WatchOom(func() {})
+
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv2
+
+import (
+	"fmt"
+	"log"
+	goos "os"
+	"path"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultBasePath                    = "/sys/fs/cgroup"
+	DefaultJobsParent                  = "jobs"
+	SubtreeControlFilename             = "cgroup.subtree_control"
+	ProcsFilename                      = "cgroup.procs"
+	defaultDirectoryPerms  os.FileMode = 0755
+)
+
+// Set maintains a collection of 0 or more cgroup controllers that should be
+// enabled for a single job cgroup in the unified hierarchy.
+type Set struct {
+	osAdapter   *os.Adapter
+	basePath    string
+	parent      string
+	jobID       uuid.UUID
+	controllers []Controller
+}
+
+// NewSet creates a new cgroup (v2) set for the given jobID within the given
+// group (see Group) of the default jobs cgroup.  If group is empty, the job's
+// cgroup is created directly in the jobs cgroup.  This assumes that the
+// unified cgroup filesystem is mounted at /sys/fs/cgroup.
+func NewSet(group string, jobID uuid.UUID, controllers ...Controller) *Set {
+	return NewSetDetailed(nil, DefaultBasePath, path.Join(DefaultJobsParent, group), jobID, controllers...)
+}
+
+// NewSetDetailed creates a new cgroup (v2) set for the given jobID rooted
+// at the given basePath.  The job's cgroup is created in the parent cgroup
+// at the given path relative to basePath (e.g., "jobs/user1").
+func NewSetDetailed(
+	osAdapter *os.Adapter,
+	basePath string,
+	parent string,
+	jobID uuid.UUID,
+	controllers ...Controller,
+) *Set {
+
+	return &Set{
+		osAdapter:   osAdapter,
+		basePath:    basePath,
+		parent:      parent,
+		jobID:       jobID,
+		controllers: controllers,
+	}
+}
+
+// Create creates the cgroup v2 directory for the job, enables all registered
+// controllers for it, and applies their configuration.
+func (s *Set) Create() error {
+	if s == nil {
+		// If the set is nil, then Create is vacuously successful
+		return nil
+	}
+
+	// In the unified hierarchy, a controller is available in a cgroup only
+	// if it is enabled in the subtree_control of every ancestor.
+	for _, dir := range ancestorDirs(s.basePath, s.parent) {
+		if err := s.osAdapter.MkdirAll(dir, defaultDirectoryPerms); err != nil {
+			return err
+		}
+
+		if err := s.enableControllers(dir); err != nil {
+			return err
+		}
+	}
+
+	path := s.cgroupDir()
+
+	if err := s.osAdapter.MkdirAll(path, defaultDirectoryPerms); err != nil {
+		return err
+	}
+
+	for i := range s.controllers {
+		// Apply the supplied configuration to the newly-created cgroup
+		if err := s.controllers[i].Apply(path); err != nil {
+			if rmErr := s.osAdapter.Remove(path); rmErr != nil {
+				log.Printf("Failed to backout cgroup %s: %v", path, rmErr)
+				// Intentionally not returning rmErr here
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Destroy removes the cgroup v2 directory for the job.  If the directory does
+// not exist, such as after an earlier call succeeded, it does nothing.
+func (s *Set) Destroy() error {
+	if s == nil {
+		// If the set is nil, then Delete is vacuously successful
+		return nil
+	}
+
+	path := s.cgroupDir()
+
+	if err := s.osAdapter.Remove(path); err != nil && !goos.IsNotExist(err) {
+		return fmt.Errorf("failed to destroy cgroup: %s", path)
+	}
+
+	return nil
+}
+
+// TaskFiles returns a list containing the 'cgroup.procs' file of the job's
+// cgroup.  Writing a PID to that file moves the process into the cgroup.
+func (s *Set) TaskFiles() []string {
+	if s == nil {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("%s/%s", s.cgroupDir(), ProcsFilename)}
+}
+
+// ReadFile returns the content of the file with the given name in the job's
+// cgroup.  All controllers share a single cgroup in the unified hierarchy, so
+// controllerName is used only to report errors.
+func (s *Set) ReadFile(controllerName, filename string) ([]byte, error) {
+	if s == nil {
+		return nil, fmt.Errorf("no cgroup for controller %s", controllerName)
+	}
+
+	return s.osAdapter.ReadFile(fmt.Sprintf("%s/%s", s.cgroupDir(), filename))
+}
+
+// enableControllers enables each registered controller for the children of
+// the cgroup at the given path.
+func (s *Set) enableControllers(path string) error {
+	return enableControllers(s.osAdapter, path, s.controllers)
+}
+
+func (s *Set) cgroupDir() string {
+	return fmt.Sprintf("%s/%s", groupDir(s.basePath, s.parent), s.jobID.String())
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroupv2_test
+
+import (
+	"fmt"
+	goos "os"
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os/ostest"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2/cgroupv2test"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Set_Create_Success(t *testing.T) {
+	jobID := uuid.MustParse("0b5183b8-b572-49c7-90c4-fffc775b7d7b")
+	mkdirAllRecorder := ostest.MkdirAllMock{}
+	writeRecorder := ostest.WriteFileMock{}
+	removeRecorder := ostest.RemoveMock{}
+
+	adapter := &os.Adapter{
+		MkdirAllFn:  mkdirAllRecorder.MkdirAll,
+		WriteFileFn: writeRecorder.WriteFile,
+		RemoveFn:    removeRecorder.Remove,
+	}
+
+	controller := &cgroupv2test.ControllerMock{ControllerName: "nil"}
+	set := cgroupv2.NewSetDetailed(adapter, cgroupv2.DefaultBasePath, "jobs", jobID, controller)
+
+	err := set.Create()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(mkdirAllRecorder.Events))
+	assert.Equal(t, 0, len(removeRecorder.Events))
+	assert.Equal(t,
+		fmt.Sprintf("%s/jobs/%s", cgroupv2.DefaultBasePath, jobID.String()),
+		mkdirAllRecorder.Events[2].Path)
+
+	assert.Equal(t, 2, len(writeRecorder.Events))
+	assert.Equal(t,
+		fmt.Sprintf("%s/%s", cgroupv2.DefaultBasePath, cgroupv2.SubtreeControlFilename),
+		writeRecorder.Events[0].Name)
+	assert.Equal(t,
+		fmt.Sprintf("%s/jobs/%s", cgroupv2.DefaultBasePath, cgroupv2.SubtreeControlFilename),
+		writeRecorder.Events[1].Name)
+	assert.Equal(t, []byte("+"+controller.Name()), writeRecorder.Events[1].Data)
+}
+
+func Test_Set_Create_Failure(t *testing.T) {
+	jobID := uuid.MustParse("0b5183b8-b572-49c7-90c4-fffc775b7d7b")
+	mkdirAllRecorder := ostest.MkdirAllMock{}
+	removeRecorder := ostest.RemoveMock{}
+
+	adapter := &os.Adapter{
+		MkdirAllFn:  mkdirAllRecorder.MkdirAll,
+		WriteFileFn: (&ostest.WriteFileMock{}).WriteFile,
+		RemoveFn:    removeRecorder.Remove,
+	}
+
+	expectedError := fmt.Errorf("injected error")
+	controller := &cgroupv2test.ControllerMock{
+		ControllerName:   "nil",
+		ApplyReturnValue: expectedError,
+	}
+	set := cgroupv2.NewSetDetailed(adapter, cgroupv2.DefaultBasePath, "jobs", jobID, controller)
+
+	err := set.Create()
+
+	assert.Equal(t, expectedError, err)
+	assert.Equal(t, 1, len(removeRecorder.Events))
+	assert.Equal(t,
+		fmt.Sprintf("%s/jobs/%s", cgroupv2.DefaultBasePath, jobID.String()),
+		removeRecorder.Events[0].Path)
+}
+
+func Test_Set_Create_EnableControllerFailure(t *testing.T) {
+	jobID := uuid.MustParse("0b5183b8-b572-49c7-90c4-fffc775b7d7b")
+	mkdirAllRecorder := ostest.MkdirAllMock{}
+
+	adapter := &os.Adapter{
+		MkdirAllFn: mkdirAllRecorder.MkdirAll,
+		WriteFileFn: (&ostest.WriteFileMock{
+			NextError: fmt.Errorf("injected error"),
+		}).WriteFile,
+	}
+
+	controller := &cgroupv2test.ControllerMock{ControllerName: "nil"}
+	set := cgroupv2.NewSetDetailed(adapter, cgroupv2.DefaultBasePath, "jobs", jobID, controller)
+
+	err := set.Create()
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, len(mkdirAllRecorder.Events))
+}
+
+func Test_Set_Destroy_Success(t *testing.T) {
+	jobID := uuid.MustParse("0b5183b8-b572-49c7-90c4-fffc775b7d7b")
+	removeRecorder := ostest.RemoveMock{}
+
+	adapter := &os.Adapter{
+		RemoveFn: removeRecorder.Remove,
+	}
+
+	controller := &cgroupv2test.ControllerMock{ControllerName: "nil"}
+	set := cgroupv2.NewSetDetailed(adapter, cgroupv2.DefaultBasePath, "jobs", jobID, controller)
+
+	err := set.Destroy()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(removeRecorder.Events))
+	assert.Equal(t,
+		fmt.Sprintf("%s/jobs/%s", cgroupv2.DefaultBasePath, jobID.String()),
+		removeRecorder.Events[0].Path)
+}
+
+func Test_Set_Destroy_Failure(t *testing.T) {
+	jobID := uuid.MustParse("0b5183b8-b572-49c7-90c4-fffc775b7d7b")
+	removeRecorder := ostest.RemoveMock{
+		NextError: fmt.Errorf("injected error"),
+	}
+
+	adapter := &os.Adapter{
+		RemoveFn: removeRecorder.Remove,
+	}
+
+	controller := &cgroupv2test.ControllerMock{ControllerName: "nil"}
+	set := cgroupv2.NewSetDetailed(adapter, cgroupv2.DefaultBasePath, "jobs", jobID, controller)
+
+	err := set.Destroy()
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, len(removeRecorder.Events))
+}
+
+func Test_Set_Destroy_Twice(t *testing.T) {
+	jobID := uuid.MustParse("0b5183b8-b572-49c7-90c4-fffc775b7d7b")
+	removed := make(map[string]bool)
+
+	adapter := &os.Adapter{
+		RemoveFn: func(path string) error {
+			if removed[path] {
+				return &goos.PathError{Op: "remove", Path: path, Err: goos.ErrNotExist}
+			}
+			removed[path] = true
+			return nil
+		},
+	}
+
+	controller := &cgroupv2test.ControllerMock{ControllerName: "nil"}
+	set := cgroupv2.NewSetDetailed(adapter, cgroupv2.DefaultBasePath, "jobs", jobID, controller)
+
+	assert.Nil(t, set.Destroy())
+
+	// The cgroups no longer exist, which is not a failure
+	assert.Nil(t, set.Destroy())
+	assert.Equal(t, 1, len(removed))
+}
+
+func Test_Set_TaskFiles(t *testing.T) {
+	jobID := uuid.MustParse("0b5183b8-b572-49c7-90c4-fffc775b7d7b")
+
+	controller := &cgroupv2test.ControllerMock{ControllerName: "nil"}
+	set := cgroupv2.NewSet("", jobID, controller)
+
+	taskFiles := set.TaskFiles()
+
+	assert.Equal(t, 1, len(taskFiles))
+	assert.Equal(t,
+		fmt.Sprintf("%s/jobs/%s/%s",
+			cgroupv2.DefaultBasePath,
+			jobID.String(),
+			cgroupv2.ProcsFilename,
+		),
+		taskFiles[0])
+}
+
+func Test_Set_ReadFile(t *testing.T) {
+	const basePath = "/sys/fs/cgroup"
+	jobID := uuid.MustParse("0b5183b8-b572-49c7-90c4-fffc775b7d7b")
+
+	readFileMock := &ostest.ReadFileMock{
+		Files: map[string]string{
+			fmt.Sprintf("%s/jobs/%s/pids.events", basePath, jobID.String()): "max 3\n",
+		},
+	}
+	adapter := &os.Adapter{
+		ReadFileFn: readFileMock.ReadFile,
+	}
+
+	controller := &cgroupv2test.ControllerMock{ControllerName: "pids"}
+	set := cgroupv2.NewSetDetailed(adapter, basePath, "jobs", jobID, controller)
+
+	content, err := set.ReadFile("pids", "pids.events")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("max 3\n"), content)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroup
+
+import (
+	"fmt"
+	"strings"
+	gosyscall "syscall"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	SysDevBlockPath = "/sys/dev/block"
+)
+
+// ResolveBlockDevice returns the "<major>:<minor>" identifier of the block
+// device to which IO limits for the given path should be applied.  If path
+// is a block device node, that device is used; otherwise, the device that
+// holds the filesystem on which path resides is used.
+//
+// The throttling interfaces accept only whole devices, so a partition is
+// resolved to the disk that contains it.  Paths that are not backed by a
+// block device that the kernel knows about (e.g., files on tmpfs) are
+// rejected.
+func ResolveBlockDevice(osAdapter *os.Adapter, syscallAdapter *syscall.Adapter, path string) (string, error) {
+	var stat gosyscall.Stat_t
+
+	if err := syscallAdapter.Stat(path, &stat); err != nil {
+		return "", err
+	}
+
+	dev := uint64(stat.Dev)
+	if stat.Mode&gosyscall.S_IFMT == gosyscall.S_IFBLK {
+		dev = uint64(stat.Rdev)
+	}
+
+	device := fmt.Sprintf("%d:%d", unix.Major(dev), unix.Minor(dev))
+	sysfsDir := fmt.Sprintf("%s/%s", SysDevBlockPath, device)
+
+	if _, err := osAdapter.ReadFile(sysfsDir + "/dev"); err != nil {
+		return "", fmt.Errorf("%s is not backed by a known block device (%s)", path, device)
+	}
+
+	// Only partitions have a "partition" attribute.  The sysfs directory of
+	// a partition is a subdirectory of the directory of its disk.
+	if _, err := osAdapter.ReadFile(sysfsDir + "/partition"); err == nil {
+		parent, err := osAdapter.ReadFile(sysfsDir + "/../dev")
+		if err != nil {
+			return "", err
+		}
+
+		device = strings.TrimSpace(string(parent))
+	}
+
+	return device, nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroup_test
+
+import (
+	gosyscall "syscall"
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os/ostest"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/syscall"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/syscall/syscalltest"
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+// newResolveAdapters returns adapters for a host with a single disk (8:0)
+// with one partition (8:1), on which /data resides.
+func newResolveAdapters() (*os.Adapter, *syscall.Adapter) {
+	readFileMock := &ostest.ReadFileMock{
+		Files: map[string]string{
+			"/sys/dev/block/8:0/dev":       "8:0\n",
+			"/sys/dev/block/8:1/dev":       "8:1\n",
+			"/sys/dev/block/8:1/partition": "1\n",
+			"/sys/dev/block/8:1/../dev":    "8:0\n",
+		},
+	}
+
+	statMock := &syscalltest.StatMock{
+		Files: map[string]gosyscall.Stat_t{
+			"/data":     {Mode: gosyscall.S_IFDIR, Dev: unix.Mkdev(8, 1)},
+			"/dev/sda":  {Mode: gosyscall.S_IFBLK, Dev: unix.Mkdev(0, 5), Rdev: unix.Mkdev(8, 0)},
+			"/dev/sda1": {Mode: gosyscall.S_IFBLK, Dev: unix.Mkdev(0, 5), Rdev: unix.Mkdev(8, 1)},
+			"/dev/shm":  {Mode: gosyscall.S_IFDIR, Dev: unix.Mkdev(0, 24)},
+		},
+	}
+
+	return &os.Adapter{ReadFileFn: readFileMock.ReadFile},
+		&syscall.Adapter{StatFn: statMock.Stat}
+}
+
+func Test_ResolveBlockDevice_DeviceNode(t *testing.T) {
+	osAdapter, syscallAdapter := newResolveAdapters()
+
+	device, err := cgroup.ResolveBlockDevice(osAdapter, syscallAdapter, "/dev/sda")
+
+	require.Nil(t, err)
+	assert.Equal(t, "8:0", device)
+}
+
+func Test_ResolveBlockDevice_PartitionNode(t *testing.T) {
+	osAdapter, syscallAdapter := newResolveAdapters()
+
+	device, err := cgroup.ResolveBlockDevice(osAdapter, syscallAdapter, "/dev/sda1")
+
+	require.Nil(t, err)
+	assert.Equal(t, "8:0", device)
+}
+
+func Test_ResolveBlockDevice_FilesystemPath(t *testing.T) {
+	osAdapter, syscallAdapter := newResolveAdapters()
+
+	device, err := cgroup.ResolveBlockDevice(osAdapter, syscallAdapter, "/data")
+
+	require.Nil(t, err)
+	assert.Equal(t, "8:0", device)
+}
+
+func Test_ResolveBlockDevice_NoBlockDevice(t *testing.T) {
+	osAdapter, syscallAdapter := newResolveAdapters()
+
+	_, err := cgroup.ResolveBlockDevice(osAdapter, syscallAdapter, "/dev/shm")
+
+	assert.Error(t, err)
+}
+
+func Test_ResolveBlockDevice_NoSuchPath(t *testing.T) {
+	osAdapter, syscallAdapter := newResolveAdapters()
+
+	_, err := cgroup.ResolveBlockDevice(osAdapter, syscallAdapter, "/no/such/path")
+
+	assert.Error(t, err)
+}
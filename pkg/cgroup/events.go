@@ -0,0 +1,52 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroup
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+const (
+	// PidsEventsFilename is the name of the pids controller's events file.
+	// The file has the same name and format in both versions of the
+	// interface.
+	PidsEventsFilename = "pids.events"
+)
+
+// ParseEvents parses the content of a flat-keyed cgroup events file (e.g.,
+// pids.events), in which each line has the form "<key> <count>".  Malformed
+// lines are ignored.
+func ParseEvents(content []byte) map[string]uint64 {
+	events := make(map[string]uint64)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+
+		count, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		events[fields[0]] = count
+	}
+
+	return events
+}
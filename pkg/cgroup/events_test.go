@@ -0,0 +1,28 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroup_test
+
+import (
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseEvents(t *testing.T) {
+	events := cgroup.ParseEvents([]byte("max 3\nlow 0\nmalformed\nbad value\n"))
+
+	assert.Equal(t, map[string]uint64{"max": 3, "low": 0}, events)
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroup
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MountInfoPath = "/proc/self/mountinfo"
+	CgroupsPath   = "/proc/cgroups"
+)
+
+// Version identifies a version of the cgroup interface.
+type Version int
+
+const (
+	V1 Version = 1
+	V2 Version = 2
+)
+
+func (v Version) String() string {
+	return fmt.Sprintf("v%d", int(v))
+}
+
+// Hierarchy describes the cgroup interface that is in use on the host.
+type Hierarchy struct {
+	// Version is the version of the cgroup interface in use.
+	Version Version
+
+	// BasePath is the directory under which the hierarchy is mounted.  For
+	// v2 this is the mount point of the unified hierarchy; for v1 it is the
+	// directory that contains the per-controller mount points.
+	BasePath string
+
+	// Controllers is the sorted list of controllers that are both enabled
+	// in the kernel and available in the hierarchy.
+	Controllers []string
+
+	// JobsParent is the path, relative to the root of the hierarchy, of the
+	// cgroup under which job cgroups are created (e.g., "jobs", or a
+	// delegated systemd unit such as "system.slice/jobmanager.service/jobs").
+	JobsParent string
+}
+
+// HasController returns true if the controller with the given name is
+// available in this hierarchy, false otherwise.
+func (h *Hierarchy) HasController(name string) bool {
+	i := sort.SearchStrings(h.Controllers, name)
+
+	return i < len(h.Controllers) && h.Controllers[i] == name
+}
+
+// NewSet creates a new Set for the given jobID within the named group of
+// the JobsParent cgroup using the implementation associated with this
+// hierarchy's version.  If group is empty, the job's cgroups are created
+// directly in the JobsParent cgroup.
+func (h *Hierarchy) NewSet(group string, jobID uuid.UUID, controllers ...Controller) Set {
+	parent := path.Join(h.JobsParent, group)
+
+	if h.Version == V2 {
+		return cgroupv2.NewSetDetailed(nil, h.BasePath, parent, jobID, toV2(controllers)...)
+	}
+
+	return cgroupv1.NewSetDetailed(nil, h.BasePath, parent, jobID, toV1(controllers)...)
+}
+
+// NewGroup creates a new Group with the given name within the JobsParent
+// cgroup using the implementation associated with this hierarchy's version.
+func (h *Hierarchy) NewGroup(name string, controllers ...Controller) Group {
+	return h.NewGroupAt(path.Join(h.JobsParent, name), controllers...)
+}
+
+// NewGroupAt creates a new Group for the cgroup at the given path, relative
+// to the root of the hierarchy, using the implementation associated with
+// this hierarchy's version.
+func (h *Hierarchy) NewGroupAt(relPath string, controllers ...Controller) Group {
+	if h.Version == V2 {
+		return cgroupv2.NewGroupDetailed(nil, h.BasePath, relPath, toV2(controllers)...)
+	}
+
+	return cgroupv1.NewGroupDetailed(nil, h.BasePath, relPath, toV1(controllers)...)
+}
+
+func toV1(controllers []Controller) []cgroupv1.Controller {
+	v1Controllers := make([]cgroupv1.Controller, len(controllers))
+	for i := range controllers {
+		v1Controllers[i] = controllers[i]
+	}
+
+	return v1Controllers
+}
+
+func toV2(controllers []Controller) []cgroupv2.Controller {
+	v2Controllers := make([]cgroupv2.Controller, len(controllers))
+	for i := range controllers {
+		v2Controllers[i] = controllers[i]
+	}
+
+	return v2Controllers
+}
+
+var (
+	defaultHierarchyOnce sync.Once
+	defaultHierarchy     *Hierarchy
+	defaultHierarchyErr  error
+)
+
+// Default returns the Hierarchy in use on this host.  The host is examined
+// only once; the mounted cgroup hierarchies are not expected to change while
+// the process is running, so all subsequent calls return the cached result.
+func Default() (*Hierarchy, error) {
+	defaultHierarchyOnce.Do(func() {
+		defaultHierarchy, defaultHierarchyErr = Detect(nil)
+	})
+
+	return defaultHierarchy, defaultHierarchyErr
+}
+
+// Detect examines /proc/self/mountinfo and /proc/cgroups to determine which
+// cgroup interface is in use and which controllers are available.
+//
+// If any v1 hierarchy has a controller bound to it, the host is using the v1
+// interface (this includes "hybrid" hosts, which also mount an empty unified
+// hierarchy).  Otherwise, if a unified hierarchy is mounted, the host is using
+// the v2 interface.
+func Detect(osAdapter *os.Adapter) (*Hierarchy, error) {
+	mountInfo, err := osAdapter.ReadFile(MountInfoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cgroups, err := osAdapter.ReadFile(CgroupsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := parseCgroups(cgroups)
+
+	var (
+		v1BasePath  string
+		v1Available []string
+		v2BasePath  string
+	)
+
+	for _, mount := range parseMountInfo(mountInfo) {
+		switch mount.fsType {
+		case "cgroup":
+			for _, option := range mount.superOptions {
+				if enabled[option] {
+					v1Available = append(v1Available, option)
+					v1BasePath = path.Dir(mount.mountPoint)
+				}
+			}
+
+		case "cgroup2":
+			if v2BasePath == "" {
+				v2BasePath = mount.mountPoint
+			}
+		}
+	}
+
+	if len(v1Available) > 0 {
+		sort.Strings(v1Available)
+
+		return &Hierarchy{
+			Version:     V1,
+			BasePath:    v1BasePath,
+			Controllers: v1Available,
+			JobsParent:  cgroupv1.DefaultJobsParent,
+		}, nil
+	}
+
+	if v2BasePath == "" {
+		return nil, fmt.Errorf("no cgroup hierarchy is mounted")
+	}
+
+	controllers, err := osAdapter.ReadFile(v2BasePath + "/cgroup.controllers")
+	if err != nil {
+		return nil, err
+	}
+
+	var v2Available []string
+	for _, name := range strings.Fields(string(controllers)) {
+		if enabled[name] {
+			v2Available = append(v2Available, name)
+		}
+	}
+	sort.Strings(v2Available)
+
+	return &Hierarchy{
+		Version:     V2,
+		BasePath:    v2BasePath,
+		Controllers: v2Available,
+		JobsParent:  cgroupv2.DefaultJobsParent,
+	}, nil
+}
+
+// mountInfoEntry models the fields of interest in a line of mountinfo.
+type mountInfoEntry struct {
+	mountPoint   string
+	fsType       string
+	superOptions []string
+}
+
+// parseMountInfo parses the content of /proc/<pid>/mountinfo.  Each line has
+// the form:
+//
+//	36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+//
+// The number of optional fields before the "-" separator varies.  See
+// proc(5) for details.  Malformed lines are ignored.
+func parseMountInfo(content []byte) []mountInfoEntry {
+	var entries []mountInfoEntry
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+
+		separator := -1
+		for i := range fields {
+			if fields[i] == "-" {
+				separator = i
+				break
+			}
+		}
+
+		if separator < 5 || separator+3 > len(fields) {
+			continue
+		}
+
+		entries = append(entries, mountInfoEntry{
+			mountPoint:   fields[4],
+			fsType:       fields[separator+1],
+			superOptions: strings.Split(fields[separator+3], ","),
+		})
+	}
+
+	return entries
+}
+
+// parseCgroups parses the content of /proc/cgroups and returns the set of
+// controllers that are enabled in the kernel.
+func parseCgroups(content []byte) map[string]bool {
+	enabled := make(map[string]bool)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// subsys_name hierarchy num_cgroups enabled
+		fields := strings.Fields(line)
+		if len(fields) == 4 && fields[3] == "1" {
+			enabled[fields[0]] = true
+		}
+	}
+
+	return enabled
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroup_test
+
+import (
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os/ostest"
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const procCgroups = `#subsys_name	hierarchy	num_cgroups	enabled
+cpuset	3	1	1
+cpu	1	1	1
+cpuacct	2	1	1
+blkio	7	1	1
+memory	4	25	0
+io	0	1	1
+pids	0	1	1
+`
+
+func Test_Detect_V1(t *testing.T) {
+	readFileMock := &ostest.ReadFileMock{
+		Files: map[string]string{
+			cgroup.CgroupsPath: procCgroups,
+			cgroup.MountInfoPath: `32 24 0:28 / /sys/fs/cgroup rw,relatime - tmpfs tmpfs rw,mode=755
+33 32 0:29 / /sys/fs/cgroup/cpu rw,relatime shared:9 - cgroup cgroup rw,cpu
+36 32 0:32 / /sys/fs/cgroup/memory rw,relatime - cgroup cgroup rw,memory
+39 32 0:35 / /sys/fs/cgroup/blkio rw,relatime - cgroup cgroup rw,blkio
+41 32 0:37 / /sys/fs/cgroup/systemd rw,relatime - cgroup cgroup rw,name=systemd
+42 32 0:38 / /sys/fs/cgroup/unified rw,relatime - cgroup2 cgroup2 rw
+`,
+		},
+	}
+	adapter := &os.Adapter{
+		ReadFileFn: readFileMock.ReadFile,
+	}
+
+	h, err := cgroup.Detect(adapter)
+
+	require.Nil(t, err)
+	assert.Equal(t, cgroup.V1, h.Version)
+	assert.Equal(t, "/sys/fs/cgroup", h.BasePath)
+	assert.Equal(t, cgroupv1.DefaultJobsParent, h.JobsParent)
+	// memory is disabled in /proc/cgroups
+	assert.Equal(t, []string{"blkio", "cpu"}, h.Controllers)
+	assert.True(t, h.HasController("cpu"))
+	assert.False(t, h.HasController("memory"))
+}
+
+func Test_Detect_V2(t *testing.T) {
+	readFileMock := &ostest.ReadFileMock{
+		Files: map[string]string{
+			cgroup.CgroupsPath:                  procCgroups,
+			cgroup.MountInfoPath:                "35 24 0:30 / /sys/fs/cgroup rw,nosuid shared:9 - cgroup2 cgroup2 rw,nsdelegate\n",
+			"/sys/fs/cgroup/cgroup.controllers": "cpuset cpu io memory hugetlb pids\n",
+		},
+	}
+	adapter := &os.Adapter{
+		ReadFileFn: readFileMock.ReadFile,
+	}
+
+	h, err := cgroup.Detect(adapter)
+
+	require.Nil(t, err)
+	assert.Equal(t, cgroup.V2, h.Version)
+	assert.Equal(t, "/sys/fs/cgroup", h.BasePath)
+	assert.Equal(t, cgroupv2.DefaultJobsParent, h.JobsParent)
+	assert.Equal(t, []string{"cpu", "cpuset", "io", "pids"}, h.Controllers)
+}
+
+func Test_Detect_NoHierarchy(t *testing.T) {
+	readFileMock := &ostest.ReadFileMock{
+		Files: map[string]string{
+			cgroup.CgroupsPath:   procCgroups,
+			cgroup.MountInfoPath: "22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw\n",
+		},
+	}
+	adapter := &os.Adapter{
+		ReadFileFn: readFileMock.ReadFile,
+	}
+
+	_, err := cgroup.Detect(adapter)
+
+	assert.Error(t, err)
+}
+
+func Test_Detect_NoMountInfo(t *testing.T) {
+	adapter := &os.Adapter{
+		ReadFileFn: (&ostest.ReadFileMock{}).ReadFile,
+	}
+
+	_, err := cgroup.Detect(adapter)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroup
+
+import (
+	"errors"
+	"fmt"
+	goos "os"
+	gosyscall "syscall"
+	"time"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/syscall"
+)
+
+var (
+	// KillAttempts and KillInterval control how many times, and how often,
+	// Kill checks whether a set's processes are frozen, and whether they
+	// have exited, before giving up.
+	KillAttempts = 50
+	KillInterval = 100 * time.Millisecond
+)
+
+// Kill kills all of the processes in the given set's cgroups, including any
+// that have left the job's process tree, and waits until the cgroups are
+// empty.  The processes are frozen first, if possible, so that none can fork
+// a new process that escapes being killed; otherwise, the processes are
+// killed repeatedly until none remain.  If the cgroups are destroyed in the
+// meantime, which is possible only once they are empty, Kill succeeds.
+func (h *Hierarchy) Kill(syscallAdapter *syscall.Adapter, set Set) error {
+	frozen := freeze(set)
+
+	for attempt := 1; ; attempt++ {
+		pids, err := set.Pids()
+		if goos.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			if frozen {
+				_ = set.Thaw()
+			}
+			return err
+		}
+
+		if len(pids) == 0 {
+			if frozen {
+				return set.Thaw()
+			}
+			return nil
+		}
+
+		for _, pid := range pids {
+			err := syscallAdapter.Kill(pid, gosyscall.SIGKILL)
+			if err != nil && !errors.Is(err, gosyscall.ESRCH) {
+				if frozen {
+					_ = set.Thaw()
+				}
+				return fmt.Errorf("failed to kill process %d: %w", pid, err)
+			}
+		}
+
+		// Every process was killed while frozen, so none can have forked
+		// since.  A v1 frozen process acts on SIGKILL only once thawed.
+		if frozen {
+			if err := set.Thaw(); err != nil {
+				return err
+			}
+			frozen = false
+		}
+
+		if attempt >= KillAttempts {
+			return fmt.Errorf("processes %v remain after being killed", pids)
+		}
+
+		time.Sleep(KillInterval)
+	}
+}
+
+// freeze freezes the processes in the given set's cgroups and waits until
+// they are frozen.  It returns true if they are frozen, false otherwise, in
+// which case the set is left thawed.
+func freeze(set Set) bool {
+	if err := set.Freeze(); err != nil {
+		return false
+	}
+
+	for attempt := 1; attempt <= KillAttempts; attempt++ {
+		if frozen, err := set.Frozen(); err != nil {
+			break
+		} else if frozen {
+			return true
+		}
+
+		time.Sleep(KillInterval)
+	}
+
+	_ = set.Thaw()
+
+	return false
+}
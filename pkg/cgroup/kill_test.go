@@ -0,0 +1,139 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroup_test
+
+import (
+	"fmt"
+	"io"
+	goos "os"
+	"syscall"
+	"testing"
+
+	adaptsyscall "github.com/adalton/teleport-exercise/pkg/adaptation/syscall"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/syscall/syscalltest"
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// killSetMock is a cgroup.Set that reports each of the lists of processes in
+// pids in turn, then pidsErr, and records the freezer operations it receives.
+type killSetMock struct {
+	freezeErr error
+	pids      [][]int
+	pidsErr   error
+	ops       []string
+}
+
+func (s *killSetMock) Create() error                           { return nil }
+func (s *killSetMock) Destroy() error                          { return nil }
+func (s *killSetMock) TaskFiles() []string                     { return nil }
+func (s *killSetMock) ReadFile(string, string) ([]byte, error) { return nil, nil }
+func (s *killSetMock) WatchOom(func()) (io.Closer, error)      { return nil, nil }
+func (s *killSetMock) Frozen() (bool, error)                   { return true, nil }
+
+func (s *killSetMock) Freeze() error {
+	s.ops = append(s.ops, "freeze")
+	return s.freezeErr
+}
+
+func (s *killSetMock) Thaw() error {
+	s.ops = append(s.ops, "thaw")
+	return nil
+}
+
+func (s *killSetMock) Pids() ([]int, error) {
+	if len(s.pids) == 0 {
+		return nil, s.pidsErr
+	}
+
+	pids := s.pids[0]
+	s.pids = s.pids[1:]
+
+	return pids, nil
+}
+
+func setKillInterval(t *testing.T, attempts int) {
+	oldAttempts, oldInterval := cgroup.KillAttempts, cgroup.KillInterval
+	cgroup.KillAttempts, cgroup.KillInterval = attempts, 0
+	t.Cleanup(func() {
+		cgroup.KillAttempts, cgroup.KillInterval = oldAttempts, oldInterval
+	})
+}
+
+func Test_Hierarchy_Kill_Frozen(t *testing.T) {
+	setKillInterval(t, 5)
+	set := &killSetMock{pids: [][]int{{5, 6}, {6}, nil}}
+	killMock := &syscalltest.KillMock{}
+
+	err := (&cgroup.Hierarchy{}).Kill(&adaptsyscall.Adapter{KillFn: killMock.Kill}, set)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"freeze", "thaw"}, set.ops)
+	assert.Equal(t, 3, len(killMock.Events))
+	assert.Equal(t, 5, killMock.Events[0].Pid)
+	assert.Equal(t, syscall.SIGKILL, killMock.Events[0].Signal)
+}
+
+func Test_Hierarchy_Kill_NoFreezer(t *testing.T) {
+	setKillInterval(t, 5)
+	set := &killSetMock{
+		freezeErr: fmt.Errorf("no cgroup for controller freezer"),
+		pids:      [][]int{{5}, {7}, nil},
+	}
+	killMock := &syscalltest.KillMock{}
+
+	err := (&cgroup.Hierarchy{}).Kill(&adaptsyscall.Adapter{KillFn: killMock.Kill}, set)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"freeze"}, set.ops)
+	assert.Equal(t, 2, len(killMock.Events))
+	assert.Equal(t, 7, killMock.Events[1].Pid)
+}
+
+func Test_Hierarchy_Kill_ProcessGone(t *testing.T) {
+	setKillInterval(t, 5)
+	set := &killSetMock{pids: [][]int{{5}, nil}}
+	killMock := &syscalltest.KillMock{NextError: syscall.ESRCH}
+
+	err := (&cgroup.Hierarchy{}).Kill(&adaptsyscall.Adapter{KillFn: killMock.Kill}, set)
+
+	assert.Nil(t, err)
+}
+
+func Test_Hierarchy_Kill_Destroyed(t *testing.T) {
+	setKillInterval(t, 5)
+
+	// The job's process terminated and its cgroups were destroyed
+	set := &killSetMock{pids: [][]int{{5}}, pidsErr: &goos.PathError{Op: "open", Err: syscall.ENOENT}}
+	killMock := &syscalltest.KillMock{}
+
+	err := (&cgroup.Hierarchy{}).Kill(&adaptsyscall.Adapter{KillFn: killMock.Kill}, set)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(killMock.Events))
+}
+
+func Test_Hierarchy_Kill_Timeout(t *testing.T) {
+	setKillInterval(t, 2)
+	set := &killSetMock{pids: [][]int{{5}, {5}, {5}}}
+	killMock := &syscalltest.KillMock{}
+
+	err := (&cgroup.Hierarchy{}).Kill(&adaptsyscall.Adapter{KillFn: killMock.Kill}, set)
+
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "remain")
+	}
+	assert.Equal(t, 2, len(killMock.Events))
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroup
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+)
+
+// Knob is a cgroup interface file, such as "memory.swappiness", and the value
+// to write to it.
+type Knob struct {
+	File  string
+	Value string
+}
+
+// KnobController writes arbitrary interface files of a single cgroup
+// controller.  It provides access to settings that have no dedicated
+// controller type.  Each Knob's File must belong to the named Controller
+// (e.g., "memory.swappiness" for "memory") and must be one of the Allowed
+// files; the Knobs are written in order.  Callers are responsible for
+// deciding which files are Allowed, and for checking that they exist (see
+// Hierarchy.HasKnobFile).
+type KnobController struct {
+	OsAdapter  *os.Adapter
+	Controller string
+	Knobs      []Knob
+	Allowed    []string
+}
+
+func (k KnobController) Name() string {
+	return k.Controller
+}
+
+func (k *KnobController) Apply(path string) error {
+	for _, knob := range k.Knobs {
+		if !IsKnobFile(k.Controller, knob.File) {
+			return fmt.Errorf("%s is not a %s controller file", knob.File, k.Controller)
+		}
+
+		if !k.IsAllowed(knob.File) {
+			return fmt.Errorf("%s may not be written", knob.File)
+		}
+
+		filename := fmt.Sprintf("%s/%s", path, knob.File)
+		if err := k.OsAdapter.WriteFile(filename, []byte(knob.Value), os.FileMode(0644)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsAllowed returns true if the given file is one of the controller's Allowed
+// files.
+func (k KnobController) IsAllowed(file string) bool {
+	for _, allowed := range k.Allowed {
+		if file == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsKnobFile returns true if the given file is an interface file of the given
+// controller in a cgroup directory -- that is, if it has the form
+// "<controller>.<name>" and does not name any other path.
+func IsKnobFile(controller, file string) bool {
+	name := strings.TrimPrefix(file, controller+".")
+
+	return controller != "" &&
+		!strings.ContainsAny(controller, "./\x00") &&
+		name != file &&
+		name != "" &&
+		!strings.ContainsAny(name, "/\x00") &&
+		name != "." && name != ".."
+}
+
+// NewKnobController returns a KnobController that writes the given knobs of
+// the named controller and that may write only the given allowed files.
+func NewKnobController(controller string, knobs []Knob, allowed []string) *KnobController {
+	return &KnobController{
+		Controller: controller,
+		Knobs:      knobs,
+		Allowed:    allowed,
+	}
+}
+
+// HasKnobFile returns true if the given interface file, such as
+// "memory.swappiness", belongs to a controller that is available in this
+// hierarchy and exists in the JobsParent cgroup, and so in the cgroups of
+// jobs.  It uses the given osAdapter to examine the JobsParent cgroup, which
+// must exist.
+func (h *Hierarchy) HasKnobFile(osAdapter *os.Adapter, file string) bool {
+	controller, _, _ := strings.Cut(file, ".")
+	if !IsKnobFile(controller, file) || !h.HasController(controller) {
+		return false
+	}
+
+	dir := path.Join(h.BasePath, h.JobsParent)
+	if h.Version == V1 {
+		dir = path.Join(h.BasePath, controller, h.JobsParent)
+	}
+
+	_, err := osAdapter.Stat(path.Join(dir, file))
+
+	return err == nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroup_test
+
+import (
+	goos "os"
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os/ostest"
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_knob_Apply(t *testing.T) {
+	path := "/sys/fs/cgroup/jobs/889f7cc2-9935-4773-aaa1-b94478abc923"
+	writeRecorder := ostest.WriteFileMock{}
+	adapter := &os.Adapter{
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	knobs := &cgroup.KnobController{
+		OsAdapter:  adapter,
+		Controller: "memory",
+		Knobs: []cgroup.Knob{
+			{File: "memory.swappiness", Value: "0"},
+			{File: "memory.oom.group", Value: "1"},
+		},
+		Allowed: []string{"memory.oom.group", "memory.swappiness"},
+	}
+	err := knobs.Apply(path)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "memory", knobs.Name())
+	assert.Equal(t, 2, len(writeRecorder.Events))
+	assert.Equal(t, path+"/memory.swappiness", writeRecorder.Events[0].Name)
+	assert.Equal(t, []byte("0"), writeRecorder.Events[0].Data)
+	assert.Equal(t, path+"/memory.oom.group", writeRecorder.Events[1].Name)
+	assert.Equal(t, []byte("1"), writeRecorder.Events[1].Data)
+}
+
+func Test_knob_Apply_ForeignFile(t *testing.T) {
+	path := "/sys/fs/cgroup/jobs/889f7cc2-9935-4773-aaa1-b94478abc923"
+	writeRecorder := ostest.WriteFileMock{}
+	adapter := &os.Adapter{
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	knobs := &cgroup.KnobController{
+		OsAdapter:  adapter,
+		Controller: "memory",
+		Knobs:      []cgroup.Knob{{File: "memory.x/../../../../etc/passwd", Value: "0"}},
+		Allowed:    []string{"memory.x/../../../../etc/passwd"},
+	}
+	err := knobs.Apply(path)
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, len(writeRecorder.Events))
+}
+
+func Test_knob_Apply_NotAllowed(t *testing.T) {
+	path := "/sys/fs/cgroup/jobs/889f7cc2-9935-4773-aaa1-b94478abc923"
+	writeRecorder := ostest.WriteFileMock{}
+	adapter := &os.Adapter{
+		WriteFileFn: writeRecorder.WriteFile,
+	}
+
+	knobs := &cgroup.KnobController{
+		OsAdapter:  adapter,
+		Controller: "memory",
+		Knobs: []cgroup.Knob{
+			{File: "memory.swappiness", Value: "0"},
+			{File: "memory.limit_in_bytes", Value: "1G"},
+		},
+		Allowed: []string{"memory.swappiness"},
+	}
+	err := knobs.Apply(path)
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, len(writeRecorder.Events))
+}
+
+func Test_knob_IsKnobFile(t *testing.T) {
+	assert.True(t, cgroup.IsKnobFile("memory", "memory.swappiness"))
+	assert.True(t, cgroup.IsKnobFile("memory", "memory.oom.group"))
+	assert.False(t, cgroup.IsKnobFile("memory", "cpu.shares"))
+	assert.False(t, cgroup.IsKnobFile("memory", "memory."))
+	assert.False(t, cgroup.IsKnobFile("memory", "memory.."))
+	assert.False(t, cgroup.IsKnobFile("memory", "memory/x"))
+	assert.False(t, cgroup.IsKnobFile("memory", "memory.x/y"))
+	assert.False(t, cgroup.IsKnobFile("", ".swappiness"))
+	assert.False(t, cgroup.IsKnobFile("..", "...x"))
+	assert.False(t, cgroup.IsKnobFile("memory", "tasks"))
+}
+
+func Test_Hierarchy_HasKnobFile(t *testing.T) {
+	var statted []string
+	adapter := &os.Adapter{
+		StatFn: func(name string) (os.FileInfo, error) {
+			statted = append(statted, name)
+			if name == "/sys/fs/cgroup/memory/jobs/memory.swappiness" ||
+				name == "/sys/fs/cgroup/jobs/memory.oom.group" {
+				return nil, nil
+			}
+			return nil, goos.ErrNotExist
+		},
+	}
+
+	v1 := &cgroup.Hierarchy{
+		Version:     cgroup.V1,
+		BasePath:    "/sys/fs/cgroup",
+		Controllers: []string{"cpu", "memory"},
+		JobsParent:  "jobs",
+	}
+
+	assert.True(t, v1.HasKnobFile(adapter, "memory.swappiness"))
+	assert.False(t, v1.HasKnobFile(adapter, "memory.oom.group"))
+	assert.False(t, v1.HasKnobFile(adapter, "pids.max"))
+	assert.False(t, v1.HasKnobFile(adapter, "memory.x/../../memory.swappiness"))
+
+	v2 := &cgroup.Hierarchy{
+		Version:     cgroup.V2,
+		BasePath:    "/sys/fs/cgroup",
+		Controllers: []string{"cpu", "memory"},
+		JobsParent:  "jobs",
+	}
+
+	assert.True(t, v2.HasKnobFile(adapter, "memory.oom.group"))
+	assert.False(t, v2.HasKnobFile(adapter, "memory.swappiness"))
+
+	// Files of unavailable controllers and foreign paths are never examined
+	assert.Equal(t, []string{
+		"/sys/fs/cgroup/memory/jobs/memory.swappiness",
+		"/sys/fs/cgroup/memory/jobs/memory.oom.group",
+		"/sys/fs/cgroup/jobs/memory.oom.group",
+		"/sys/fs/cgroup/jobs/memory.swappiness",
+	}, statted)
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroup
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2"
+)
+
+// OomKills returns the number of processes in the memory cgroup of the given
+// set that the OOM killer has killed.  The set must belong to this hierarchy.
+func (h *Hierarchy) OomKills(set Set) (uint64, error) {
+	// Both memory.oom_control (v1) and memory.events (v2) are flat-keyed
+	// files with an oom_kill count.
+	filename := cgroupv2.MemoryEventsFilename
+	if h.Version == V1 {
+		filename = cgroupv1.MemoryOomControlFilename
+	}
+
+	content, err := set.ReadFile("memory", filename)
+	if err != nil {
+		return 0, err
+	}
+
+	return ParseEvents(content)["oom_kill"], nil
+}
+
+// PeakMemoryUsage returns the maximum memory usage, in bytes, recorded for
+// the memory cgroup of the given set.  The set must belong to this hierarchy.
+func (h *Hierarchy) PeakMemoryUsage(set Set) (uint64, error) {
+	filename := cgroupv2.MemoryPeakFilename
+	if h.Version == V1 {
+		filename = cgroupv1.MemoryMaxUsageInBytesFilename
+	}
+
+	content, err := set.ReadFile("memory", filename)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+}
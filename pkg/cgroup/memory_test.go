@@ -0,0 +1,94 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroup_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os/ostest"
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const memoryTestJobID = "0b5183b8-b572-49c7-90c4-fffc775b7d7b"
+
+func Test_Hierarchy_OomKills_V1(t *testing.T) {
+	readFileMock := &ostest.ReadFileMock{
+		Files: map[string]string{
+			fmt.Sprintf("/cg/memory/jobs/%s/memory.oom_control", memoryTestJobID): "oom_kill_disable 0\nunder_oom 0\noom_kill 2\n",
+		},
+	}
+
+	hierarchy := &cgroup.Hierarchy{Version: cgroup.V1, BasePath: "/cg", JobsParent: "jobs"}
+	set := newMemoryTestSet(hierarchy, readFileMock)
+
+	oomKills, err := hierarchy.OomKills(set)
+
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), oomKills)
+}
+
+func Test_Hierarchy_OomKills_V2(t *testing.T) {
+	readFileMock := &ostest.ReadFileMock{
+		Files: map[string]string{
+			fmt.Sprintf("/cg/jobs/%s/memory.events", memoryTestJobID): "low 0\nhigh 0\nmax 7\noom 1\noom_kill 1\n",
+		},
+	}
+
+	hierarchy := &cgroup.Hierarchy{Version: cgroup.V2, BasePath: "/cg", JobsParent: "jobs"}
+	set := newMemoryTestSet(hierarchy, readFileMock)
+
+	oomKills, err := hierarchy.OomKills(set)
+
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), oomKills)
+}
+
+func Test_Hierarchy_PeakMemoryUsage(t *testing.T) {
+	readFileMock := &ostest.ReadFileMock{
+		Files: map[string]string{
+			fmt.Sprintf("/cg/memory/jobs/%s/memory.max_usage_in_bytes", memoryTestJobID): "16777216\n",
+			fmt.Sprintf("/cg/jobs/%s/memory.peak", memoryTestJobID):                      "8388608\n",
+		},
+	}
+
+	for version, expected := range map[cgroup.Version]uint64{cgroup.V1: 16777216, cgroup.V2: 8388608} {
+		hierarchy := &cgroup.Hierarchy{Version: version, BasePath: "/cg", JobsParent: "jobs"}
+		set := newMemoryTestSet(hierarchy, readFileMock)
+
+		peak, err := hierarchy.PeakMemoryUsage(set)
+
+		assert.Nil(t, err, version)
+		assert.Equal(t, expected, peak, version)
+	}
+}
+
+// newMemoryTestSet returns a Set of the given hierarchy's version that reads
+// files using readFileMock.
+func newMemoryTestSet(hierarchy *cgroup.Hierarchy, readFileMock *ostest.ReadFileMock) cgroup.Set {
+	adapter := &os.Adapter{ReadFileFn: readFileMock.ReadFile}
+	jobID := uuid.MustParse(memoryTestJobID)
+
+	if hierarchy.Version == cgroup.V1 {
+		return cgroupv1.NewSetDetailed(adapter, hierarchy.BasePath, hierarchy.JobsParent, jobID)
+	}
+
+	return cgroupv2.NewSetDetailed(adapter, hierarchy.BasePath, hierarchy.JobsParent, jobID)
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroup
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	goos "os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	gosyscall "syscall"
+	"time"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/syscall"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// ProcsFilename is the name of the file that lists the processes in a
+	// cgroup in both versions of the interface.
+	ProcsFilename = "cgroup.procs"
+)
+
+var (
+	// OrphanRemoveAttempts and OrphanRemoveInterval control how many times,
+	// and how often, RemoveOrphan tries to kill the processes in an orphan's
+	// cgroups and remove them.  A cgroup cannot be removed until the
+	// processes that were killed have been reaped.
+	OrphanRemoveAttempts = 50
+	OrphanRemoveInterval = 100 * time.Millisecond
+)
+
+// Orphan identifies the cgroups of a job that were found in the JobsParent
+// cgroup but that are not managed by any Set, typically because a previous
+// instance of the server terminated before it destroyed them.
+type Orphan struct {
+	// Group is the name of the group (see NewGroup) that contains the
+	// job's cgroups, or "" if they are directly in the JobsParent cgroup.
+	Group string
+
+	// JobID is the ID of the job to which the cgroups belonged.
+	JobID uuid.UUID
+
+	// Pids is the list of processes that RemoveOrphan found in, and killed
+	// in, the job's cgroups.
+	Pids []int
+
+	// dirs is the list of the job's cgroup directories; v1 has one per
+	// controller hierarchy.
+	dirs []string
+}
+
+// FindOrphans returns the job cgroups that exist in the JobsParent cgroup,
+// whether directly or within a group.  Job cgroups are recognized by their
+// names, which are job IDs; all other cgroups are assumed to be groups.  The
+// caller is responsible for excluding the jobs that it manages.
+//
+// Orphans in groups are ordered before those directly in the JobsParent
+// cgroup so that they can be removed in order.
+func (h *Hierarchy) FindOrphans(osAdapter *os.Adapter) ([]*Orphan, error) {
+	var (
+		orphans []*Orphan
+		byPath  = make(map[string]*Orphan)
+	)
+
+	add := func(group string, jobID uuid.UUID, dir string) {
+		key := path.Join(group, jobID.String())
+
+		orphan, exists := byPath[key]
+		if !exists {
+			orphan = &Orphan{Group: group, JobID: jobID}
+			byPath[key] = orphan
+			orphans = append(orphans, orphan)
+		}
+		orphan.dirs = append(orphan.dirs, dir)
+	}
+
+	for _, jobsDir := range h.jobsDirs() {
+		groups, err := readSubdirs(osAdapter, jobsDir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, group := range groups {
+			groupDir := jobsDir + "/" + group
+
+			if jobID, ok := parseJobID(group); ok {
+				add("", jobID, groupDir)
+			}
+
+			jobs, err := readSubdirs(osAdapter, groupDir)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, job := range jobs {
+				if jobID, ok := parseJobID(job); ok {
+					add(group, jobID, groupDir+"/"+job)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(orphans, func(i, j int) bool {
+		return orphans[i].Group != "" && orphans[j].Group == ""
+	})
+
+	return orphans, nil
+}
+
+// RemoveOrphan kills the processes in the given orphan's cgroups and removes
+// the cgroups.  The processes are thawed first, since they are frozen if the
+// server terminated while it was killing them (see Kill).  The processes are
+// killed again before each attempt to remove the cgroups to catch any that
+// were forked in the meantime.  The killed processes are recorded in the
+// orphan's Pids.
+func (h *Hierarchy) RemoveOrphan(
+	osAdapter *os.Adapter,
+	syscallAdapter *syscall.Adapter,
+	orphan *Orphan,
+) error {
+	h.thawOrphan(osAdapter, orphan)
+
+	killed := make(map[int]bool)
+	remaining := orphan.dirs
+
+	for attempt := 1; ; attempt++ {
+		for _, pid := range readPids(osAdapter, remaining) {
+			err := syscallAdapter.Kill(pid, gosyscall.SIGKILL)
+			if err != nil && !errors.Is(err, gosyscall.ESRCH) {
+				return fmt.Errorf("failed to kill process %d of job %v: %w", pid, orphan.JobID, err)
+			}
+
+			if !killed[pid] {
+				killed[pid] = true
+				orphan.Pids = append(orphan.Pids, pid)
+			}
+		}
+
+		var failed []string
+		for _, dir := range remaining {
+			if err := osAdapter.Remove(dir); err != nil && !goos.IsNotExist(err) {
+				failed = append(failed, dir)
+			}
+		}
+		remaining = failed
+
+		if len(remaining) == 0 {
+			return nil
+		}
+
+		if attempt >= OrphanRemoveAttempts {
+			return fmt.Errorf("failed to remove cgroups: %s", strings.Join(remaining, ", "))
+		}
+
+		time.Sleep(OrphanRemoveInterval)
+	}
+}
+
+// thawOrphan thaws the processes in the given orphan's cgroups.  A v1 frozen
+// process cannot act on SIGKILL until it is thawed.  The job may have had no
+// freezer cgroup, so failures are ignored.
+func (h *Hierarchy) thawOrphan(osAdapter *os.Adapter, orphan *Orphan) {
+	freezerDir := path.Join(h.BasePath, "freezer") + "/"
+
+	for _, dir := range orphan.dirs {
+		switch {
+		case h.Version == V2:
+			filename := path.Join(dir, cgroupv2.FreezeFilename)
+			_ = osAdapter.WriteFile(filename, []byte("0"), os.FileMode(0644))
+
+		case strings.HasPrefix(dir, freezerDir):
+			filename := path.Join(dir, cgroupv1.FreezerStateFilename)
+			_ = osAdapter.WriteFile(filename, []byte(cgroupv1.FreezerStateThawed), os.FileMode(0644))
+		}
+	}
+}
+
+// jobsDirs returns the directories of the JobsParent cgroup; v1 has one in
+// each controller's hierarchy.
+func (h *Hierarchy) jobsDirs() []string {
+	if h.Version == V2 {
+		return []string{path.Join(h.BasePath, h.JobsParent)}
+	}
+
+	dirs := make([]string, 0, len(h.Controllers))
+	for _, controller := range h.Controllers {
+		dirs = append(dirs, path.Join(h.BasePath, controller, h.JobsParent))
+	}
+
+	return dirs
+}
+
+// readSubdirs returns the names of the subdirectories of the given
+// directory.  A directory that does not exist has no subdirectories.
+func readSubdirs(osAdapter *os.Adapter, dir string) ([]string, error) {
+	entries, err := osAdapter.ReadDir(dir)
+	if err != nil {
+		if goos.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+
+	return names, nil
+}
+
+// parseJobID returns the job ID that the given cgroup name represents, and
+// true, or false if the name is not that of a job cgroup.
+func parseJobID(name string) (uuid.UUID, bool) {
+	jobID, err := uuid.Parse(name)
+	if err != nil || jobID.String() != name {
+		return uuid.UUID{}, false
+	}
+
+	return jobID, true
+}
+
+// readPids returns the processes listed in the cgroup.procs files of the
+// given cgroup directories, without duplicates.  Files that cannot be read
+// are ignored.
+func readPids(osAdapter *os.Adapter, dirs []string) []int {
+	var pids []int
+	seen := make(map[int]bool)
+
+	for _, dir := range dirs {
+		content, err := osAdapter.ReadFile(dir + "/" + ProcsFilename)
+		if err != nil {
+			continue
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		for scanner.Scan() {
+			pid, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+			if err != nil || seen[pid] {
+				continue
+			}
+			seen[pid] = true
+			pids = append(pids, pid)
+		}
+	}
+
+	return pids
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroup_test
+
+import (
+	goos "os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os/ostest"
+	adaptsyscall "github.com/adalton/teleport-exercise/pkg/adaptation/syscall"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/syscall/syscalltest"
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	orphanJobID      = "3f8c2b6e-27c4-4c2d-9a64-6f0f0b3a1c11"
+	orphanGroupJobID = "9b1e7c3a-5d2f-4e8b-8c6a-2a4d9e0f7b22"
+)
+
+// makeCgroup creates the directory at the given path below dir with a
+// cgroup.procs file with the given content.
+func makeCgroup(t *testing.T, dir, path, procs string) {
+	full := filepath.Join(dir, path)
+
+	require.Nil(t, goos.MkdirAll(full, 0755))
+	require.Nil(t, goos.WriteFile(filepath.Join(full, cgroup.ProcsFilename), []byte(procs), 0644))
+}
+
+func Test_Hierarchy_FindOrphans_V2(t *testing.T) {
+	base := t.TempDir()
+	makeCgroup(t, base, "jobs/"+orphanJobID, "11\n12\n")
+	makeCgroup(t, base, "jobs/user1", "")
+	makeCgroup(t, base, "jobs/user1/"+orphanGroupJobID, "13\n")
+	makeCgroup(t, base, "jobs/user1/not-a-job", "")
+	makeCgroup(t, base, "jobmanager", "1\n")
+
+	hierarchy := &cgroup.Hierarchy{Version: cgroup.V2, BasePath: base, JobsParent: "jobs"}
+
+	orphans, err := hierarchy.FindOrphans(nil)
+
+	require.Nil(t, err)
+	require.Equal(t, 2, len(orphans))
+	assert.Equal(t, "user1", orphans[0].Group)
+	assert.Equal(t, orphanGroupJobID, orphans[0].JobID.String())
+	assert.Equal(t, "", orphans[1].Group)
+	assert.Equal(t, orphanJobID, orphans[1].JobID.String())
+}
+
+func Test_Hierarchy_FindOrphans_NoJobsParent(t *testing.T) {
+	hierarchy := &cgroup.Hierarchy{Version: cgroup.V2, BasePath: t.TempDir(), JobsParent: "jobs"}
+
+	orphans, err := hierarchy.FindOrphans(nil)
+
+	assert.Nil(t, err)
+	assert.Nil(t, orphans)
+}
+
+func Test_Hierarchy_RemoveOrphan_V1(t *testing.T) {
+	base := t.TempDir()
+	makeCgroup(t, base, "cpu/jobs/user1/"+orphanGroupJobID, "21\n22\n")
+	makeCgroup(t, base, "memory/jobs/user1/"+orphanGroupJobID, "22\n21\n")
+
+	hierarchy := &cgroup.Hierarchy{
+		Version:     cgroup.V1,
+		BasePath:    base,
+		Controllers: []string{"cpu", "memory"},
+		JobsParent:  "jobs",
+	}
+
+	orphans, err := hierarchy.FindOrphans(nil)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(orphans))
+
+	removeMock := &ostest.RemoveMock{}
+	killMock := &syscalltest.KillMock{}
+
+	err = hierarchy.RemoveOrphan(
+		&os.Adapter{RemoveFn: removeMock.Remove},
+		&adaptsyscall.Adapter{KillFn: killMock.Kill},
+		orphans[0],
+	)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []int{21, 22}, orphans[0].Pids)
+	require.Equal(t, 2, len(killMock.Events))
+	assert.Equal(t, syscall.SIGKILL, killMock.Events[0].Signal)
+	require.Equal(t, 2, len(removeMock.Events))
+	assert.Equal(t, filepath.Join(base, "cpu/jobs/user1", orphanGroupJobID), removeMock.Events[0].Path)
+	assert.Equal(t, filepath.Join(base, "memory/jobs/user1", orphanGroupJobID), removeMock.Events[1].Path)
+}
+
+func Test_Hierarchy_RemoveOrphan_Frozen_V1(t *testing.T) {
+	base := t.TempDir()
+	makeCgroup(t, base, "freezer/jobs/"+orphanJobID, "51\n")
+	makeCgroup(t, base, "memory/jobs/"+orphanJobID, "51\n")
+
+	hierarchy := &cgroup.Hierarchy{
+		Version:     cgroup.V1,
+		BasePath:    base,
+		Controllers: []string{"freezer", "memory"},
+		JobsParent:  "jobs",
+	}
+
+	orphans, err := hierarchy.FindOrphans(nil)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(orphans))
+
+	// The server terminated while the job's processes were frozen
+	var events []string
+	osAdapter := &os.Adapter{
+		WriteFileFn: func(name string, data []byte, perm os.FileMode) error {
+			events = append(events, "write "+name+" "+string(data))
+			return nil
+		},
+		RemoveFn: (&ostest.RemoveMock{}).Remove,
+	}
+	syscallAdapter := &adaptsyscall.Adapter{
+		KillFn: func(pid int, signal syscall.Signal) error {
+			events = append(events, "kill")
+			return nil
+		},
+	}
+
+	err = hierarchy.RemoveOrphan(osAdapter, syscallAdapter, orphans[0])
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"write " + filepath.Join(base, "freezer/jobs", orphanJobID, "freezer.state") + " THAWED",
+		"kill",
+	}, events)
+}
+
+func Test_Hierarchy_RemoveOrphan_ProcessGone(t *testing.T) {
+	base := t.TempDir()
+	makeCgroup(t, base, "jobs/"+orphanJobID, "31\n")
+
+	hierarchy := &cgroup.Hierarchy{Version: cgroup.V2, BasePath: base, JobsParent: "jobs"}
+
+	orphans, err := hierarchy.FindOrphans(nil)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(orphans))
+
+	removeMock := &ostest.RemoveMock{}
+	killMock := &syscalltest.KillMock{NextError: syscall.ESRCH}
+
+	err = hierarchy.RemoveOrphan(
+		&os.Adapter{RemoveFn: removeMock.Remove},
+		&adaptsyscall.Adapter{KillFn: killMock.Kill},
+		orphans[0],
+	)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(removeMock.Events))
+}
+
+func Test_Hierarchy_RemoveOrphan_Busy(t *testing.T) {
+	attempts, interval := cgroup.OrphanRemoveAttempts, cgroup.OrphanRemoveInterval
+	cgroup.OrphanRemoveAttempts, cgroup.OrphanRemoveInterval = 3, 0
+	t.Cleanup(func() {
+		cgroup.OrphanRemoveAttempts, cgroup.OrphanRemoveInterval = attempts, interval
+	})
+
+	base := t.TempDir()
+	makeCgroup(t, base, "jobs/"+orphanJobID, "41\n")
+
+	hierarchy := &cgroup.Hierarchy{Version: cgroup.V2, BasePath: base, JobsParent: "jobs"}
+
+	orphans, err := hierarchy.FindOrphans(nil)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(orphans))
+
+	removeMock := &ostest.RemoveMock{NextError: syscall.EBUSY}
+	killMock := &syscalltest.KillMock{}
+
+	err = hierarchy.RemoveOrphan(
+		&os.Adapter{RemoveFn: removeMock.Remove},
+		&adaptsyscall.Adapter{KillFn: killMock.Kill},
+		orphans[0],
+	)
+
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), orphanJobID)
+	}
+	assert.Equal(t, 3, len(removeMock.Events))
+	assert.Equal(t, 3, len(killMock.Events))
+	assert.Equal(t, []int{41}, orphans[0].Pids)
+}
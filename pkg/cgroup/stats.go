@@ -0,0 +1,177 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroup
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+const (
+	// PidsCurrentFilename is the name of the pids controller's current
+	// count file in both versions of the interface.
+	PidsCurrentFilename = "pids.current"
+
+	// PidsPeakFilename is the name of the pids controller's peak count
+	// file.  It is available only on recent kernels.
+	PidsPeakFilename = "pids.peak"
+
+	v1CpuacctUsageFilename        = "cpuacct.usage"
+	v1MemoryUsageFilename         = "memory.usage_in_bytes"
+	v1BlkioIoServiceBytesFilename = "blkio.throttle.io_service_bytes"
+	v2CpuStatFilename             = "cpu.stat"
+	v2MemoryCurrentFilename       = "memory.current"
+	v2IoStatFilename              = "io.stat"
+)
+
+// Stats describes the resource usage of the cgroups in a Set.  Usage that
+// the set does not account for (e.g., because the set does not include the
+// corresponding controller) is zero.
+type Stats struct {
+	// CpuUsageNs is the total CPU time consumed, in nanoseconds.
+	CpuUsageNs uint64
+
+	// MemoryUsage and PeakMemoryUsage are the current and maximum memory
+	// usage, in bytes.
+	MemoryUsage     uint64
+	PeakMemoryUsage uint64
+
+	// IoReadBytes and IoWriteBytes are the number of bytes read from and
+	// written to all block devices.
+	IoReadBytes  uint64
+	IoWriteBytes uint64
+
+	// Pids is the number of processes and threads, and PeakPids is the
+	// maximum number that has existed at any one time.  PeakPids is zero if
+	// the kernel does not report it.
+	Pids     uint64
+	PeakPids uint64
+}
+
+// Stats reads the resource usage of the given set's cgroups.  The set must
+// belong to this hierarchy and its cgroups must exist.
+//
+// For v1, CPU usage is read from the cpuacct.usage file in the cpu
+// controller's cgroup, which relies on the cpu and cpuacct controllers being
+// mounted together, as they are on all common distributions.
+func (h *Hierarchy) Stats(set Set) *Stats {
+	stats := &Stats{}
+
+	if h.Version == V1 {
+		stats.CpuUsageNs = readUint(set, "cpu", v1CpuacctUsageFilename)
+		stats.MemoryUsage = readUint(set, "memory", v1MemoryUsageFilename)
+
+		if content, err := set.ReadFile("blkio", v1BlkioIoServiceBytesFilename); err == nil {
+			stats.IoReadBytes, stats.IoWriteBytes = parseV1IoServiceBytes(content)
+		}
+	} else {
+		if content, err := set.ReadFile("cpu", v2CpuStatFilename); err == nil {
+			stats.CpuUsageNs = ParseEvents(content)["usage_usec"] * 1000
+		}
+
+		stats.MemoryUsage = readUint(set, "memory", v2MemoryCurrentFilename)
+
+		if content, err := set.ReadFile("io", v2IoStatFilename); err == nil {
+			stats.IoReadBytes, stats.IoWriteBytes = parseV2IoStat(content)
+		}
+	}
+
+	if peak, err := h.PeakMemoryUsage(set); err == nil {
+		stats.PeakMemoryUsage = peak
+	}
+
+	stats.Pids = readUint(set, "pids", PidsCurrentFilename)
+	stats.PeakPids = readUint(set, "pids", PidsPeakFilename)
+
+	return stats
+}
+
+// readUint returns the single unsigned integer value in the named file of
+// the given controller's cgroup, or 0 if the value cannot be read.
+func readUint(set Set, controllerName, filename string) uint64 {
+	content, err := set.ReadFile(controllerName, filename)
+	if err != nil {
+		return 0
+	}
+
+	value, err := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return value
+}
+
+// parseV1IoServiceBytes sums the Read and Write counts of every device in the
+// content of a v1 blkio.throttle.io_service_bytes file, in which each line
+// has the form "<major>:<minor> <operation> <bytes>".
+func parseV1IoServiceBytes(content []byte) (read, write uint64) {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+
+		count, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		switch fields[1] {
+		case "Read":
+			read += count
+		case "Write":
+			write += count
+		}
+	}
+
+	return read, write
+}
+
+// parseV2IoStat sums the rbytes and wbytes counts of every device in the
+// content of a v2 io.stat file, in which each line has the form
+// "<major>:<minor> rbytes=<n> wbytes=<n> ...".
+func parseV2IoStat(content []byte) (read, write uint64) {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		for _, field := range fields[1:] {
+			key, value, found := strings.Cut(field, "=")
+			if !found {
+				continue
+			}
+
+			count, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				continue
+			}
+
+			switch key {
+			case "rbytes":
+				read += count
+			case "wbytes":
+				write += count
+			}
+		}
+	}
+
+	return read, write
+}
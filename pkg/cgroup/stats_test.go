@@ -0,0 +1,77 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroup_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os/ostest"
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Hierarchy_Stats_V1(t *testing.T) {
+	jobDir := func(controllerName string) string {
+		return fmt.Sprintf("/cg/%s/jobs/%s", controllerName, memoryTestJobID)
+	}
+
+	readFileMock := &ostest.ReadFileMock{
+		Files: map[string]string{
+			jobDir("cpu") + "/cpuacct.usage":                     "2500000000\n",
+			jobDir("memory") + "/memory.usage_in_bytes":          "1048576\n",
+			jobDir("memory") + "/memory.max_usage_in_bytes":      "2097152\n",
+			jobDir("pids") + "/pids.current":                     "4\n",
+			jobDir("pids") + "/pids.peak":                        "9\n",
+			jobDir("blkio") + "/blkio.throttle.io_service_bytes": "8:0 Read 4096\n8:0 Write 100\n8:0 Sync 4196\n8:16 Read 1024\n8:16 Write 0\nTotal 5220\n",
+		},
+	}
+
+	hierarchy := &cgroup.Hierarchy{Version: cgroup.V1, BasePath: "/cg", JobsParent: "jobs"}
+	set := newMemoryTestSet(hierarchy, readFileMock)
+
+	assert.Equal(t, &cgroup.Stats{
+		CpuUsageNs:      2500000000,
+		MemoryUsage:     1048576,
+		PeakMemoryUsage: 2097152,
+		IoReadBytes:     5120,
+		IoWriteBytes:    100,
+		Pids:            4,
+		PeakPids:        9,
+	}, hierarchy.Stats(set))
+}
+
+func Test_Hierarchy_Stats_V2(t *testing.T) {
+	jobDir := fmt.Sprintf("/cg/jobs/%s", memoryTestJobID)
+
+	readFileMock := &ostest.ReadFileMock{
+		Files: map[string]string{
+			jobDir + "/cpu.stat":       "usage_usec 2500\nuser_usec 2000\nsystem_usec 500\n",
+			jobDir + "/memory.current": "1048576\n",
+			jobDir + "/io.stat":        "8:0 rbytes=4096 wbytes=100 rios=1 wios=1 dbytes=0 dios=0\n8:16 rbytes=1024 wbytes=0\n",
+		},
+	}
+
+	hierarchy := &cgroup.Hierarchy{Version: cgroup.V2, BasePath: "/cg", JobsParent: "jobs"}
+	set := newMemoryTestSet(hierarchy, readFileMock)
+
+	// memory.peak and the pids files are missing, so they are reported as zero
+	assert.Equal(t, &cgroup.Stats{
+		CpuUsageNs:   2500000,
+		MemoryUsage:  1048576,
+		IoReadBytes:  5120,
+		IoWriteBytes: 100,
+	}, hierarchy.Stats(set))
+}
@@ -18,9 +18,12 @@ import (
 	"errors"
 	"io"
 	"syscall"
+	"time"
 
 	"github.com/adalton/teleport-exercise/certs"
+	"github.com/adalton/teleport-exercise/pkg/config"
 	"github.com/adalton/teleport-exercise/pkg/jobmanager"
+	"github.com/adalton/teleport-exercise/pkg/quantity"
 	"github.com/adalton/teleport-exercise/service/jobmanager/jobmanagerv1"
 
 	"google.golang.org/grpc"
@@ -29,6 +32,91 @@ import (
 // JobStatus models the current status of a job.
 type JobStatus = jobmanager.JobStatus
 
+// JobStats describes the resource usage of a running job.
+type JobStats = jobmanager.JobStats
+
+// Process describes a process in a running job's cgroups.
+type Process = jobmanager.Process
+
+// ExecStatus describes how a process started by Exec terminated.
+type ExecStatus = jobmanager.ExecStatus
+
+// ResourceUsage summarizes the resources that a terminated job consumed.
+type ResourceUsage = jobmanager.ResourceUsage
+
+// ServerInfo describes the resource enforcement that the server applies to jobs.
+type ServerInfo = jobmanager.ServerInfo
+
+// BlockIOLimit describes the IO throttling limits for the block device that
+// holds Path.
+type BlockIOLimit = config.BlkioLimit
+
+// CgroupKnob is a value to write to a cgroup interface file of a job.
+type CgroupKnob = config.CgroupKnob
+
+// JobState is the stage of its lifecycle that a job has reached.
+type JobState = jobmanager.JobState
+
+// TerminationReason describes why a job terminated.
+type TerminationReason = jobmanager.TerminationReason
+
+// OomPolicy describes what happens to a job that reaches its memory limit.
+type OomPolicy = jobmanager.OomPolicy
+
+// JobLimits describes the resource limits requested for a job.  Limits that
+// are not specified (zero) take the server's default values.  See the
+// quantity package for parsing sizes and rates.
+type JobLimits struct {
+	BlockIO         []BlockIOLimit
+	MaxPids         uint64
+	CpusetCpus      string
+	CpusetMems      string
+	Cpus            float64
+	UnlimitedCpus   bool
+	CpuWeight       uint64
+	CpuPeriodUs     uint64
+	MemoryLimit     quantity.Bytes
+	MemorySwapLimit quantity.Bytes
+	MemorySoftLimit quantity.Bytes
+	OomPolicy       OomPolicy
+	Knobs           []CgroupKnob
+}
+
+// ParseOomPolicy returns the OomPolicy with the given name, either "kill" or
+// "pause".  An empty name selects the server's default policy.
+func ParseOomPolicy(name string) (OomPolicy, error) {
+	return jobmanager.ParseOomPolicy(name)
+}
+
+// ParseSize parses the value of the named size limit, in bytes with an
+// optional K, M, G or T suffix (e.g., "512M").  An empty value is zero.
+func ParseSize(name, value string) (quantity.Bytes, error) {
+	size, err := jobmanager.ParseSize(name, value)
+
+	return quantity.Bytes(size), err
+}
+
+// ParseByteRate parses the value of the named limit in bytes per second, in
+// the same format as ParseSize with an optional "/s" (e.g., "40M/s").  An
+// empty value is zero.
+func ParseByteRate(name, value string) (quantity.Bytes, error) {
+	rate, err := jobmanager.ParseByteRate(name, value)
+
+	return quantity.Bytes(rate), err
+}
+
+// ParseRate parses the value of the named limit in operations per second
+// (e.g., "100/s").  An empty value is zero.
+func ParseRate(name, value string) (uint64, error) {
+	return jobmanager.ParseRate(name, value)
+}
+
+// ParseCpus parses the value of the named limit in CPUs (e.g., "1.5" or
+// "1500m").  An empty value is zero.
+func ParseCpus(name, value string) (float64, error) {
+	return jobmanager.ParseCpus(name, value)
+}
+
 // Superuser is the name of the user who can access any job.
 const Superuser = jobmanager.Superuser
 
@@ -37,6 +125,12 @@ const Superuser = jobmanager.Superuser
 type Client struct {
 	jm   jobmanagerv1.JobManagerClient
 	conn *grpc.ClientConn
+
+	// OnTruncation, if set, is called while streaming output each time the
+	// server reports that it discarded the given number of bytes of output
+	// before they could be streamed.  The output written after the call
+	// resumes after the discarded bytes.
+	OnTruncation func(truncated uint64)
 }
 
 // NewClient creates a new JobManager client using the given user's credentials
@@ -63,10 +157,14 @@ func NewClient(userID, hostPort string) (*Client, error) {
 	}, nil
 }
 
-// Start invokes an RPC on the JobManager server to start a new job.
+// Start invokes an RPC on the JobManager server to start a new job.  The
+// given limits, if non-nil, override the server's default resource limits
+// for the job.
 func (c *Client) Start(
 	ctx context.Context,
-	jobName, programPath string,
+	jobName string,
+	limits *JobLimits,
+	programPath string,
 	programArgs ...string,
 ) (jobID string, err error) {
 
@@ -74,6 +172,31 @@ func (c *Client) Start(
 		Name:        jobName,
 		ProgramPath: programPath,
 		Arguments:   programArgs,
+		Limits:      jobLimitsLocalToRpc(limits),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return job.Id.Id, nil
+}
+
+// Create invokes an RPC on the JobManager server to create a new job without
+// starting it.  The job is started with Launch.  The given limits, if
+// non-nil, override the server's default resource limits for the job.
+func (c *Client) Create(
+	ctx context.Context,
+	jobName string,
+	limits *JobLimits,
+	programPath string,
+	programArgs ...string,
+) (jobID string, err error) {
+
+	job, err := c.jm.Create(ctx, &jobmanagerv1.JobCreationRequest{
+		Name:        jobName,
+		ProgramPath: programPath,
+		Arguments:   programArgs,
+		Limits:      jobLimitsLocalToRpc(limits),
 	})
 	if err != nil {
 		return "", err
@@ -82,6 +205,14 @@ func (c *Client) Start(
 	return job.Id.Id, nil
 }
 
+// Launch invokes an RPC on the JobManager server to start the created job
+// with the given jobID.
+func (c *Client) Launch(ctx context.Context, jobID string) error {
+	_, err := c.jm.Launch(ctx, &jobmanagerv1.JobID{Id: jobID})
+
+	return err
+}
+
 // Stop invokes an RPC on the JobManager to stop a job.  If the job with the
 // given jobID isn't running, this operation does nothing.
 func (c *Client) Stop(ctx context.Context, jobID string) error {
@@ -90,6 +221,14 @@ func (c *Client) Stop(ctx context.Context, jobID string) error {
 	return err
 }
 
+// Remove invokes an RPC on the JobManager server to remove the terminated job
+// with the given jobID and discard its output.
+func (c *Client) Remove(ctx context.Context, jobID string) error {
+	_, err := c.jm.Remove(ctx, &jobmanagerv1.JobID{Id: jobID})
+
+	return err
+}
+
 // Query invokes an RPC on the JobManager server to retrieve the current status
 // of the job with the given jobID.
 func (c *Client) Query(ctx context.Context, jobID string) (*JobStatus, error) {
@@ -101,6 +240,48 @@ func (c *Client) Query(ctx context.Context, jobID string) (*JobStatus, error) {
 	return jobStatusRpcToLocal(jobStatus), nil
 }
 
+// Stats invokes an RPC on the JobManager server to retrieve the current
+// resource usage of the running job with the given jobID.
+func (c *Client) Stats(ctx context.Context, jobID string) (*JobStats, error) {
+	jobStats, err := c.jm.Stats(ctx, &jobmanagerv1.JobID{Id: jobID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &JobStats{
+		CpuUsage:        time.Duration(jobStats.CpuUsageNs),
+		MemoryUsage:     jobStats.MemoryUsage,
+		PeakMemoryUsage: jobStats.PeakMemoryUsage,
+		IoReadBytes:     jobStats.IoReadBytes,
+		IoWriteBytes:    jobStats.IoWriteBytes,
+		Pids:            jobStats.Pids,
+	}, nil
+}
+
+// Processes invokes an RPC on the JobManager server to retrieve the list of
+// processes in the cgroups of the running job with the given jobID.
+func (c *Client) Processes(ctx context.Context, jobID string) ([]*Process, error) {
+	processList, err := c.jm.Processes(ctx, &jobmanagerv1.JobID{Id: jobID})
+	if err != nil {
+		return nil, err
+	}
+
+	processes := make([]*Process, 0, len(processList.Processes))
+
+	for _, process := range processList.Processes {
+		processes = append(processes, &Process{
+			Pid:     int(process.Pid),
+			Ppid:    int(process.Ppid),
+			Command: process.Command,
+			State:   process.State,
+			Rss:     process.Rss,
+			CpuTime: time.Duration(process.CpuTimeNs),
+		})
+	}
+
+	return processes, nil
+}
+
 // Query invokes an RPC on the JobManager server to retrieve the list of jobs
 // started by the user.  If the user is the administrator, then it returns a
 // list of all jobs in the system.
@@ -119,6 +300,20 @@ func (c *Client) List(ctx context.Context) ([]*JobStatus, error) {
 	return retList, nil
 }
 
+// Info invokes an RPC on the JobManager server to retrieve information about
+// the server, including the resource enforcement it applies to jobs.
+func (c *Client) Info(ctx context.Context) (*ServerInfo, error) {
+	info, err := c.jm.Info(ctx, &jobmanagerv1.NilMessage{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ServerInfo{
+		CgroupVersion: info.CgroupVersion,
+		Capabilities:  info.Capabilities,
+	}, nil
+}
+
 // StreamStdout invokes an RPC on the JobManager server to stream the standard
 // output of the job with the given jobID.  This function will block until either
 // (1) the context is interrupted, or (2) the job completes.
@@ -138,6 +333,59 @@ func (c *Client) Close() error {
 	return c.conn.Close()
 }
 
+// Exec invokes an RPC on the JobManager server to run the given program in
+// the namespaces and cgroups of the running job with the given jobID.  It
+// writes the program's standard output and standard error to stdout and
+// stderr until the program terminates, then returns its status.  Canceling
+// the given context kills the program.
+func (c *Client) Exec(
+	ctx context.Context,
+	jobID string,
+	stdout, stderr io.Writer,
+	programPath string,
+	programArgs ...string,
+) (*ExecStatus, error) {
+
+	grpcStream, err := c.jm.Exec(ctx, &jobmanagerv1.ExecRequest{
+		JobID:       &jobmanagerv1.JobID{Id: jobID},
+		ProgramPath: programPath,
+		Arguments:   programArgs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		output, err := grpcStream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil, errors.New("exec ended without an exit status")
+			}
+			return nil, err
+		}
+
+		if exitStatus := output.ExitStatus; exitStatus != nil {
+			status := &ExecStatus{ExitCode: int(exitStatus.ExitCode)}
+			if exitStatus.ErrorMessage != "" {
+				status.RunError = errors.New(exitStatus.ErrorMessage)
+			}
+
+			return status, nil
+		}
+
+		out := stdout
+		if output.OutputStream == jobmanagerv1.OutputStream_OutputStream_STDERR {
+			out = stderr
+		}
+
+		c.reportTruncation(output.TruncatedBytes)
+
+		if _, err := out.Write(output.Output); err != nil {
+			return nil, err
+		}
+	}
+}
+
 func jobStatusRpcToLocal(jobStatus *jobmanagerv1.JobStatus) *JobStatus {
 	var runError error
 	if jobStatus.ErrorMessage != "" {
@@ -150,9 +398,32 @@ func jobStatusRpcToLocal(jobStatus *jobmanagerv1.JobStatus) *JobStatus {
 		ID:        jobStatus.Job.Id.Id,
 		Running:   jobStatus.IsRunning,
 		Pid:       int(jobStatus.Pid),
+		State:     jobmanager.JobState(jobStatus.State),
 		ExitCode:  int(jobStatus.ExitCode),
 		SignalNum: syscall.Signal(jobStatus.SignalNumber),
 		RunError:  runError,
+
+		PidsLimitReached: jobStatus.PidsLimitReached,
+
+		TerminationReason: jobmanager.TerminationReason(jobStatus.TerminationReason),
+		OomKills:          jobStatus.OomKills,
+		PeakMemoryUsage:   jobStatus.PeakMemoryUsage,
+		Usage:             resourceUsageRpcToLocal(jobStatus.Usage),
+	}
+}
+
+func resourceUsageRpcToLocal(usage *jobmanagerv1.ResourceUsage) *ResourceUsage {
+	if usage == nil {
+		return nil
+	}
+
+	return &ResourceUsage{
+		UserTime:        time.Duration(usage.UserTimeNs),
+		SystemTime:      time.Duration(usage.SystemTimeNs),
+		PeakMemoryUsage: usage.PeakMemoryUsage,
+		IoReadBytes:     usage.IoReadBytes,
+		IoWriteBytes:    usage.IoWriteBytes,
+		MaxPids:         usage.MaxPids,
 	}
 }
 
@@ -180,6 +451,8 @@ func (c *Client) stream(
 			return err
 		}
 
+		c.reportTruncation(output.TruncatedBytes)
+
 		_, err = out.Write(output.Output)
 		if err != nil {
 			return err
@@ -188,3 +461,68 @@ func (c *Client) stream(
 
 	return nil
 }
+
+// reportTruncation calls OnTruncation, if it is set, if the given number of
+// truncated bytes is non-zero.
+func (c *Client) reportTruncation(truncated uint64) {
+	if truncated > 0 && c.OnTruncation != nil {
+		c.OnTruncation(truncated)
+	}
+}
+
+// formatQuantity returns the given quantity in the form that the server
+// expects.  A zero quantity is empty, which keeps the server's default.
+func formatQuantity(value quantity.Bytes) string {
+	if value == 0 {
+		return ""
+	}
+
+	return value.String()
+}
+
+func jobLimitsLocalToRpc(limits *JobLimits) *jobmanagerv1.ResourceLimits {
+	if limits == nil {
+		return nil
+	}
+
+	rpcLimits := &jobmanagerv1.ResourceLimits{
+		MaxPids:       limits.MaxPids,
+		CpusetCpus:    limits.CpusetCpus,
+		CpusetMems:    limits.CpusetMems,
+		Cpus:          limits.Cpus,
+		UnlimitedCpus: limits.UnlimitedCpus,
+		CpuWeight:     limits.CpuWeight,
+		CpuPeriodUs:   limits.CpuPeriodUs,
+
+		MemoryLimit:     formatQuantity(limits.MemoryLimit),
+		MemorySwapLimit: formatQuantity(limits.MemorySwapLimit),
+		MemorySoftLimit: formatQuantity(limits.MemorySoftLimit),
+	}
+
+	switch limits.OomPolicy {
+	case jobmanager.OomPolicyKill:
+		rpcLimits.OomPolicy = jobmanagerv1.OomPolicy_OomPolicy_KILL
+
+	case jobmanager.OomPolicyPause:
+		rpcLimits.OomPolicy = jobmanagerv1.OomPolicy_OomPolicy_PAUSE
+	}
+
+	for _, limit := range limits.BlockIO {
+		rpcLimits.BlockIO = append(rpcLimits.BlockIO, &jobmanagerv1.BlockIOLimit{
+			Path:      limit.Path,
+			ReadBps:   formatQuantity(limit.ReadBps),
+			WriteBps:  formatQuantity(limit.WriteBps),
+			ReadIops:  limit.ReadIops,
+			WriteIops: limit.WriteIops,
+		})
+	}
+
+	for _, knob := range limits.Knobs {
+		rpcLimits.Knobs = append(rpcLimits.Knobs, &jobmanagerv1.CgroupKnob{
+			File:  knob.File,
+			Value: knob.Value,
+		})
+	}
+
+	return rpcLimits
+}
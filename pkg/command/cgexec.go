@@ -14,12 +14,90 @@ limitations under the License.
 package command
 
 import (
+	"errors"
 	"fmt"
+	goos "os"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	gosyscall "syscall"
 
 	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
 	"github.com/adalton/teleport-exercise/pkg/adaptation/syscall"
+	"github.com/adalton/teleport-exercise/pkg/config"
+	"github.com/adalton/teleport-exercise/pkg/startstatus"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// JoinOption is the prefix of the option that directs Cgexec to run the
+	// command in the namespaces of the process with the given PID (e.g.,
+	// "--join=1234").
+	JoinOption = "--join="
+
+	// InitOption directs Cgexec to act as the init process of the command's
+	// PID namespace rather than exec-ing the command.
+	InitOption = "--init"
+
+	// StatusFdOption is the prefix of the option that directs Cgexec to
+	// report whether it started the command over the status pipe with the
+	// given file descriptor (e.g., "--status-fd=3"); see the startstatus
+	// package.
+	StatusFdOption = "--status-fd="
+
+	// OomScoreAdjOption is the prefix of the option that directs Cgexec to
+	// set its oom_score_adj, which the command inherits, to the given value
+	// before it starts the command (e.g., "--oom-score-adj=0").
+	OomScoreAdjOption = "--oom-score-adj="
+
+	// OomScoreAdjFilenameFormat is the format of the name of the file
+	// through which the OOM killer's preference for a process, given by pid
+	// or "self", is set.  Cgexec, as an init process, exempts itself from
+	// the OOM killer; see OomScoreAdjNeverKill.
+	OomScoreAdjFilenameFormat = "/proc/%v/oom_score_adj"
+
+	// OomScoreAdjNeverKill is the oom_score_adj value that exempts a
+	// process from the OOM killer.
+	OomScoreAdjNeverKill = "-1000"
 )
 
+// joinNamespaces lists the namespaces that "--join" enters.  The mount
+// namespace is entered last, since doing so changes the view of /proc.
+var joinNamespaces = []struct {
+	name   string
+	nstype int
+}{
+	{"net", unix.CLONE_NEWNET},
+	{"pid", unix.CLONE_NEWPID},
+	{"mnt", unix.CLONE_NEWNS},
+}
+
+// initForwardedSignals lists the signals that Cgexec forwards to the command
+// in init mode (see InitOption).
+var initForwardedSignals = []goos.Signal{
+	gosyscall.SIGHUP,
+	gosyscall.SIGINT,
+	gosyscall.SIGQUIT,
+	gosyscall.SIGTERM,
+	gosyscall.SIGUSR1,
+	gosyscall.SIGUSR2,
+	gosyscall.SIGWINCH,
+	gosyscall.SIGCONT,
+}
+
+// ExitError is returned by Cgexec when it ran the command as a child process
+// (see JoinOption and InitOption) rather than exec-ing it.  Code is the exit status of the
+// command, or 128 plus the number of the signal that killed it.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
 // Cgexec adds the current process to 0 or more specified cgroups, then
 // execs the specfied command.  The format of args is:
 //
@@ -27,12 +105,53 @@ import (
 //     args[n:n+1] - --
 //     args[n+2:]  - command to exec and its arguments
 //
+// If the first cgroup file is instead "--join=<pid>", Cgexec also enters the
+// network, PID and mount namespaces of the process with the given pid.  A
+// process cannot enter a PID namespace itself, so Cgexec then runs the
+// command as its child, waits for it to terminate, and returns an ExitError
+// that reports its exit status.  If Cgexec receives SIGTERM, SIGINT or SIGHUP
+// in the meantime, it kills the command.
+//
+// If the first cgroup file is instead "--init", Cgexec runs the command as
+// its child and acts as the init process of its PID namespace: it reaps every
+// process that terminates in the namespace, forwards SIGHUP, SIGINT, SIGQUIT,
+// SIGTERM, SIGUSR1, SIGUSR2, SIGWINCH and SIGCONT to the command, and returns
+// an ExitError that reports the command's exit status once it terminates.
+//
+// Options, which precede the cgroup files, may be combined, except that
+// "--join" and "--init" are mutually exclusive.  If the "--status-fd=<fd>"
+// option is given, Cgexec reports to the server whether it started the
+// command over that file descriptor, which is closed when the command starts.
+// If the "--oom-score-adj=<score>" option is given, Cgexec sets its
+// oom_score_adj to score before it starts the command.
+//
+// Cgexec adds the calling thread to the cgroups and starts the command from
+// that thread, so the caller must have locked its goroutine to its thread
+// (see runtime.LockOSThread).
+//
 // It returns an error if it failed to add itself to the requested cgroups
 // or if it fails to exec the command.
 func Cgexec(args []string) error {
 	return CgexecDetailed(args, nil, nil)
 }
 
+// CgexecMain runs Cgexec with the given arguments on behalf of an application
+// and exits with the application's exit status: that of the command if Cgexec
+// ran it as a child, or 1 if Cgexec failed.  It does not return.
+func CgexecMain(args []string) {
+	if err := Cgexec(args); err != nil {
+		var exitErr *ExitError
+		if errors.As(err, &exitErr) {
+			goos.Exit(exitErr.Code)
+		}
+
+		fmt.Fprintf(goos.Stderr, "cgexec failed: %v\n", err)
+	}
+
+	// Cgexec shouldn't return in a non-error case
+	goos.Exit(1)
+}
+
 // CgexecDetailed is wrapped by Cgexec and performs the same operation,
 // optionally with concrete os and syscall adapters.
 func CgexecDetailed(args []string, osa *os.Adapter, sa *syscall.Adapter) error {
@@ -60,17 +179,306 @@ func CgexecDetailed(args []string, osa *os.Adapter, sa *syscall.Adapter) error {
 		return fmt.Errorf("cgexec: no command provided")
 	}
 
-	pid := fmt.Sprintf("%d", osa.Getpid())
+	var (
+		joinPid     int
+		asInit      bool
+		statusFd    = -1
+		oomScoreAdj string
+		optionErr   error
+	)
+
+	// Cgroup files are absolute paths, so options are easily distinguished
+	for len(taskFileList) > 0 && strings.HasPrefix(taskFileList[0], "--") {
+		option := taskFileList[0]
+		taskFileList = taskFileList[1:]
+
+		switch {
+		case strings.HasPrefix(option, JoinOption):
+			value := strings.TrimPrefix(option, JoinOption)
+
+			var err error
+			if joinPid, err = strconv.Atoi(value); err != nil || joinPid <= 0 {
+				optionErr = fmt.Errorf("invalid pid '%s'", value)
+			}
+
+		case option == InitOption:
+			asInit = true
+
+		case strings.HasPrefix(option, StatusFdOption):
+			value := strings.TrimPrefix(option, StatusFdOption)
+
+			var err error
+			if statusFd, err = strconv.Atoi(value); err != nil || statusFd < 0 {
+				return fmt.Errorf("cgexec: invalid status fd '%s'", value)
+			}
+
+		case strings.HasPrefix(option, OomScoreAdjOption):
+			oomScoreAdj = strings.TrimPrefix(option, OomScoreAdjOption)
+
+			if _, err := strconv.Atoi(oomScoreAdj); err != nil {
+				optionErr = fmt.Errorf("invalid OOM score adjustment '%s'", oomScoreAdj)
+			}
+
+		default:
+			optionErr = fmt.Errorf("unknown option '%s'", option)
+		}
+	}
+
+	if joinPid != 0 && asInit {
+		optionErr = fmt.Errorf("%s and %s cannot be combined", JoinOption, InitOption)
+	}
+
+	status := openStatusPipe(statusFd)
+	if optionErr != nil {
+		_ = status.failed(startstatus.NewError("parse arguments", "", optionErr))
+		return fmt.Errorf("cgexec: %w", optionErr)
+	}
+
+	// Join the cgroups first; their paths are those of our mount namespace.
+	// Writing our thread's ID moves this thread to cgroup v1 "tasks" files,
+	// and our whole process to "cgroup.procs" files.
+	tid := fmt.Sprintf("%d", sa.Gettid())
 	for _, taskFile := range taskFileList {
-		if err := osa.WriteFile(taskFile, []byte(pid), DefaultPerms); err != nil {
+		if err := osa.WriteFile(taskFile, []byte(tid), DefaultPerms); err != nil {
+			_ = status.failed(startstatus.NewError("join cgroup", taskFile, err))
 			return err
 		}
 	}
 
+	// Any process may raise its own score, so this requires no privilege
+	// when it undoes the exemption that an init process gave itself
+	if oomScoreAdj != "" {
+		scoreFile := fmt.Sprintf(OomScoreAdjFilenameFormat, "self")
+		if err := osa.WriteFile(scoreFile, []byte(oomScoreAdj), DefaultPerms); err != nil {
+			_ = status.failed(startstatus.NewError("set OOM score", scoreFile, err))
+			return err
+		}
+	}
+
+	if joinPid != 0 {
+		return runInNamespaces(joinPid, commandList, osa, sa, status)
+	}
+
+	if asInit {
+		return runAsInit(commandList, osa, sa, status)
+	}
+
+	// The status pipe is closed on exec
 	if err := sa.Exec(commandList[0], commandList, osa.Environ()); err != nil {
+		_ = status.failed(startstatus.NewError("exec", commandList[0], err))
 		return err
 	}
 
 	// This should never happen
 	panic("Reached end of Cgexec unexpectedly")
 }
+
+// runInNamespaces enters the namespaces of the process with the given pid,
+// runs the given command as a child process, and waits for it to terminate.
+// It returns an ExitError that reports the command's exit status.
+func runInNamespaces(
+	pid int,
+	commandList []string,
+	osa *os.Adapter,
+	sa *syscall.Adapter,
+	startStatus *statusPipe,
+) error {
+	// Namespaces are entered per thread, and the command must be forked
+	// from the thread that entered them.  The thread is never unlocked, so
+	// it is not reused once this goroutine exits.
+	runtime.LockOSThread()
+
+	// A thread cannot enter a mount namespace while it shares its
+	// filesystem information (e.g., its working directory) with others
+	if err := sa.Unshare(unix.CLONE_FS); err != nil {
+		return startStatus.failed(startstatus.NewError("unshare filesystem information", "", err))
+	}
+
+	files := make([]*goos.File, 0, len(joinNamespaces))
+	defer func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}()
+
+	// Open every namespace before entering any, since entering the mount
+	// namespace changes the view of /proc
+	for _, ns := range joinNamespaces {
+		path := fmt.Sprintf("/proc/%d/ns/%s", pid, ns.name)
+		file, err := goos.Open(path)
+		if err != nil {
+			return startStatus.failed(startstatus.NewError("open namespace", path, err))
+		}
+		files = append(files, file)
+	}
+
+	for i, ns := range joinNamespaces {
+		if err := sa.Setns(int(files[i].Fd()), ns.nstype); err != nil {
+			return startStatus.failed(startstatus.NewError("enter namespace", files[i].Name(), err))
+		}
+	}
+
+	// The command must not outlive us, but its parent-death signal cannot
+	// be used since we are outside its PID namespace.  Instead, we kill it
+	// when we are asked to terminate.
+	signals := make(chan goos.Signal, 1)
+	signal.Notify(signals, gosyscall.SIGTERM, gosyscall.SIGINT, gosyscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	childPid, err := sa.ForkExec(commandList[0], commandList, &gosyscall.ProcAttr{
+		Env:   osa.Environ(),
+		Files: []uintptr{0, 1, 2},
+	})
+	if err != nil {
+		return startStatus.failed(startstatus.NewError("exec", commandList[0], err))
+	}
+	startStatus.started()
+
+	go func() {
+		for range signals {
+			_ = sa.Kill(childPid, gosyscall.SIGKILL)
+		}
+	}()
+
+	var status gosyscall.WaitStatus
+	for {
+		_, err := sa.Wait4(childPid, &status, 0, nil)
+		if err == nil {
+			break
+		}
+		if err != gosyscall.EINTR {
+			return fmt.Errorf("cgexec: %w", err)
+		}
+	}
+
+	return newExitError(status)
+}
+
+// runAsInit runs the given command as a child process and acts as the init
+// process of its PID namespace until the command terminates.  It returns an
+// ExitError that reports the command's exit status.
+func runAsInit(
+	commandList []string,
+	osa *os.Adapter,
+	sa *syscall.Adapter,
+	startStatus *statusPipe,
+) error {
+	// Signals that arrive before the command starts are forwarded once it
+	// has started
+	signals := make(chan goos.Signal, len(initForwardedSignals))
+	signal.Notify(signals, initForwardedSignals...)
+	defer signal.Stop(signals)
+
+	// We are much larger than the command and share its memory cgroup, so
+	// the OOM killer would choose us first, and our death kills the whole
+	// namespace.  We exempt ourselves before the command can allocate any
+	// memory.  This is best effort; it requires privilege.
+	selfScoreFile := fmt.Sprintf(OomScoreAdjFilenameFormat, "self")
+	score, scoreErr := osa.ReadFile(selfScoreFile)
+	if scoreErr == nil {
+		scoreErr = osa.WriteFile(selfScoreFile, []byte(OomScoreAdjNeverKill), os.FileMode(0644))
+	}
+
+	argv0, argv := commandList[0], commandList
+	files := []uintptr{0, 1, 2}
+
+	// The command must not inherit the exemption, or the OOM killer could
+	// never choose it.  /proc is that of the parent PID namespace, which
+	// knows the command by another PID, so we cannot set the command's score
+	// once it has started.  Instead, we run a copy of ourselves that restores
+	// our original score before it execs the command, and that reports to
+	// the server in our place whether it did.
+	if scoreErr == nil {
+		argv0 = config.SelfExecPath
+		argv = append([]string{argv0}, config.CgexecArgs...)
+		argv = append(argv, OomScoreAdjOption+strings.TrimSpace(string(score)))
+
+		if startStatus.file != nil {
+			argv = append(argv, fmt.Sprintf("%s%d", StatusFdOption, len(files)))
+			files = append(files, startStatus.file.Fd())
+		}
+
+		argv = append(append(argv, "--"), commandList...)
+	}
+
+	childPid, err := sa.ForkExec(argv0, argv, &gosyscall.ProcAttr{
+		Env:   osa.Environ(),
+		Files: files,
+	})
+	if err != nil {
+		return startStatus.failed(startstatus.NewError("exec", argv0, err))
+	}
+	startStatus.started()
+
+	go func() {
+		for sig := range signals {
+			_ = sa.Kill(childPid, sig.(gosyscall.Signal))
+		}
+	}()
+
+	// Processes orphaned in the namespace become our children, so reap
+	// every child until the command itself terminates.  Once we exit, the
+	// kernel kills any processes that remain in the namespace.
+	var status gosyscall.WaitStatus
+	for {
+		wpid, err := sa.Wait4(-1, &status, 0, nil)
+		if err != nil {
+			if err == gosyscall.EINTR {
+				continue
+			}
+			return fmt.Errorf("cgexec: %w", err)
+		}
+
+		if wpid == childPid {
+			return newExitError(status)
+		}
+	}
+}
+
+// newExitError returns an ExitError that reports the given wait status of a
+// terminated process.
+func newExitError(status gosyscall.WaitStatus) *ExitError {
+	if status.Signaled() {
+		return &ExitError{Code: 128 + int(status.Signal())}
+	}
+
+	return &ExitError{Code: status.ExitStatus()}
+}
+
+// statusPipe reports whether Cgexec started the command over the status pipe
+// given by StatusFdOption, if any.
+type statusPipe struct {
+	file *goos.File
+}
+
+// openStatusPipe returns a statusPipe for the given file descriptor, or one
+// that reports nothing if the file descriptor is negative.  The file
+// descriptor is closed on exec, which reports that the command started.
+func openStatusPipe(fd int) *statusPipe {
+	if fd < 0 {
+		return &statusPipe{}
+	}
+
+	unix.CloseOnExec(fd)
+
+	return &statusPipe{file: goos.NewFile(uintptr(fd), "status")}
+}
+
+// started reports that the command started by closing the status pipe.
+func (p *statusPipe) started() {
+	if p.file != nil {
+		p.file.Close()
+		p.file = nil
+	}
+}
+
+// failed reports the given error over the status pipe and returns it.
+func (p *statusPipe) failed(err *startstatus.Error) error {
+	if p.file != nil {
+		_ = startstatus.Write(p.file, err)
+		p.file.Close()
+		p.file = nil
+	}
+
+	return err
+}
@@ -15,6 +15,8 @@ package command_test
 
 import (
 	"fmt"
+	goos "os"
+	gosyscall "syscall"
 	"testing"
 
 	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
@@ -22,21 +24,25 @@ import (
 	"github.com/adalton/teleport-exercise/pkg/adaptation/syscall"
 	"github.com/adalton/teleport-exercise/pkg/adaptation/syscall/syscalltest"
 	"github.com/adalton/teleport-exercise/pkg/command"
+	"github.com/adalton/teleport-exercise/pkg/config"
+	"github.com/adalton/teleport-exercise/pkg/startstatus"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
 )
 
 func Test_Cgexec_WriteCgroupFiles_Success(t *testing.T) {
 	writeFileRecorder := &ostest.WriteFileMock{}
-	pidGenerator := ostest.GetpidMock(1234)
+	tidGenerator := syscalltest.GettidMock(1234)
 
 	osa := &os.Adapter{
 		WriteFileFn: writeFileRecorder.WriteFile,
-		GetpidFn:    pidGenerator.Getpid,
 	}
 
 	sc := &syscall.Adapter{
-		ExecFn: (&syscalltest.ExecMock{}).Exec,
+		GettidFn: tidGenerator.Gettid,
+		ExecFn:   (&syscalltest.ExecMock{}).Exec,
 	}
 
 	cgfile := "/sys/fs/cgroup/cpu/job/1e71d42d-b7e2-4f1c-893f-b16415b96e1a/tasks"
@@ -52,7 +58,7 @@ func Test_Cgexec_WriteCgroupFiles_Success(t *testing.T) {
 
 	assert.Equal(t, 1, len(writeFileRecorder.Events))
 	assert.Equal(t, cgfile, writeFileRecorder.Events[0].Name)
-	assert.Equal(t, fmt.Sprintf("%d", pidGenerator), string(writeFileRecorder.Events[0].Data))
+	assert.Equal(t, fmt.Sprintf("%d", tidGenerator), string(writeFileRecorder.Events[0].Data))
 }
 
 func Test_Cgexec_WriteCgroupFiles_Failure(t *testing.T) {
@@ -60,14 +66,14 @@ func Test_Cgexec_WriteCgroupFiles_Failure(t *testing.T) {
 	writeFileRecorder := &ostest.WriteFileMock{
 		NextError: expectedError,
 	}
-	var pidGenerator ostest.GetpidMock
+	var tidGenerator syscalltest.GettidMock
 	osa := &os.Adapter{
 		WriteFileFn: writeFileRecorder.WriteFile,
-		GetpidFn:    pidGenerator.Getpid,
 	}
 
 	sc := &syscall.Adapter{
-		ExecFn: (&syscalltest.ExecMock{}).Exec,
+		GettidFn: tidGenerator.Gettid,
+		ExecFn:   (&syscalltest.ExecMock{}).Exec,
 	}
 
 	cgfile := "/sys/fs/cgroup/cpu/job/1e71d42d-b7e2-4f1c-893f-b16415b96e1a/tasks"
@@ -86,17 +92,17 @@ func Test_Cgexec_WriteCgroupFiles_Failure(t *testing.T) {
 
 func Test_Cgexec_Exec(t *testing.T) {
 	env := ostest.EnvironMock{"x=y"}
-	var pidGenerator ostest.GetpidMock
+	var tidGenerator syscalltest.GettidMock
 
 	osa := &os.Adapter{
 		WriteFileFn: (&ostest.WriteFileMock{}).WriteFile,
-		GetpidFn:    pidGenerator.Getpid,
 		EnvironFn:   env.Environ,
 	}
 
 	execRecorder := &syscalltest.ExecMock{}
 	sc := &syscall.Adapter{
-		ExecFn: execRecorder.Exec,
+		GettidFn: tidGenerator.Gettid,
+		ExecFn:   execRecorder.Exec,
 	}
 
 	commandName := "commandName"
@@ -119,3 +125,526 @@ func Test_Cgexec_Exec(t *testing.T) {
 	assert.Equal(t, argv, execRecorder.Argv)
 	assert.Equal(t, env, ostest.EnvironMock(execRecorder.Envv))
 }
+
+func Test_Cgexec_Join_InvalidPid(t *testing.T) {
+	execRecorder := &syscalltest.ExecMock{}
+	sc := &syscall.Adapter{
+		ExecFn: execRecorder.Exec,
+	}
+
+	args := []string{
+		"nameOfTheTool",
+		command.JoinOption + "notAPid",
+		"--",
+		"commandName",
+	}
+
+	err := command.CgexecDetailed(args, &os.Adapter{}, sc)
+
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid pid 'notAPid'")
+	}
+	assert.Equal(t, "", execRecorder.Argv0)
+}
+
+func Test_Cgexec_Join(t *testing.T) {
+	const childPid = 4321
+
+	writeFileRecorder := &ostest.WriteFileMock{}
+	env := ostest.EnvironMock{"x=y"}
+	tidGenerator := syscalltest.GettidMock(1234)
+
+	osa := &os.Adapter{
+		WriteFileFn: writeFileRecorder.WriteFile,
+		EnvironFn:   env.Environ,
+	}
+
+	var (
+		unshareFlags []int
+		setnsTypes   []int
+		forkArgv     []string
+		forkEnv      []string
+	)
+	execRecorder := &syscalltest.ExecMock{}
+	sc := &syscall.Adapter{
+		GettidFn: tidGenerator.Gettid,
+		ExecFn:   execRecorder.Exec,
+		UnshareFn: func(flags int) error {
+			unshareFlags = append(unshareFlags, flags)
+			return nil
+		},
+		SetnsFn: func(fd int, nstype int) error {
+			setnsTypes = append(setnsTypes, nstype)
+			return nil
+		},
+		ForkExecFn: func(argv0 string, argv []string, attr *gosyscall.ProcAttr) (int, error) {
+			forkArgv = argv
+			forkEnv = attr.Env
+			return childPid, nil
+		},
+		Wait4Fn: func(
+			pid int,
+			wstatus *gosyscall.WaitStatus,
+			options int,
+			rusage *gosyscall.Rusage,
+		) (int, error) {
+			*wstatus = gosyscall.WaitStatus(7 << 8) // exited with status 7
+			return pid, nil
+		},
+	}
+
+	cgfile := "/sys/fs/cgroup/cpu/job/1e71d42d-b7e2-4f1c-893f-b16415b96e1a/tasks"
+	args := []string{
+		"nameOfTheTool",
+		fmt.Sprintf("%s%d", command.JoinOption, goos.Getpid()),
+		cgfile,
+		"--",
+		"commandName",
+		"arg1",
+	}
+
+	err := command.CgexecDetailed(args, osa, sc)
+
+	var exitErr *command.ExitError
+	if assert.ErrorAs(t, err, &exitErr) {
+		assert.Equal(t, 7, exitErr.Code)
+	}
+
+	assert.Equal(t, 1, len(writeFileRecorder.Events))
+	assert.Equal(t, cgfile, writeFileRecorder.Events[0].Name)
+	assert.Equal(t, []int{gosyscall.CLONE_FS}, unshareFlags)
+	assert.Equal(t,
+		[]int{gosyscall.CLONE_NEWNET, gosyscall.CLONE_NEWPID, gosyscall.CLONE_NEWNS},
+		setnsTypes)
+	assert.Equal(t, []string{"commandName", "arg1"}, forkArgv)
+	assert.Equal(t, []string(env), forkEnv)
+	assert.Equal(t, "", execRecorder.Argv0)
+}
+
+func Test_Cgexec_Init(t *testing.T) {
+	const (
+		childPid  = 2
+		orphanPid = 7
+	)
+
+	writeFileRecorder := &ostest.WriteFileMock{}
+	env := ostest.EnvironMock{"x=y"}
+	tidGenerator := syscalltest.GettidMock(1)
+
+	readFileMock := &ostest.ReadFileMock{
+		Files: map[string]string{"/proc/self/oom_score_adj": "100\n"},
+	}
+
+	osa := &os.Adapter{
+		WriteFileFn: writeFileRecorder.WriteFile,
+		ReadFileFn:  readFileMock.ReadFile,
+		EnvironFn:   env.Environ,
+	}
+
+	// An orphaned process terminates before the command does
+	waitResults := []struct {
+		pid    int
+		status gosyscall.WaitStatus
+		err    error
+	}{
+		{0, 0, gosyscall.EINTR},
+		{orphanPid, 0, nil},
+		{childPid, gosyscall.WaitStatus(5 << 8), nil}, // exited with status 5
+	}
+
+	var (
+		forkArgv0 string
+		forkArgv  []string
+		waitPids  []int
+	)
+	execRecorder := &syscalltest.ExecMock{}
+	sc := &syscall.Adapter{
+		GettidFn: tidGenerator.Gettid,
+		ExecFn:   execRecorder.Exec,
+		ForkExecFn: func(argv0 string, argv []string, attr *gosyscall.ProcAttr) (int, error) {
+			forkArgv0 = argv0
+			forkArgv = argv
+			return childPid, nil
+		},
+		Wait4Fn: func(
+			pid int,
+			wstatus *gosyscall.WaitStatus,
+			options int,
+			rusage *gosyscall.Rusage,
+		) (int, error) {
+			waitPids = append(waitPids, pid)
+			result := waitResults[0]
+			waitResults = waitResults[1:]
+
+			*wstatus = result.status
+			return result.pid, result.err
+		},
+	}
+
+	cgfile := "/sys/fs/cgroup/cpu/job/1e71d42d-b7e2-4f1c-893f-b16415b96e1a/tasks"
+	args := []string{
+		"nameOfTheTool",
+		command.InitOption,
+		cgfile,
+		"--",
+		"commandName",
+		"arg1",
+	}
+
+	err := command.CgexecDetailed(args, osa, sc)
+
+	var exitErr *command.ExitError
+	if assert.ErrorAs(t, err, &exitErr) {
+		assert.Equal(t, 5, exitErr.Code)
+	}
+
+	// The init process exempts itself from the OOM killer before forking,
+	// and runs a copy of itself that restores its original score before it
+	// execs the command
+	assert.Equal(t, 2, len(writeFileRecorder.Events))
+	assert.Equal(t, cgfile, writeFileRecorder.Events[0].Name)
+	assert.Equal(t, "/proc/self/oom_score_adj", writeFileRecorder.Events[1].Name)
+	assert.Equal(t, []byte(command.OomScoreAdjNeverKill), writeFileRecorder.Events[1].Data)
+	assert.Equal(t, config.SelfExecPath, forkArgv0)
+	assert.Equal(t, []string{
+		config.SelfExecPath,
+		command.OomScoreAdjOption + "100",
+		"--",
+		"commandName",
+		"arg1",
+	}, forkArgv)
+	assert.Equal(t, []int{-1, -1, -1}, waitPids)
+	assert.Equal(t, 0, len(waitResults))
+	assert.Equal(t, "", execRecorder.Argv0)
+}
+
+func Test_Cgexec_Init_CommandSignaled(t *testing.T) {
+	const childPid = 2
+
+	osa := &os.Adapter{
+		WriteFileFn: (&ostest.WriteFileMock{}).WriteFile,
+		EnvironFn:   ostest.EnvironMock{}.Environ,
+	}
+
+	sc := &syscall.Adapter{
+		GettidFn: syscalltest.GettidMock(1).Gettid,
+		ForkExecFn: func(argv0 string, argv []string, attr *gosyscall.ProcAttr) (int, error) {
+			return childPid, nil
+		},
+		Wait4Fn: func(
+			pid int,
+			wstatus *gosyscall.WaitStatus,
+			options int,
+			rusage *gosyscall.Rusage,
+		) (int, error) {
+			*wstatus = gosyscall.WaitStatus(gosyscall.SIGTERM) // killed by SIGTERM
+			return childPid, nil
+		},
+	}
+
+	err := command.CgexecDetailed(
+		[]string{"nameOfTheTool", command.InitOption, "--", "commandName"}, osa, sc)
+
+	var exitErr *command.ExitError
+	if assert.ErrorAs(t, err, &exitErr) {
+		assert.Equal(t, 128+int(gosyscall.SIGTERM), exitErr.Code)
+	}
+}
+
+func Test_Cgexec_Init_ForkExecFailure(t *testing.T) {
+	expectedError := fmt.Errorf("injected error")
+
+	osa := &os.Adapter{
+		WriteFileFn: (&ostest.WriteFileMock{}).WriteFile,
+		EnvironFn:   ostest.EnvironMock{}.Environ,
+	}
+
+	sc := &syscall.Adapter{
+		GettidFn: syscalltest.GettidMock(1).Gettid,
+		ForkExecFn: func(argv0 string, argv []string, attr *gosyscall.ProcAttr) (int, error) {
+			return 0, expectedError
+		},
+	}
+
+	err := command.CgexecDetailed(
+		[]string{"nameOfTheTool", command.InitOption, "--", "commandName"}, osa, sc)
+
+	assert.ErrorIs(t, err, expectedError)
+}
+
+func Test_Cgexec_Init_NoOomExemption(t *testing.T) {
+	const childPid = 2
+
+	// Without privilege, the init process keeps its score, so the command
+	// inherits it without a copy of the init process in between
+	writeFileMock := &ostest.WriteFileMock{}
+	readFileMock := &ostest.ReadFileMock{
+		Files: map[string]string{"/proc/self/oom_score_adj": "100\n"},
+	}
+
+	osa := &os.Adapter{
+		WriteFileFn: func(name string, data []byte, perm os.FileMode) error {
+			if name == "/proc/self/oom_score_adj" {
+				return gosyscall.EACCES
+			}
+			return writeFileMock.WriteFile(name, data, perm)
+		},
+		ReadFileFn: readFileMock.ReadFile,
+		EnvironFn:  ostest.EnvironMock{}.Environ,
+	}
+
+	var forkArgv []string
+	sc := &syscall.Adapter{
+		GettidFn: syscalltest.GettidMock(1).Gettid,
+		ForkExecFn: func(argv0 string, argv []string, attr *gosyscall.ProcAttr) (int, error) {
+			forkArgv = argv
+			return childPid, nil
+		},
+		Wait4Fn: func(
+			pid int,
+			wstatus *gosyscall.WaitStatus,
+			options int,
+			rusage *gosyscall.Rusage,
+		) (int, error) {
+			return childPid, nil
+		},
+	}
+
+	err := command.CgexecDetailed(
+		[]string{"nameOfTheTool", command.InitOption, "--", "commandName"}, osa, sc)
+
+	var exitErr *command.ExitError
+	assert.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, []string{"commandName"}, forkArgv)
+}
+
+func Test_Cgexec_OomScoreAdj(t *testing.T) {
+	writeFileRecorder := &ostest.WriteFileMock{}
+	env := ostest.EnvironMock{"x=y"}
+
+	osa := &os.Adapter{
+		WriteFileFn: writeFileRecorder.WriteFile,
+		EnvironFn:   env.Environ,
+	}
+
+	execRecorder := &syscalltest.ExecMock{}
+	sc := &syscall.Adapter{
+		GettidFn: syscalltest.GettidMock(1).Gettid,
+		ExecFn:   execRecorder.Exec,
+	}
+
+	args := []string{
+		"nameOfTheTool",
+		command.OomScoreAdjOption + "100",
+		"--",
+		"commandName",
+		"arg1",
+	}
+
+	err := command.CgexecDetailed(args, osa, sc)
+
+	assert.Error(t, err)
+
+	// The score is set before the command is exec'd
+	if assert.Equal(t, 1, len(writeFileRecorder.Events)) {
+		assert.Equal(t, "/proc/self/oom_score_adj", writeFileRecorder.Events[0].Name)
+		assert.Equal(t, []byte("100"), writeFileRecorder.Events[0].Data)
+	}
+	assert.Equal(t, "commandName", execRecorder.Argv0)
+	assert.Equal(t, []string{"commandName", "arg1"}, execRecorder.Argv)
+}
+
+func Test_Cgexec_OomScoreAdj_Invalid(t *testing.T) {
+	osa := &os.Adapter{
+		WriteFileFn: (&ostest.WriteFileMock{}).WriteFile,
+	}
+
+	execRecorder := &syscalltest.ExecMock{}
+	sc := &syscall.Adapter{
+		GettidFn: syscalltest.GettidMock(1).Gettid,
+		ExecFn:   execRecorder.Exec,
+	}
+
+	err := command.CgexecDetailed(
+		[]string{"nameOfTheTool", command.OomScoreAdjOption + "high", "--", "commandName"}, osa, sc)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "", execRecorder.Argv0)
+}
+
+// statusPipe returns the read end of a new pipe, and the number of a
+// duplicate of its write end for Cgexec to report over and close.
+func statusPipe(t *testing.T) (*goos.File, int) {
+	reader, writer, err := goos.Pipe()
+	require.Nil(t, err)
+	defer writer.Close()
+
+	fd, err := unix.Dup(int(writer.Fd()))
+	require.Nil(t, err)
+
+	return reader, fd
+}
+
+func Test_Cgexec_StatusFd_WriteCgroupFilesFailure(t *testing.T) {
+	expectedError := &goos.PathError{Op: "open", Path: "tasks", Err: gosyscall.EACCES}
+	writeFileRecorder := &ostest.WriteFileMock{
+		NextError: expectedError,
+	}
+
+	osa := &os.Adapter{
+		WriteFileFn: writeFileRecorder.WriteFile,
+	}
+
+	execRecorder := &syscalltest.ExecMock{}
+	sc := &syscall.Adapter{
+		GettidFn: syscalltest.GettidMock(1234).Gettid,
+		ExecFn:   execRecorder.Exec,
+	}
+
+	reader, fd := statusPipe(t)
+	defer reader.Close()
+
+	cgfile := "/sys/fs/cgroup/cpu/job/1e71d42d-b7e2-4f1c-893f-b16415b96e1a/tasks"
+	args := []string{
+		"nameOfTheTool",
+		fmt.Sprintf("%s%d", command.StatusFdOption, fd),
+		cgfile,
+		"--",
+		"commandName",
+	}
+
+	err := command.CgexecDetailed(args, osa, sc)
+
+	assert.Equal(t, expectedError, err)
+	assert.Equal(t, "", execRecorder.Argv0)
+
+	statusErr := startstatus.Read(reader)
+
+	var startErr *startstatus.Error
+	if assert.ErrorAs(t, statusErr, &startErr) {
+		assert.Equal(t, "join cgroup", startErr.Op)
+		assert.Equal(t, cgfile, startErr.Path)
+		assert.Equal(t, gosyscall.EACCES, startErr.Errno)
+	}
+}
+
+func Test_Cgexec_StatusFd_ExecFailure(t *testing.T) {
+	osa := &os.Adapter{
+		WriteFileFn: (&ostest.WriteFileMock{}).WriteFile,
+		EnvironFn:   ostest.EnvironMock{}.Environ,
+	}
+
+	sc := &syscall.Adapter{
+		GettidFn: syscalltest.GettidMock(1234).Gettid,
+		ExecFn: func(argv0 string, argv []string, envv []string) error {
+			return gosyscall.ENOENT
+		},
+	}
+
+	reader, fd := statusPipe(t)
+	defer reader.Close()
+
+	args := []string{
+		"nameOfTheTool",
+		fmt.Sprintf("%s%d", command.StatusFdOption, fd),
+		"--",
+		"/bin/missing",
+	}
+
+	err := command.CgexecDetailed(args, osa, sc)
+
+	assert.ErrorIs(t, err, gosyscall.ENOENT)
+
+	statusErr := startstatus.Read(reader)
+
+	var startErr *startstatus.Error
+	if assert.ErrorAs(t, statusErr, &startErr) {
+		assert.Equal(t, "exec", startErr.Op)
+		assert.Equal(t, "/bin/missing", startErr.Path)
+	}
+	assert.ErrorIs(t, statusErr, gosyscall.ENOENT)
+}
+
+func Test_Cgexec_StatusFd_InitStarted(t *testing.T) {
+	const childPid = 2
+
+	osa := &os.Adapter{
+		WriteFileFn: (&ostest.WriteFileMock{}).WriteFile,
+		EnvironFn:   ostest.EnvironMock{}.Environ,
+	}
+
+	reader, fd := statusPipe(t)
+	defer reader.Close()
+
+	var statusErr error
+	sc := &syscall.Adapter{
+		GettidFn: syscalltest.GettidMock(1).Gettid,
+		ForkExecFn: func(argv0 string, argv []string, attr *gosyscall.ProcAttr) (int, error) {
+			return childPid, nil
+		},
+		Wait4Fn: func(
+			pid int,
+			wstatus *gosyscall.WaitStatus,
+			options int,
+			rusage *gosyscall.Rusage,
+		) (int, error) {
+			// The status pipe is closed once the command has started
+			statusErr = startstatus.Read(reader)
+			return childPid, nil
+		},
+	}
+
+	args := []string{
+		"nameOfTheTool",
+		fmt.Sprintf("%s%d", command.StatusFdOption, fd),
+		command.InitOption,
+		"--",
+		"commandName",
+	}
+
+	err := command.CgexecDetailed(args, osa, sc)
+
+	var exitErr *command.ExitError
+	assert.ErrorAs(t, err, &exitErr)
+	assert.Nil(t, statusErr)
+}
+
+func Test_Cgexec_StatusFd_UnknownOption(t *testing.T) {
+	reader, fd := statusPipe(t)
+	defer reader.Close()
+
+	args := []string{
+		"nameOfTheTool",
+		fmt.Sprintf("%s%d", command.StatusFdOption, fd),
+		"--bogus",
+		"--",
+		"commandName",
+	}
+
+	err := command.CgexecDetailed(args, &os.Adapter{}, &syscall.Adapter{})
+
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "unknown option '--bogus'")
+	}
+
+	var startErr *startstatus.Error
+	if assert.ErrorAs(t, startstatus.Read(reader), &startErr) {
+		assert.Equal(t, "parse arguments", startErr.Op)
+	}
+}
+
+func Test_Cgexec_JoinAndInit(t *testing.T) {
+	args := []string{
+		"nameOfTheTool",
+		command.JoinOption + "1",
+		command.InitOption,
+		"--",
+		"commandName",
+	}
+
+	err := command.CgexecDetailed(args, &os.Adapter{}, &syscall.Adapter{})
+
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "cannot be combined")
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobctl
+
+import (
+	"errors"
+	"os"
+
+	"github.com/adalton/teleport-exercise/pkg/client/jobmanager"
+
+	"github.com/spf13/cobra"
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec",
+	Short: "Run a command in a job",
+	Long: "Run a command in the namespaces and cgroups of a running job managed by JobManager " +
+		"and show its output.  jobctl exits with the command's exit status.",
+	Example: "jobctl exec ba90b623-3dae-4bdd-8b96-c1ea4a999c44 -- /bin/ps -ef",
+	RunE:    execCommand,
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+}
+
+func execCommand(cmd *cobra.Command, args []string) error {
+	if cmd.ArgsLenAtDash() != 1 {
+		return errors.New("must include exactly one job ID before --")
+	}
+
+	if len(args) < 2 {
+		return errors.New("no command specified after --")
+	}
+
+	c, err := jobmanager.NewClient(argUserID, argServerHostPort)
+	if err != nil {
+		return err
+	}
+
+	c.OnTruncation = reportTruncation
+
+	status, err := c.Exec(cmd.Context(), args[0], os.Stdout, os.Stderr, args[1], args[2:]...)
+	c.Close()
+	if err != nil {
+		return err
+	}
+
+	if status.RunError != nil {
+		return status.RunError
+	}
+
+	if status.ExitCode != 0 {
+		os.Exit(status.ExitCode)
+	}
+
+	return nil
+}
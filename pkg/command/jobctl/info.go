@@ -0,0 +1,64 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobctl
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/adalton/teleport-exercise/pkg/client/jobmanager"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var infoCmd = &cobra.Command{
+	Use:     "info",
+	Short:   "Show server information",
+	Long:    "Show information about the JobManager, including the resource limits it enforces on jobs",
+	Example: "jobctl info",
+	RunE:    info,
+}
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+}
+
+func info(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), shortOperationTimeout)
+	defer cancel()
+
+	c, err := jobmanager.NewClient(argUserID, argServerHostPort)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	serverInfo, err := c.Info(ctx)
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+
+	table.SetHeader([]string{"Cgroup Version", "Capabilities"})
+	table.Append([]string{
+		serverInfo.CgroupVersion,
+		strings.Join(serverInfo.Capabilities, ", "),
+	})
+
+	table.Render()
+
+	return nil
+}
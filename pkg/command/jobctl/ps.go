@@ -0,0 +1,77 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobctl
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adalton/teleport-exercise/pkg/client/jobmanager"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var psCmd = &cobra.Command{
+	Use:     "ps",
+	Short:   "List job processes",
+	Long:    "List the processes in the cgroups of a running job managed by JobManager",
+	Example: "jobctl ps ba90b623-3dae-4bdd-8b96-c1ea4a999c44",
+	RunE:    ps,
+}
+
+func init() {
+	rootCmd.AddCommand(psCmd)
+}
+
+func ps(cmd *cobra.Command, jobIDs []string) error {
+	if len(jobIDs) != 1 {
+		return errors.New("exactly one job must be specified")
+	}
+
+	c, err := jobmanager.NewClient(argUserID, argServerHostPort)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), shortOperationTimeout)
+	defer cancel()
+
+	processes, err := c.Processes(ctx, jobIDs[0])
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"PID", "PPID", "State", "RSS", "CPU Time", "Command"})
+	table.SetAutoWrapText(false)
+
+	for _, process := range processes {
+		table.Append([]string{
+			strconv.Itoa(process.Pid),
+			strconv.Itoa(process.Ppid),
+			process.State,
+			strconv.FormatUint(process.Rss, 10),
+			process.CpuTime.Round(time.Millisecond).String(),
+			strings.Join(process.Command, " "),
+		})
+	}
+	table.Render()
+
+	return nil
+}
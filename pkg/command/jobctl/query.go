@@ -18,12 +18,17 @@ import (
 	"errors"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/adalton/teleport-exercise/pkg/client/jobmanager"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
+var (
+	argQueryUsage bool
+)
+
 var queryCmd = &cobra.Command{
 	Use:     "query",
 	Short:   "Query job state",
@@ -33,6 +38,13 @@ var queryCmd = &cobra.Command{
 }
 
 func init() {
+	queryCmd.PersistentFlags().BoolVar(
+		&argQueryUsage,
+		"usage",
+		false,
+		"Show the resources that terminated jobs consumed instead of their state",
+	)
+
 	rootCmd.AddCommand(queryCmd)
 }
 
@@ -65,14 +77,48 @@ func query(cmd *cobra.Command, jobIDs []string) error {
 		jobStatusList = append(jobStatusList, status)
 	}
 
-	renderJobStatusList(jobStatusList)
+	if argQueryUsage {
+		renderJobUsageList(jobStatusList)
+	} else {
+		renderJobStatusList(jobStatusList)
+	}
 
 	return lastError
 }
 
+// renderJobUsageList displays the final resource usage of each of the given
+// jobs.  The usage of jobs that are still running is left blank.
+func renderJobUsageList(jobStatus []*jobmanager.JobStatus) {
+	table := tablewriter.NewWriter(os.Stdout)
+
+	table.SetHeader([]string{"Name", "ID", "CPU User", "CPU System", "Peak Memory",
+		"IO Read", "IO Write", "Max Pids"})
+
+	for _, js := range jobStatus {
+		columns := []string{js.Name, js.ID, "", "", "", "", "", ""}
+
+		if usage := js.Usage; usage != nil {
+			columns[2] = usage.UserTime.Round(time.Millisecond).String()
+			columns[3] = usage.SystemTime.Round(time.Millisecond).String()
+			columns[4] = strconv.FormatUint(usage.PeakMemoryUsage, 10)
+			columns[5] = strconv.FormatUint(usage.IoReadBytes, 10)
+			columns[6] = strconv.FormatUint(usage.IoWriteBytes, 10)
+
+			if usage.MaxPids > 0 {
+				columns[7] = strconv.FormatUint(usage.MaxPids, 10)
+			}
+		}
+
+		table.Append(columns)
+	}
+
+	table.Render()
+}
+
 func renderJobStatusList(jobStatus []*jobmanager.JobStatus) {
 	isAdmin := argUserID == jobmanager.Superuser
-	header := []string{"Owner", "Name", "ID", "Running", "Pid", "Exit Code", "Signal", "Error"}
+	header := []string{"Owner", "Name", "ID", "State", "Pid", "Exit Code", "Signal", "Termination",
+		"Peak Memory", "Pids Limit Reached", "Error"}
 
 	if !isAdmin {
 		header = header[1:]
@@ -103,7 +149,17 @@ func renderJobStatusList(jobStatus []*jobmanager.JobStatus) {
 			pid = strconv.FormatInt(int64(js.Pid), 10)
 		}
 
-		columns := make([]string, 0, 8)
+		pidsLimitReached := ""
+		if js.PidsLimitReached {
+			pidsLimitReached = "yes"
+		}
+
+		peakMemory := ""
+		if js.PeakMemoryUsage > 0 {
+			peakMemory = strconv.FormatUint(js.PeakMemoryUsage, 10)
+		}
+
+		columns := make([]string, 0, 11)
 
 		if isAdmin {
 			columns = append(columns, js.Owner)
@@ -111,10 +167,13 @@ func renderJobStatusList(jobStatus []*jobmanager.JobStatus) {
 
 		columns = append(columns, js.Name)
 		columns = append(columns, js.ID)
-		columns = append(columns, strconv.FormatBool(js.Running))
+		columns = append(columns, js.State.String())
 		columns = append(columns, pid)
 		columns = append(columns, exitCode)
 		columns = append(columns, sigStr)
+		columns = append(columns, js.TerminationReason.String())
+		columns = append(columns, peakMemory)
+		columns = append(columns, pidsLimitReached)
 		columns = append(columns, runErr)
 
 		table.Append(columns)
@@ -15,7 +15,9 @@ package jobctl
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/adalton/teleport-exercise/pkg/client/jobmanager"
 	"github.com/olekukonko/tablewriter"
@@ -25,39 +27,164 @@ import (
 var (
 	argStartJobName string
 	argJobCommand   string
+	argBlockIO      []string
+	argMaxPids      uint64
+	argCpusetCpus   string
+	argCpusetMems   string
+	argCpus         string
+	argUnlimitedCpu bool
+	argCpuWeight    uint64
+	argCpuPeriodUs  uint64
+	argMemory       string
+	argMemorySwap   string
+	argMemorySoft   string
+	argOomPolicy    string
+	argKnobs        []string
 )
 
 var startCmd = &cobra.Command{
-	Use:     "start",
-	Short:   "Start a new job",
-	Long:    "Starts a new job with the given parameters on the JobManager",
-	Example: "start -j myJob -c /usr/bin/find -- /dir -type f",
-	RunE:    start,
+	Use:   "start",
+	Short: "Start a new job",
+	Long:  "Starts a new job with the given parameters on the JobManager",
+	Example: "start -j myJob -c /usr/bin/find -- /dir -type f\n" +
+		"  start -j myJob --blkio path=/data,wiops=100 -c /usr/bin/find -- /data",
+	RunE: start,
 }
 
 func init() {
-	startCmd.PersistentFlags().StringVarP(
+	addJobCreationFlags(startCmd)
+
+	rootCmd.AddCommand(startCmd)
+}
+
+// addJobCreationFlags registers the flags that describe a new job, shared by
+// the start and create commands, on the given command.
+func addJobCreationFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(
 		&argStartJobName,
 		"jobName",
 		"j",
 		"",
 		"The name of the job to create; must be unique",
 	)
-	startCmd.MarkPersistentFlagRequired("jobName")
+	cmd.MarkPersistentFlagRequired("jobName")
 
-	startCmd.PersistentFlags().StringVarP(
+	cmd.PersistentFlags().StringVarP(
 		&argJobCommand,
 		"command",
 		"c",
 		"",
 		"The command for the job to run; must supply full path",
 	)
-	startCmd.MarkPersistentFlagRequired("command")
+	cmd.MarkPersistentFlagRequired("command")
 
-	rootCmd.AddCommand(startCmd)
+	cmd.PersistentFlags().StringArrayVar(
+		&argBlockIO,
+		"blkio",
+		nil,
+		"Block IO limits for the device that holds a path, in the form "+
+			"path=<path>[,rbps=<rate>][,wbps=<rate>][,riops=<n>][,wiops=<n>], "+
+			"where rates are in bytes per second (e.g., 40M); may be repeated",
+	)
+
+	cmd.PersistentFlags().Uint64Var(
+		&argMaxPids,
+		"maxPids",
+		0,
+		"The maximum number of processes and threads in the job; "+
+			"0 uses the server's default",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&argCpusetCpus,
+		"cpusetCpus",
+		"",
+		"The CPUs to which to restrict the job (e.g., 0-3,8)",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&argCpusetMems,
+		"cpusetMems",
+		"",
+		"The memory nodes to which to restrict the job (e.g., 0)",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&argCpus,
+		"cpus",
+		"",
+		"The CPU quota of the job, in CPUs (e.g., 1.5) or millicpus (e.g., 1500m)",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&argUnlimitedCpu,
+		"unlimitedCpus",
+		false,
+		"Remove the CPU quota and rely on the CPU weight alone",
+	)
+
+	cmd.PersistentFlags().Uint64Var(
+		&argCpuWeight,
+		"cpuWeight",
+		0,
+		"The relative CPU weight of the job under contention, in the range "+
+			"[1, 10000]; the default weight is 100",
+	)
+
+	cmd.PersistentFlags().Uint64Var(
+		&argCpuPeriodUs,
+		"cpuPeriodUs",
+		0,
+		"The period, in microseconds, over which the CPU quota is enforced; "+
+			"0 uses the server's default",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&argMemory,
+		"memory",
+		"",
+		"The memory limit of the job (e.g., 512M)",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&argMemorySwap,
+		"memorySwap",
+		"",
+		"The combined memory and swap limit of the job (e.g., 1G); "+
+			"equal to --memory to disable swap",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&argMemorySoft,
+		"memorySoftLimit",
+		"",
+		"The memory usage above which the job's memory is reclaimed first "+
+			"under memory pressure (e.g., 256M)",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&argOomPolicy,
+		"oom",
+		"",
+		"What happens when the job reaches its memory limit: kill or pause",
+	)
+
+	cmd.PersistentFlags().StringArrayVar(
+		&argKnobs,
+		"knob",
+		nil,
+		"A cgroup interface file to write for the job, in the form "+
+			"<file>=<value> (e.g., memory.swappiness=0); the server must "+
+			"allow the file; may be repeated",
+	)
 }
 
 func start(cmd *cobra.Command, args []string) error {
+	limits, err := parseJobLimits()
+	if err != nil {
+		return err
+	}
+
 	ctx, cancel := context.WithTimeout(cmd.Context(), shortOperationTimeout)
 	defer cancel()
 
@@ -67,7 +194,7 @@ func start(cmd *cobra.Command, args []string) error {
 	}
 	defer c.Close()
 
-	jobID, err := c.Start(ctx, argStartJobName, argJobCommand, args...)
+	jobID, err := c.Start(ctx, argStartJobName, limits, argJobCommand, args...)
 	if err != nil {
 		return err
 	}
@@ -81,3 +208,109 @@ func start(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// parseJobLimits builds the JobLimits for the new job from the command line
+// arguments.  Limits that are not specified are left zero so that the server
+// applies its defaults.
+func parseJobLimits() (*jobmanager.JobLimits, error) {
+	oomPolicy, err := jobmanager.ParseOomPolicy(argOomPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	cpus, err := jobmanager.ParseCpus("--cpus", argCpus)
+	if err != nil {
+		return nil, err
+	}
+
+	memory, err := jobmanager.ParseSize("--memory", argMemory)
+	if err != nil {
+		return nil, err
+	}
+
+	memorySwap, err := jobmanager.ParseSize("--memorySwap", argMemorySwap)
+	if err != nil {
+		return nil, err
+	}
+
+	memorySoft, err := jobmanager.ParseSize("--memorySoftLimit", argMemorySoft)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := &jobmanager.JobLimits{
+		MaxPids:       argMaxPids,
+		CpusetCpus:    argCpusetCpus,
+		CpusetMems:    argCpusetMems,
+		Cpus:          cpus,
+		UnlimitedCpus: argUnlimitedCpu,
+		CpuWeight:     argCpuWeight,
+		CpuPeriodUs:   argCpuPeriodUs,
+
+		MemoryLimit:     memory,
+		MemorySwapLimit: memorySwap,
+		MemorySoftLimit: memorySoft,
+		OomPolicy:       oomPolicy,
+	}
+
+	for _, spec := range argBlockIO {
+		limit, err := parseBlockIOLimit(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		limits.BlockIO = append(limits.BlockIO, limit)
+	}
+
+	for _, spec := range argKnobs {
+		file, value, found := strings.Cut(spec, "=")
+		if !found || file == "" {
+			return nil, fmt.Errorf("malformed cgroup knob '%s': expected <file>=<value>", spec)
+		}
+
+		limits.Knobs = append(limits.Knobs, jobmanager.CgroupKnob{File: file, Value: value})
+	}
+
+	return limits, nil
+}
+
+// parseBlockIOLimit parses a block IO limit of the form
+// "path=<path>[,rbps=<rate>][,wbps=<rate>][,riops=<n>][,wiops=<n>]".
+func parseBlockIOLimit(spec string) (jobmanager.BlockIOLimit, error) {
+	var limit jobmanager.BlockIOLimit
+
+	for _, field := range strings.Split(spec, ",") {
+		key, value, found := strings.Cut(field, "=")
+		if !found {
+			return limit, fmt.Errorf("malformed block IO limit '%s': expected key=value, found '%s'", spec, field)
+		}
+
+		name := fmt.Sprintf("block IO limit '%s': %s", spec, key)
+		var err error
+
+		switch key {
+		case "path":
+			limit.Path = value
+		case "rbps":
+			limit.ReadBps, err = jobmanager.ParseByteRate(name, value)
+		case "wbps":
+			limit.WriteBps, err = jobmanager.ParseByteRate(name, value)
+		case "riops":
+			limit.ReadIops, err = jobmanager.ParseRate(name, value)
+		case "wiops":
+			limit.WriteIops, err = jobmanager.ParseRate(name, value)
+		default:
+			return limit, fmt.Errorf("malformed block IO limit '%s': unknown key '%s'", spec, key)
+		}
+
+		if err != nil {
+			return limit, err
+		}
+	}
+
+	if limit.Path == "" {
+		return limit, fmt.Errorf("malformed block IO limit '%s': path is required", spec)
+	}
+
+	return limit, nil
+}
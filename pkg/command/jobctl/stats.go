@@ -0,0 +1,152 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/adalton/teleport-exercise/pkg/client/jobmanager"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// clearScreen is the ANSI escape sequence that moves the cursor to the
+	// top-left corner of the terminal and clears the screen.
+	clearScreen = "\033[H\033[2J"
+)
+
+var (
+	argStatsWatch    bool
+	argStatsInterval time.Duration
+)
+
+var statsCmd = &cobra.Command{
+	Use:     "stats",
+	Short:   "Show job resource usage",
+	Long:    "Show the resource usage of one or more running jobs managed by JobManager",
+	Example: "jobctl stats --watch ba90b623-3dae-4bdd-8b96-c1ea4a999c44",
+	RunE:    stats,
+}
+
+func init() {
+	statsCmd.PersistentFlags().BoolVarP(
+		&argStatsWatch,
+		"watch",
+		"w",
+		false,
+		"Refresh the resource usage until interrupted or all jobs have terminated",
+	)
+
+	statsCmd.PersistentFlags().DurationVar(
+		&argStatsInterval,
+		"interval",
+		time.Second,
+		"The refresh interval in watch mode",
+	)
+
+	rootCmd.AddCommand(statsCmd)
+}
+
+func stats(cmd *cobra.Command, jobIDs []string) error {
+	if len(jobIDs) == 0 {
+		return errors.New("no jobs specified")
+	}
+
+	if argStatsInterval <= 0 {
+		return errors.New("the refresh interval must be positive")
+	}
+
+	c, err := jobmanager.NewClient(argUserID, argServerHostPort)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if !argStatsWatch {
+		_, lastError := renderJobStats(cmd.Context(), c, jobIDs)
+		return lastError
+	}
+
+	ticker := time.NewTicker(argStatsInterval)
+	defer ticker.Stop()
+
+	for {
+		fmt.Print(clearScreen)
+
+		running, lastError := renderJobStats(cmd.Context(), c, jobIDs)
+		if running == 0 {
+			return lastError
+		}
+
+		if lastError != nil {
+			fmt.Fprintln(os.Stderr, lastError)
+		}
+
+		select {
+		case <-cmd.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// renderJobStats retrieves and displays the resource usage of the jobs with
+// the given jobIDs.  It returns the number of jobs whose usage it retrieved
+// and the last error encountered, if any.
+func renderJobStats(ctx context.Context, c *jobmanager.Client, jobIDs []string) (int, error) {
+	var lastError error
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"ID", "CPU Time", "Memory", "Peak Memory", "IO Read", "IO Write", "Pids"})
+
+	rendered := 0
+
+	for _, jobID := range jobIDs {
+		jobStats, err := queryJobStats(ctx, c, jobID)
+		if err != nil {
+			lastError = err
+			continue
+		}
+
+		table.Append([]string{
+			jobID,
+			jobStats.CpuUsage.Round(time.Millisecond).String(),
+			strconv.FormatUint(jobStats.MemoryUsage, 10),
+			strconv.FormatUint(jobStats.PeakMemoryUsage, 10),
+			strconv.FormatUint(jobStats.IoReadBytes, 10),
+			strconv.FormatUint(jobStats.IoWriteBytes, 10),
+			strconv.FormatUint(jobStats.Pids, 10),
+		})
+		rendered++
+	}
+
+	if rendered > 0 {
+		table.Render()
+	}
+
+	return rendered, lastError
+}
+
+func queryJobStats(ctx context.Context, c *jobmanager.Client, jobID string) (*jobmanager.JobStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, shortOperationTimeout)
+	defer cancel()
+
+	return c.Stats(ctx, jobID)
+}
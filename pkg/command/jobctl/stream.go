@@ -15,6 +15,7 @@ package jobctl
 
 import (
 	"errors"
+	"fmt"
 	"os"
 
 	"github.com/adalton/teleport-exercise/pkg/client/jobmanager"
@@ -62,9 +63,18 @@ func stream(cmd *cobra.Command, args []string) error {
 	}
 	defer c.Close()
 
+	c.OnTruncation = reportTruncation
+
 	if argStream == "stdout" {
 		return c.StreamStdout(cmd.Context(), args[0], os.Stdout)
 	}
 
 	return c.StreamStderr(cmd.Context(), args[0], os.Stderr)
 }
+
+// reportTruncation tells the user, on standard error, that the server
+// discarded the given number of bytes of output before they could be
+// streamed.
+func reportTruncation(truncated uint64) {
+	fmt.Fprintf(os.Stderr, "[%d bytes truncated]\n", truncated)
+}
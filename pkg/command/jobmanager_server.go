@@ -16,10 +16,15 @@ package command
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net"
+	"strings"
 
 	"github.com/adalton/teleport-exercise/certs"
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/config"
+	"github.com/adalton/teleport-exercise/pkg/jobmanager"
 	"github.com/adalton/teleport-exercise/server/jobmanager/serverv1"
 	"github.com/adalton/teleport-exercise/service/jobmanager/jobmanagerv1"
 
@@ -28,24 +33,97 @@ import (
 
 // RunJobmanagerServer runs a JobmanagerServer on the given network, listening
 // on the given address, with the given CA certificate and server certificate
-// and key.
+// and key.  If any of the default cgroup controllers is unavailable on this
+// host, the server logs a warning and runs without enforcing that limit.
 func RunJobmanagerServer(
 	ctx context.Context,
 	listener net.Listener,
 	caCert, serverCert, serverKey []byte,
+) error {
+	return RunJobmanagerServerDetailed(ctx, listener, caCert, serverCert, serverKey, false)
+}
+
+// RunJobmanagerServerDetailed is wrapped by RunJobmanagerServer and performs
+// the same operation.  If requireAllControllers is true, the server refuses to
+// start if any of the default cgroup controllers is unavailable on this host.
+func RunJobmanagerServerDetailed(
+	ctx context.Context,
+	listener net.Listener,
+	caCert, serverCert, serverKey []byte,
+	requireAllControllers bool,
 ) error {
 	tc, err := certs.NewServerTransportCredentials(caCert, serverCert, serverKey)
 	if err != nil {
 		return err
 	}
 
+	hierarchy, err := cgroup.Default()
+	if err != nil {
+		return fmt.Errorf("failed to detect cgroup hierarchy: %w", err)
+	}
+
+	// The hierarchy is shared with the jobs, which create their cgroups
+	// under the configured parent.
+	hierarchy.JobsParent = config.CgroupJobsParent
+
+	if err := jobmanager.ReserveServiceResources(hierarchy); err != nil {
+		return fmt.Errorf("failed to reserve resources for the server: %w", err)
+	}
+
+	// No job has been started yet, so any job cgroups are left over from a
+	// previous instance of the server.
+	if _, err := jobmanager.ReconcileOrphans(hierarchy); err != nil {
+		log.Printf("WARNING: failed to remove orphaned job cgroups: %v", err)
+	}
+
+	blockIO, err := jobmanager.ResolveBlockIOLimits(config.CgroupDefaultBlkioLimits)
+	if err != nil {
+		return fmt.Errorf("invalid block IO limits: %w", err)
+	}
+
+	knobs, err := jobmanager.ResolveKnobs(config.CgroupDefaultKnobs)
+	if err != nil {
+		return fmt.Errorf("invalid cgroup knobs: %w", err)
+	}
+
+	unavailable, err := jobmanager.PrepareKnobs(hierarchy, knobs)
+	if err != nil {
+		return fmt.Errorf("invalid cgroup knobs: %w", err)
+	}
+
+	if len(unavailable) > 0 && config.CgroupAllowJobKnobs {
+		log.Printf("WARNING: cgroup %s files do not exist; jobs may not set them: %s",
+			hierarchy.Version, strings.Join(unavailable, ", "))
+	}
+
+	if err := jobmanager.PrepareOutputBuffers(config.OutputBufferType, config.OutputSpoolDir,
+		config.OutputRingBufferCapacity); err != nil {
+		return fmt.Errorf("invalid job output configuration: %w", err)
+	}
+
+	manager, missing := jobmanager.NewManager(hierarchy, blockIO, knobs)
+	if len(missing) > 0 {
+		if requireAllControllers {
+			return fmt.Errorf("required cgroup %s controllers are unavailable: %s",
+				hierarchy.Version, strings.Join(missing, ", "))
+		}
+
+		log.Printf("WARNING: cgroup %s controllers are unavailable; "+
+			"their limits will not be enforced: %s",
+			hierarchy.Version, strings.Join(missing, ", "))
+	}
+
+	log.Printf("Using cgroup %s hierarchy at %s", hierarchy.Version, hierarchy.BasePath)
+
+	go jobmanager.FailedDestroys.Run(ctx, config.CgroupDestroyRetryInterval)
+
 	grpcServer := grpc.NewServer(
 		grpc.Creds(tc),
 		grpc.UnaryInterceptor(serverv1.UnaryGetUserIDFromContextInterceptor),
 		grpc.StreamInterceptor(serverv1.StreamGetUserIDFromContextInterceptor),
 	)
 
-	jobmanagerv1.RegisterJobManagerServer(grpcServer, serverv1.NewJobmanagerServer())
+	jobmanagerv1.RegisterJobManagerServer(grpcServer, serverv1.NewJobManagerServerDetailed(manager))
 
 	errChan := make(chan error)
 
@@ -14,9 +14,11 @@ limitations under the License.
 package config
 
 import (
-	"fmt"
 	"os"
 	"path"
+	"time"
+
+	"github.com/adalton/teleport-exercise/pkg/quantity"
 )
 
 // Note: Generally I would avoid having a "config.go" as a place for a bunch of
@@ -26,12 +28,26 @@ import (
 //       one place.
 var (
 	CgexecPath string
+
+	// CgexecArgs precede cgexec's own arguments when CgexecPath is run.
+	// They select the hidden subcommand of a binary, such as the server,
+	// that includes cgexec's functionality.
+	CgexecArgs []string
+)
+
+const (
+	// CgexecPathEnv is the environment variable that, if set, names the
+	// cgexec binary.
+	CgexecPathEnv = "CGEXEC_PATH"
+
+	// SelfExecPath is the path through which a process re-executes its own
+	// binary, even if the binary has since been replaced or removed.
+	SelfExecPath = "/proc/self/exe"
 )
 
 // init sets CgexecPath based on the position of the current executable
 func init() {
-	if dir, ok := os.LookupEnv("CGEXEC_PATH"); ok {
-		fmt.Println(dir)
+	if dir, ok := os.LookupEnv(CgexecPathEnv); ok {
 		CgexecPath = dir
 		return
 	}
@@ -46,10 +62,168 @@ func init() {
 	CgexecPath = path.Dir(exe) + binaryName
 }
 
+// UseSelfExec directs jobs to be started by re-executing the current binary
+// with the given subcommand, which must provide cgexec's functionality,
+// rather than by running a separate cgexec binary.  It has no effect if
+// CgexecPathEnv is set.
+func UseSelfExec(subcommand string) {
+	if _, ok := os.LookupEnv(CgexecPathEnv); ok {
+		return
+	}
+
+	CgexecPath = SelfExecPath
+	CgexecArgs = []string{subcommand}
+}
+
+// CgexecInit runs cgexec as the init process of each job's PID namespace, so
+// that orphaned processes are reaped and signals sent to the job's top-level
+// process are forwarded to the job's program.  Otherwise, the program itself
+// is the init process.
+//
+// The init process is charged to the job's memory cgroup for the memory that
+// it uses once it has joined the job's cgroups, a few hundred KiB, which counts
+// against the job's memory limit.  Its resident set, which includes the pages
+// of its executable, is far larger, so it exempts itself from the OOM killer,
+// which requires CAP_SYS_RESOURCE; otherwise, it would be the first process
+// killed when the job reached its memory limit, and its death would end the
+// job.  The job's program does not inherit the exemption: the init process
+// starts it through a copy of cgexec that restores the original score.
+var CgexecInit = false
+
+// Values of OutputBufferType.
 const (
-	CgroupDefaultCpuLimit        = 0.5
-	CgroupDefaultMemoryLimit     = "2M"
-	CgroupDefaultBlkioDevice     = "8:16"
-	CgroupDefaultBlkioWriteLimit = CgroupDefaultBlkioDevice + " 20971520"
-	CgroupDefaultBlkioReadLimit  = CgroupDefaultBlkioDevice + " 41943040"
+	// OutputBufferMemory keeps the output of jobs in the server's memory.
+	OutputBufferMemory = "memory"
+
+	// OutputBufferFile spools the output of jobs to files in OutputSpoolDir.
+	OutputBufferFile = "file"
+
+	// OutputBufferRing keeps only the most recent OutputRingBufferCapacity
+	// bytes of each job's standard output and standard error in the
+	// server's memory.  Clients that stream discarded output are told, in
+	// a message of its own, how many bytes were discarded.
+	OutputBufferRing = "ring"
+)
+
+// OutputBufferType selects where the server keeps the output of jobs, and of
+// processes started in jobs, so that clients can stream it.
+var OutputBufferType = OutputBufferMemory
+
+// OutputSpoolDir is the directory in which the output of jobs is spooled if
+// OutputBufferType is OutputBufferFile.  The server creates it if it does not
+// exist.  Empty uses the default directory for temporary files.
+var OutputSpoolDir = ""
+
+// OutputRingBufferCapacity is the number of bytes of each output stream that
+// is retained if OutputBufferType is OutputBufferRing.
+var OutputRingBufferCapacity = 1 * quantity.Mi
+
+const (
+	CgroupDefaultCpuLimit    = 0.5
+	CgroupDefaultCpuPeriodUs = 100000
+	CgroupDefaultMemoryLimit = 2 * quantity.Mi
+	CgroupDefaultPidsLimit   = 1024
+)
+
+// CgroupMaxCpuLimit is the largest CPU quota, in CPUs, that clients may request
+// for a job.  Zero leaves the CPU quota uncapped.
+var CgroupMaxCpuLimit = 2.0
+
+// CgroupAllowUnlimitedCpus permits clients to request that their jobs have no
+// CPU quota at all, regardless of CgroupMaxCpuLimit.
+var CgroupAllowUnlimitedCpus = true
+
+// CgroupMaxMemoryLimit is the largest memory limit, and memory+swap limit,
+// that clients may request for a job.  Zero leaves those limits uncapped.
+var CgroupMaxMemoryLimit = 1 * quantity.Gi
+
+// CgroupMaxPidsLimit is the largest pids limit that clients may request for a
+// job.  Zero leaves the pids limit uncapped.
+var CgroupMaxPidsLimit = uint64(4096)
+
+// BlkioLimit is a set of block IO throttling limits, in bytes per second
+// (Bps) and in operations per second (Iops).  Path is either a block device
+// node or any path on a filesystem; in the latter case the limits apply to
+// the device that holds the filesystem.  A zero limit leaves that direction
+// unthrottled.
+type BlkioLimit struct {
+	Path      string
+	ReadBps   quantity.Bytes
+	WriteBps  quantity.Bytes
+	ReadIops  uint64
+	WriteIops uint64
+}
+
+// CgroupDefaultBlkioLimits are the block IO limits applied to every job.
+// Each Path must resolve to a different block device.
+var CgroupDefaultBlkioLimits = []BlkioLimit{
+	{Path: "/", ReadBps: 40 * quantity.Mi, WriteBps: 20 * quantity.Mi},
+}
+
+// UserLimits are aggregate limits on the combined resource usage of all of
+// one user's jobs, enforced by a per-user parent cgroup.  A zero limit leaves
+// that resource unlimited.
+type UserLimits struct {
+	Cpus        float64
+	MemoryLimit quantity.Bytes
+	MaxPids     uint64
+}
+
+// CgroupDefaultUserLimits are the aggregate limits for users that have no
+// entry in CgroupUserLimits.
+var CgroupDefaultUserLimits = UserLimits{Cpus: 2, MemoryLimit: 64 * quantity.Mi, MaxPids: 4096}
+
+// CgroupUserLimits overrides CgroupDefaultUserLimits for individual users.
+var CgroupUserLimits = map[string]UserLimits{}
+
+// CgroupKnob is a cgroup interface file, such as "memory.swappiness", and the
+// value to write to it.
+type CgroupKnob struct {
+	File  string
+	Value string
+}
+
+// CgroupAllowedKnobs are the cgroup interface files that may be written with
+// CgroupKnobs.  Each must have the form "<controller>.<name>".  The server
+// checks the files against its cgroup hierarchy at startup: it refuses to
+// start if one of CgroupDefaultKnobs does not exist, and jobs may not request
+// the allowed files that do not exist.
+var CgroupAllowedKnobs = []string{
+	"cpu.idle",
+	"memory.oom.group",
+	"memory.swappiness",
+	"memory.zswap.max",
+}
+
+// CgroupDefaultKnobs are written to every job's cgroups after its limits have
+// been applied.
+var CgroupDefaultKnobs = []CgroupKnob{}
+
+// CgroupAllowJobKnobs permits clients to request knobs, from among
+// CgroupAllowedKnobs, for their jobs.
+var CgroupAllowJobKnobs = false
+
+// CgroupServiceCgroup is the cgroup, relative to the root of the cgroup
+// hierarchy, into which the server moves itself at startup so that it is
+// isolated from the jobs.  Empty leaves the server where it is.
+var CgroupServiceCgroup = "jobmanager"
+
+// CgroupJobsParent is the cgroup, relative to the root of the cgroup
+// hierarchy, under which job cgroups are created.  It must not be within
+// CgroupServiceCgroup, nor the reverse.  When the server runs in a systemd
+// unit with Delegate=yes, both should be within the unit's cgroup (e.g.,
+// "system.slice/jobmanager.service/server" and
+// "system.slice/jobmanager.service/jobs").
+var CgroupJobsParent = "jobs"
+
+// CgroupReservedCpus and CgroupReservedMemory are the CPU capacity, in CPUs,
+// and the memory that are held back from all of the jobs combined so that
+// the server remains responsive.  Zero reserves nothing.
+var (
+	CgroupReservedCpus   = 0.5
+	CgroupReservedMemory = 64 * quantity.Mi
 )
+
+// CgroupDestroyRetryInterval is how often the server retries removing the
+// cgroups of terminated jobs that it failed to remove when they terminated.
+var CgroupDestroyRetryInterval = 30 * time.Second
@@ -14,6 +14,8 @@ limitations under the License.
 package io
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"sync"
 	"sync/atomic"
@@ -23,11 +25,27 @@ import (
 // will read from the underlying buffer at at once.
 const DefaultMaxBufferSize = 1024
 
-// ByteStream enables clients to stream bytes from an OutputBuffer.
+// Chunk is the next part of a ByteStream: either some of the buffer's Data,
+// or the number of bytes that the buffer Truncated -- that is, discarded
+// before they could be streamed (see RingBuffer).
+type Chunk struct {
+	Data      []byte
+	Truncated int64
+}
+
+// TruncationMarker returns the text that Stream sends in place of a run of
+// truncated bytes.
+func TruncationMarker(truncated int64) []byte {
+	return []byte(fmt.Sprintf("[%d bytes truncated]\n", truncated))
+}
+
+// ByteStream enables clients to stream bytes from an OutputBuffer.  A
+// ByteStream is consumed with either Stream or Chunks, but not both.
 type ByteStream struct {
 	startGoroutine sync.Once
 	buffer         OutputBuffer
 	channel        chan []byte
+	chunks         chan Chunk
 	maxReadSize    int
 	closed         int32 // 0 = not closed, 1 = closed
 }
@@ -43,17 +61,47 @@ func NewByteStream(buffer OutputBuffer) *ByteStream {
 func NewByteStreamDetailed(buffer OutputBuffer, maxReadSize int) *ByteStream {
 	return &ByteStream{
 		channel:     make(chan []byte),
+		chunks:      make(chan Chunk),
 		buffer:      buffer,
 		maxReadSize: maxReadSize,
 	}
 }
 
-// Stream returns a chanel that streams the content of the underlying OutputBuffer.
+// Stream returns a chanel that streams the content of the underlying
+// OutputBuffer.  Each run of bytes that the buffer discards before they can
+// be streamed is replaced with a TruncationMarker; readers that must tell the
+// marker apart from the content should use Chunks.
 func (b *ByteStream) Stream() <-chan []byte {
+	b.start(func(chunk Chunk) {
+		if chunk.Truncated != 0 {
+			b.channel <- TruncationMarker(chunk.Truncated)
+		} else {
+			b.channel <- chunk.Data
+		}
+	})
+
+	return b.channel
+}
+
+// Chunks returns a channel that streams the content of the underlying
+// OutputBuffer, along with a Chunk that reports each run of bytes that the
+// buffer discards before they can be streamed.
+func (b *ByteStream) Chunks() <-chan Chunk {
+	b.start(func(chunk Chunk) {
+		b.chunks <- chunk
+	})
+
+	return b.chunks
+}
+
+// start starts, if it has not already been started, the goroutine that reads
+// the underlying OutputBuffer and passes each Chunk to send.
+func (b *ByteStream) start(send func(chunk Chunk)) {
 
 	b.startGoroutine.Do(func() {
 		go func() {
 			defer close(b.channel)
+			defer close(b.chunks)
 
 			var nextByte int64
 			readBuffer := make([]byte, b.maxReadSize)
@@ -76,7 +124,19 @@ func (b *ByteStream) Stream() <-chan []byte {
 					return
 				}
 
-				if n, err := b.buffer.ReadAt(readBuffer, nextByte); err != nil {
+				var truncated *TruncatedError
+
+				if n, err := b.buffer.ReadAt(readBuffer, nextByte); errors.As(err, &truncated) {
+					// The bytes at nextByte were discarded before this
+					// stream could read them; skip to the oldest byte
+					// that the buffer retains.
+					send(Chunk{Truncated: truncated.Truncated()})
+					nextByte = truncated.Start
+				} else if errors.Is(err, ErrReleased) {
+					// The buffer's content has been discarded; there is
+					// nothing more to stream.
+					return
+				} else if err != nil {
 					// If ReadAt fails, we'l assume the buffer is in a bad state
 					// and that future reads would also fail.
 					//
@@ -92,7 +152,7 @@ func (b *ByteStream) Stream() <-chan []byte {
 					bufToWrite := make([]byte, n)
 					copy(bufToWrite, readBuffer[0:n])
 
-					b.channel <- bufToWrite
+					send(Chunk{Data: bufToWrite})
 					nextByte += int64(n)
 				}
 
@@ -103,8 +163,6 @@ func (b *ByteStream) Stream() <-chan []byte {
 		}()
 
 	})
-
-	return b.channel
 }
 
 // Close marks this ByteStream as closed.  If there is a goroutine associated with
@@ -129,6 +187,7 @@ func (b *ByteStream) Close() {
 	// thing that could close it.
 	if !goroutineWasStarted {
 		close(b.channel)
+		close(b.chunks)
 		return
 	}
 
@@ -148,10 +207,21 @@ func (b *ByteStream) Close() {
 	// unblock the goroutine that was blocked on a write to the channel, enable
 	// it to see that this ByteStream is closed, and close the channel.
 	//
-	// Once we know the channel is closed, then we can safely return.
-	_, channelOpen := <-b.channel
-	for channelOpen {
-		_, channelOpen = <-b.channel
+	// The goroutine writes to only one of the channels, but closes both.
+	// Once we know that both are closed, then we can safely return.
+	channel, chunks := b.channel, b.chunks
+	for channel != nil || chunks != nil {
+		select {
+		case _, open := <-channel:
+			if !open {
+				channel = nil
+			}
+
+		case _, open := <-chunks:
+			if !open {
+				chunks = nil
+			}
+		}
 	}
 }
 
@@ -124,3 +124,79 @@ func Test_ByteStream_DoubleClose(t *testing.T) {
 	_, ok := <-stream
 	assert.False(t, ok)
 }
+
+func Test_ByteStream_Truncated(t *testing.T) {
+	buffer := io.NewRingBuffer(4)
+	stream := io.NewByteStream(buffer)
+
+	buffer.Write([]byte("abcdefghij"))
+	buffer.Close()
+
+	output := make([]byte, 0)
+	for content := range stream.Stream() {
+		output = append(output, content...)
+	}
+
+	// The discarded bytes are replaced with a marker
+	assert.Equal(t, []byte("[6 bytes truncated]\nghij"), output)
+}
+
+func Test_ByteStream_Chunks_Truncated(t *testing.T) {
+	buffer := io.NewRingBuffer(4)
+	stream := io.NewByteStream(buffer)
+
+	buffer.Write([]byte("abcdefghij"))
+	buffer.Close()
+
+	var chunks []io.Chunk
+	for chunk := range stream.Chunks() {
+		chunks = append(chunks, chunk)
+	}
+
+	assert.Equal(t, []io.Chunk{{Truncated: 6}, {Data: []byte("ghij")}}, chunks)
+}
+
+func Test_ByteStream_Chunks_TruncatedWhileStreaming(t *testing.T) {
+	buffer := io.NewRingBuffer(4)
+	stream := io.NewByteStream(buffer).Chunks()
+
+	buffer.Write([]byte("ab"))
+	assert.Equal(t, io.Chunk{Data: []byte("ab")}, <-stream)
+
+	// The reader falls behind by more than the buffer's capacity
+	buffer.Write([]byte("cdefgh"))
+	assert.Equal(t, io.Chunk{Truncated: 2}, <-stream)
+	assert.Equal(t, io.Chunk{Data: []byte("efgh")}, <-stream)
+
+	buffer.Close()
+	_, ok := <-stream
+	assert.False(t, ok)
+}
+
+func Test_ByteStream_Chunks_Close(t *testing.T) {
+	buffer := io.NewMemoryBuffer()
+	bstream := io.NewByteStream(buffer)
+	stream := bstream.Chunks()
+
+	buffer.Write([]byte("hello"))
+
+	// Close must not block even though the chunk was never received
+	bstream.Close()
+
+	for range stream {
+	}
+}
+
+func Test_ByteStream_Released(t *testing.T) {
+	buffer := io.NewMemoryBuffer()
+	stream := io.NewByteStream(buffer).Stream()
+
+	buffer.Write([]byte("hello"))
+	assert.Equal(t, []byte("hello"), <-stream)
+
+	buffer.Release()
+
+	// Once its buffer is released, the stream ends
+	_, ok := <-stream
+	assert.False(t, ok)
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package io
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileBuffer is a buffer of bytes that is spooled to a file.  Like a
+// MemoryBuffer, it keeps the data even after it has been read to enable
+// multiple clients to read what is written to this buffer, but it does not
+// hold that data in memory.
+//
+// The spool file is created in the buffer's directory on the first write, and
+// is removed from the directory as soon as it is created so that it does not
+// outlive the process.  Its space is reclaimed when the FileBuffer is
+// released.
+type FileBuffer struct {
+	mutex    sync.RWMutex
+	waitCond *sync.Cond
+	dir      string
+	file     *os.File
+	size     int64
+	closed   bool
+	released bool
+}
+
+// NewFileBuffer creates and returns a FileBuffer that spools to a file in
+// the given directory.  If dir is empty, the default directory for temporary
+// files is used.
+func NewFileBuffer(dir string) *FileBuffer {
+	b := &FileBuffer{
+		dir: dir,
+	}
+
+	b.waitCond = sync.NewCond(&b.mutex)
+
+	return b
+}
+
+// Write appends newContent to this FileBuffer.  Compatible with io.Writer.
+func (b *FileBuffer) Write(newContent []byte) (bytesWritten int, err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.closed {
+		return 0, fmt.Errorf("cannot write to a closed FileBuffer")
+	}
+
+	if len(newContent) == 0 {
+		return 0, nil
+	}
+
+	if b.file == nil {
+		if err := b.createSpoolFile(); err != nil {
+			return 0, err
+		}
+	}
+
+	bytesWritten, err = b.file.WriteAt(newContent, b.size)
+	if bytesWritten > 0 {
+		b.size += int64(bytesWritten)
+		b.waitCond.Broadcast()
+	}
+
+	return bytesWritten, err
+}
+
+// createSpoolFile creates the file to which this FileBuffer spools and
+// removes it from its directory.  The caller must hold the lock.
+func (b *FileBuffer) createSpoolFile() error {
+	file, err := os.CreateTemp(b.dir, "jobmanager-output-*")
+	if err != nil {
+		return fmt.Errorf("failed to create output spool file: %w", err)
+	}
+
+	if err := os.Remove(file.Name()); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to remove output spool file from its directory: %w", err)
+	}
+
+	b.file = file
+
+	return nil
+}
+
+// ReadAt reads len(p) bytes into outputBuffer starting at the given offset in
+// the underlying file.  It returns the number of bytes read
+// (0 <= bytesRead <= len(outputBuffer)).  This will return an error if the
+// given offset is greater than the current buffer size.
+// Compatible with io.ReadAt.
+func (b *FileBuffer) ReadAt(outputBuffer []byte, offset int64) (bytesRead int, err error) {
+	if len(outputBuffer) == 0 {
+		return 0, nil
+	}
+
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	if b.released {
+		return 0, ErrReleased
+	}
+
+	if offset > b.size {
+		return 0, fmt.Errorf("offset '%d' is greater than buffer size '%d'", offset, b.size)
+	}
+
+	if remaining := b.size - offset; int64(len(outputBuffer)) > remaining {
+		outputBuffer = outputBuffer[:remaining]
+	}
+
+	if len(outputBuffer) == 0 {
+		return 0, nil
+	}
+
+	// Only the bytes that have been written are read, so a short read is
+	// an error
+	return b.file.ReadAt(outputBuffer, offset)
+}
+
+// Close closes this FileBuffer.  Once the FileBuffer is closed, it will
+// accept no additional writes, but its content can still be read until the
+// FileBuffer is released.  The returned error is always nil.
+func (b *FileBuffer) Close() error {
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.closed = true
+	b.waitCond.Broadcast()
+
+	return nil
+}
+
+// Release closes this FileBuffer and closes its spool file, which frees the
+// space that the file occupies.
+func (b *FileBuffer) Release() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.closed = true
+	b.released = true
+	b.waitCond.Broadcast()
+
+	if b.file == nil {
+		return nil
+	}
+
+	err := b.file.Close()
+	b.file = nil
+
+	return err
+}
+
+// Size returns the current size of this FileBuffer.
+func (b *FileBuffer) Size() int64 {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.size
+}
+
+// Closed returns true if this FileBuffer has been closed, false otherwise.
+func (b *FileBuffer) Closed() bool {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.closed
+}
+
+// waitForChange blocks waiting for a change to this file buffer.  The given
+// size is the last known buffer size.  This function unblocks if:
+// * The size is less than the current size of the buffer
+// * The buffer is closed.
+func (b *FileBuffer) waitForChange(size int64) (newBufferSize int64, closed bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for !b.closed && size == b.size {
+		b.waitCond.Wait()
+	}
+
+	return b.size, b.closed
+}
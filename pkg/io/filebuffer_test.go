@@ -0,0 +1,217 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package io_test
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/io"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FileBuffer_InitialSizeZero(t *testing.T) {
+	b := io.NewFileBuffer(t.TempDir())
+
+	assert.Equal(t, int64(0), b.Size())
+}
+
+func Test_FileBuffer_ReadAt_FromEmptyBuffer(t *testing.T) {
+	b := io.NewFileBuffer(t.TempDir())
+	result := make([]byte, 64)
+
+	bytesRead, err := b.ReadAt(result, 0)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, bytesRead)
+}
+
+func Test_FileBuffer_Write_EmptyBuffer(t *testing.T) {
+	b := io.NewFileBuffer(t.TempDir())
+
+	bytesWritten, err := b.Write([]byte(""))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, bytesWritten)
+}
+
+func Test_FileBuffer_WriteAfterClose(t *testing.T) {
+	b := io.NewFileBuffer(t.TempDir())
+
+	err := b.Close()
+	assert.Nil(t, err)
+	assert.True(t, b.Closed())
+
+	_, err = b.Write([]byte(""))
+
+	assert.Error(t, err)
+}
+
+func Test_FileBuffer_Write_MissingDirectory(t *testing.T) {
+	b := io.NewFileBuffer(t.TempDir() + "/missing")
+
+	_, err := b.Write([]byte("hello"))
+
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "failed to create output spool file")
+	}
+	assert.Equal(t, int64(0), b.Size())
+}
+
+func Test_FileBuffer_SpoolFileNotVisible(t *testing.T) {
+	dir := t.TempDir()
+	b := io.NewFileBuffer(dir)
+
+	_, err := b.Write([]byte("hello"))
+	require.Nil(t, err)
+
+	entries, err := os.ReadDir(dir)
+
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+}
+
+func Test_FileBuffer_ReadAt_FromMiddle(t *testing.T) {
+	b := io.NewFileBuffer(t.TempDir())
+	result := make([]byte, 7)
+
+	b.Write([]byte("abcdefghijklmnopqrstuvwxyz"))
+
+	bytesRead, err := b.ReadAt(result, 2)
+
+	assert.Nil(t, err)
+	assert.Equal(t, len(result), bytesRead)
+	assert.Equal(t, []byte("cdefghi"), result)
+}
+
+func Test_FileBuffer_ReadAt_FromEnd(t *testing.T) {
+	b := io.NewFileBuffer(t.TempDir())
+	result := make([]byte, 7)
+
+	b.Write([]byte("abcdefghijklmnopqrstuvwxyz"))
+
+	bytesRead, err := b.ReadAt(result, 26-3)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, bytesRead)
+	assert.Equal(t, []byte("xyz"), result[0:bytesRead])
+}
+
+func Test_FileBuffer_ReadAt_AcrossWrites(t *testing.T) {
+	b := io.NewFileBuffer(t.TempDir())
+	result := make([]byte, 26)
+
+	b.Write([]byte("abcdefghijklm"))
+	b.Write([]byte("nopqrstuvwxyz"))
+
+	bytesRead, err := b.ReadAt(result, 0)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 26, bytesRead)
+	assert.Equal(t, []byte("abcdefghijklmnopqrstuvwxyz"), result)
+}
+
+func Test_FileBuffer_ReadAt_AfterClose(t *testing.T) {
+	b := io.NewFileBuffer(t.TempDir())
+	result := make([]byte, 5)
+
+	b.Write([]byte("hello"))
+	b.Close()
+
+	bytesRead, err := b.ReadAt(result, 0)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("hello"), result[0:bytesRead])
+}
+
+func Test_FileBuffer_ReadAt_OffsetEqualToSize(t *testing.T) {
+	b := io.NewFileBuffer(t.TempDir())
+	content := []byte("abcdefghijklmnopqrstuvwxyz")
+	result := make([]byte, 5)
+
+	b.Write(content)
+
+	bytesRead, err := b.ReadAt(result, int64(len(content)))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, bytesRead)
+}
+
+func Test_FileBuffer_ReadAt_OffsetGreaterThanSize(t *testing.T) {
+	b := io.NewFileBuffer(t.TempDir())
+	content := []byte("abcdefghijklmnopqrstuvwxyz")
+	result := make([]byte, 5)
+
+	b.Write(content)
+
+	_, err := b.ReadAt(result, int64(len(content)+1))
+
+	assert.Error(t, err)
+}
+
+func Test_FileBuffer_ByteStream(t *testing.T) {
+	const iterationCount = 5
+	payload := []byte("hello")
+	output := make([]byte, 0, iterationCount*len(payload))
+
+	buffer := io.NewFileBuffer(t.TempDir())
+	stream := io.NewByteStreamDetailed(buffer, 3)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		for content := range stream.Stream() {
+			output = append(output, content...)
+		}
+		wg.Done()
+	}()
+
+	for i := 0; i < iterationCount; i++ {
+		buffer.Write(payload)
+	}
+
+	buffer.Close()
+	wg.Wait()
+
+	assert.Equal(t, []byte("hellohellohellohellohello"), output)
+}
+
+func Test_FileBuffer_Release(t *testing.T) {
+	b := io.NewFileBuffer(t.TempDir())
+	result := make([]byte, 5)
+
+	b.Write([]byte("hello"))
+
+	assert.Nil(t, b.Release())
+	assert.True(t, b.Closed())
+
+	_, err := b.ReadAt(result, 0)
+	assert.ErrorIs(t, err, io.ErrReleased)
+
+	_, err = b.Write([]byte("world"))
+	assert.Error(t, err)
+
+	// Releasing again does nothing
+	assert.Nil(t, b.Release())
+}
+
+func Test_FileBuffer_Release_NeverWritten(t *testing.T) {
+	b := io.NewFileBuffer(t.TempDir())
+
+	assert.Nil(t, b.Release())
+}
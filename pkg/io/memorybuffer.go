@@ -29,6 +29,7 @@ type MemoryBuffer struct {
 	waitCond *sync.Cond
 	content  []byte
 	closed   bool
+	released bool
 }
 
 // NewMemoryBuffer creates and returns a MemoryBuffer with an initial capacity
@@ -78,6 +79,10 @@ func (b *MemoryBuffer) ReadAt(outputBuffer []byte, offset int64) (bytesRead int,
 	b.mutex.RLock()
 	defer b.mutex.RUnlock()
 
+	if b.released {
+		return 0, ErrReleased
+	}
+
 	if offset > int64(len(b.content)) {
 		return 0, fmt.Errorf("offset '%d' is greater than buffer size '%d'", offset, len(b.content))
 	}
@@ -98,6 +103,20 @@ func (b *MemoryBuffer) Close() error {
 	return nil
 }
 
+// Release closes this MemoryBuffer and discards its content.  The returned
+// error is always nil.
+func (b *MemoryBuffer) Release() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.closed = true
+	b.released = true
+	b.content = nil
+	b.waitCond.Broadcast()
+
+	return nil
+}
+
 // Size returns the current size of this MemoryBuffer.
 func (b *MemoryBuffer) Size() int64 {
 	b.mutex.RLock()
@@ -149,3 +149,16 @@ func Test_MemoryBuffer_ReadAt_OffsetGreaterThanSize(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+func Test_MemoryBuffer_Release(t *testing.T) {
+	b := io.NewMemoryBuffer()
+	result := make([]byte, 5)
+
+	b.Write([]byte("hello"))
+
+	assert.Nil(t, b.Release())
+	assert.True(t, b.Closed())
+
+	_, err := b.ReadAt(result, 0)
+	assert.ErrorIs(t, err, io.ErrReleased)
+}
@@ -14,9 +14,14 @@ limitations under the License.
 package io
 
 import (
+	"errors"
 	goio "io"
 )
 
+// ErrReleased is returned when reading from an OutputBuffer that has been
+// released.
+var ErrReleased = errors.New("output buffer has been released")
+
 // OutputBuffer is an abstraction over buffers to which the job manager
 // can write output.
 type OutputBuffer interface {
@@ -24,5 +29,10 @@ type OutputBuffer interface {
 	goio.ReaderAt
 	goio.Closer
 
+	// Release closes the buffer, if it is not already closed, and frees the
+	// resources that hold its content.  Once it is released, reads fail
+	// with ErrReleased.
+	Release() error
+
 	waitForChange(nextByte int64) (bufferSize int64, closed bool)
 }
@@ -0,0 +1,222 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package io
+
+import (
+	"fmt"
+	"sync"
+)
+
+// TruncatedError is returned by RingBuffer.ReadAt when the requested offset
+// precedes the oldest byte that the buffer retains.
+type TruncatedError struct {
+	// Offset is the offset that was requested.
+	Offset int64
+
+	// Start is the offset of the oldest byte that the buffer retains.
+	Start int64
+}
+
+func (e *TruncatedError) Error() string {
+	return fmt.Sprintf("offset '%d' precedes the retained content, which starts at offset '%d'",
+		e.Offset, e.Start)
+}
+
+// Truncated returns the number of bytes between the requested offset and the
+// oldest byte that the buffer retains.
+func (e *TruncatedError) Truncated() int64 {
+	return e.Start - e.Offset
+}
+
+// RingBuffer is an in-memory buffer of bytes with a fixed capacity.  Once the
+// capacity is reached, each write discards the oldest bytes.  Offsets are
+// logical: they count every byte ever written to the buffer, so the offset of
+// a byte does not change as older bytes are discarded.
+type RingBuffer struct {
+	mutex    sync.RWMutex
+	waitCond *sync.Cond
+	content  []byte
+	start    int64
+	size     int64
+	closed   bool
+	released bool
+}
+
+// NewRingBuffer creates and returns a RingBuffer that retains the most recent
+// capacity bytes written to it.  The capacity must be positive.
+func NewRingBuffer(capacity int) *RingBuffer {
+	if capacity <= 0 {
+		panic(fmt.Sprintf("invalid RingBuffer capacity '%d'", capacity))
+	}
+
+	b := &RingBuffer{
+		content: make([]byte, capacity),
+	}
+
+	b.waitCond = sync.NewCond(&b.mutex)
+
+	return b
+}
+
+// Write appends newContent to this RingBuffer, discarding the oldest bytes if
+// the buffer is full.  The returned bytesWritten is always len(newContent).
+// Compatible with io.Writer.
+func (b *RingBuffer) Write(newContent []byte) (bytesWritten int, err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.closed {
+		return 0, fmt.Errorf("cannot write to a closed RingBuffer")
+	}
+
+	capacity := int64(len(b.content))
+	newSize := b.size + int64(len(newContent))
+
+	// Only the last capacity bytes of newContent can be retained
+	retained := newContent
+	if int64(len(retained)) > capacity {
+		retained = retained[int64(len(retained))-capacity:]
+	}
+
+	offset := newSize - int64(len(retained))
+	for len(retained) > 0 {
+		n := copy(b.content[offset%capacity:], retained)
+		retained = retained[n:]
+		offset += int64(n)
+	}
+
+	b.size = newSize
+	if b.size-b.start > capacity {
+		b.start = b.size - capacity
+	}
+
+	b.waitCond.Broadcast()
+
+	return len(newContent), nil
+}
+
+// ReadAt reads len(p) bytes into outputBuffer starting at the given logical
+// offset.  It returns the number of bytes read
+// (0 <= bytesRead <= len(outputBuffer)).  This will return an error if the
+// given offset is greater than the current buffer size, or a *TruncatedError
+// if the byte at the given offset has been discarded.
+// Compatible with io.ReadAt.
+func (b *RingBuffer) ReadAt(outputBuffer []byte, offset int64) (bytesRead int, err error) {
+	if len(outputBuffer) == 0 {
+		return 0, nil
+	}
+
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	if b.released {
+		return 0, ErrReleased
+	}
+
+	if offset > b.size {
+		return 0, fmt.Errorf("offset '%d' is greater than buffer size '%d'", offset, b.size)
+	}
+
+	if offset < b.start {
+		return 0, &TruncatedError{Offset: offset, Start: b.start}
+	}
+
+	capacity := int64(len(b.content))
+
+	if remaining := b.size - offset; int64(len(outputBuffer)) > remaining {
+		outputBuffer = outputBuffer[:remaining]
+	}
+
+	for len(outputBuffer) > 0 {
+		physical := offset % capacity
+		end := physical + int64(len(outputBuffer))
+		if end > capacity {
+			end = capacity
+		}
+
+		n := copy(outputBuffer, b.content[physical:end])
+		outputBuffer = outputBuffer[n:]
+		offset += int64(n)
+		bytesRead += n
+	}
+
+	return bytesRead, nil
+}
+
+// Close closes this RingBuffer.  Once the RingBuffer is closed, it will
+// accept no additional writes.  The returned error is always nil.
+func (b *RingBuffer) Close() error {
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.closed = true
+	b.waitCond.Broadcast()
+
+	return nil
+}
+
+// Release closes this RingBuffer and discards its content.  The returned
+// error is always nil.
+func (b *RingBuffer) Release() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.closed = true
+	b.released = true
+	b.content = nil
+	b.waitCond.Broadcast()
+
+	return nil
+}
+
+// Size returns the total number of bytes written to this RingBuffer,
+// including those that have been discarded.
+func (b *RingBuffer) Size() int64 {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.size
+}
+
+// Start returns the offset of the oldest byte that this RingBuffer retains.
+func (b *RingBuffer) Start() int64 {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.start
+}
+
+// Closed returns true if this RingBuffer has been closed, false otherwise.
+func (b *RingBuffer) Closed() bool {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.closed
+}
+
+// waitForChange blocks waiting for a change to this ring buffer.  The given
+// size is the last known buffer size.  This function unblocks if:
+// * The size is less than the current size of the buffer
+// * The buffer is closed.
+func (b *RingBuffer) waitForChange(size int64) (newBufferSize int64, closed bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for !b.closed && size == b.size {
+		b.waitCond.Wait()
+	}
+
+	return b.size, b.closed
+}
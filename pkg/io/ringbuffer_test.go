@@ -0,0 +1,138 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package io_test
+
+import (
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/io"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RingBuffer_InitialSizeZero(t *testing.T) {
+	b := io.NewRingBuffer(8)
+
+	assert.Equal(t, int64(0), b.Size())
+	assert.Equal(t, int64(0), b.Start())
+}
+
+func Test_RingBuffer_InvalidCapacity(t *testing.T) {
+	assert.Panics(t, func() { io.NewRingBuffer(0) })
+}
+
+func Test_RingBuffer_WriteAfterClose(t *testing.T) {
+	b := io.NewRingBuffer(8)
+
+	err := b.Close()
+	assert.Nil(t, err)
+	assert.True(t, b.Closed())
+
+	_, err = b.Write([]byte(""))
+
+	assert.Error(t, err)
+}
+
+func Test_RingBuffer_ReadAt_WithinCapacity(t *testing.T) {
+	b := io.NewRingBuffer(8)
+	result := make([]byte, 8)
+
+	b.Write([]byte("abcdef"))
+
+	bytesRead, err := b.ReadAt(result, 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("bcdef"), result[0:bytesRead])
+	assert.Equal(t, int64(0), b.Start())
+}
+
+func Test_RingBuffer_ReadAt_Wrapped(t *testing.T) {
+	b := io.NewRingBuffer(8)
+	result := make([]byte, 8)
+
+	b.Write([]byte("abcdef"))
+	b.Write([]byte("ghijkl"))
+
+	assert.Equal(t, int64(12), b.Size())
+	assert.Equal(t, int64(4), b.Start())
+
+	bytesRead, err := b.ReadAt(result, 4)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("efghijkl"), result[0:bytesRead])
+
+	bytesRead, err = b.ReadAt(result[0:3], 6)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("ghi"), result[0:bytesRead])
+}
+
+func Test_RingBuffer_Write_LargerThanCapacity(t *testing.T) {
+	b := io.NewRingBuffer(4)
+	result := make([]byte, 4)
+
+	b.Write([]byte("ab"))
+
+	bytesWritten, err := b.Write([]byte("cdefghij"))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 8, bytesWritten)
+	assert.Equal(t, int64(10), b.Size())
+	assert.Equal(t, int64(6), b.Start())
+
+	bytesRead, err := b.ReadAt(result, 6)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("ghij"), result[0:bytesRead])
+}
+
+func Test_RingBuffer_ReadAt_Truncated(t *testing.T) {
+	b := io.NewRingBuffer(4)
+	result := make([]byte, 4)
+
+	b.Write([]byte("abcdefghij"))
+
+	_, err := b.ReadAt(result, 2)
+
+	var truncated *io.TruncatedError
+	require.ErrorAs(t, err, &truncated)
+	assert.Equal(t, int64(2), truncated.Offset)
+	assert.Equal(t, int64(6), truncated.Start)
+	assert.Equal(t, int64(4), truncated.Truncated())
+}
+
+func Test_RingBuffer_ReadAt_OffsetGreaterThanSize(t *testing.T) {
+	b := io.NewRingBuffer(4)
+	result := make([]byte, 4)
+
+	b.Write([]byte("abc"))
+
+	_, err := b.ReadAt(result, 4)
+
+	assert.Error(t, err)
+}
+
+func Test_RingBuffer_Release(t *testing.T) {
+	b := io.NewRingBuffer(4)
+	result := make([]byte, 4)
+
+	b.Write([]byte("abc"))
+
+	assert.Nil(t, b.Release())
+	assert.True(t, b.Closed())
+
+	_, err := b.ReadAt(result, 0)
+	assert.ErrorIs(t, err, io.ErrReleased)
+}
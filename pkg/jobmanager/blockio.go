@@ -0,0 +1,191 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobmanager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/syscall"
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2"
+	"github.com/adalton/teleport-exercise/pkg/config"
+)
+
+// DeviceLimit is a set of block IO throttling limits, in bytes per second
+// (Bps) and in operations per second (Iops), on a single block device.
+// Device has the form "<major>:<minor>".  A zero limit leaves that direction
+// unthrottled.
+type DeviceLimit struct {
+	Device    string
+	ReadBps   uint64
+	WriteBps  uint64
+	ReadIops  uint64
+	WriteIops uint64
+}
+
+// ResolveBlockIOLimits resolves the path of each of the given limits to the
+// block device to which the limit applies.
+func ResolveBlockIOLimits(limits []config.BlkioLimit) ([]DeviceLimit, error) {
+	return ResolveBlockIOLimitsDetailed(nil, nil, limits)
+}
+
+// ResolveBlockIOLimitsDetailed is wrapped by ResolveBlockIOLimits and performs
+// the same operation using the given adapters.  If a path is not backed by a
+// known block device, or if more than one limit resolves to the same device,
+// it returns an error that wraps ErrInvalidArgument.
+func ResolveBlockIOLimitsDetailed(
+	osAdapter *os.Adapter,
+	syscallAdapter *syscall.Adapter,
+	limits []config.BlkioLimit,
+) ([]DeviceLimit, error) {
+	deviceLimits := make([]DeviceLimit, 0, len(limits))
+	pathByDevice := make(map[string]string)
+
+	for _, limit := range limits {
+		device, err := cgroup.ResolveBlockDevice(osAdapter, syscallAdapter, limit.Path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidArgument, err)
+		}
+
+		if other, exists := pathByDevice[device]; exists {
+			return nil, fmt.Errorf("%w: %s and %s both resolve to block device %s",
+				ErrInvalidArgument, other, limit.Path, device)
+		}
+		pathByDevice[device] = limit.Path
+
+		deviceLimits = append(deviceLimits, DeviceLimit{
+			Device:    device,
+			ReadBps:   uint64(limit.ReadBps),
+			WriteBps:  uint64(limit.WriteBps),
+			ReadIops:  limit.ReadIops,
+			WriteIops: limit.WriteIops,
+		})
+	}
+
+	return deviceLimits, nil
+}
+
+// deviceLimitStrings holds block IO limits in the "<major>:<minor> <value>"
+// form expected by the block IO controllers.
+type deviceLimitStrings struct {
+	readBps   []string
+	writeBps  []string
+	readIops  []string
+	writeIops []string
+}
+
+// newDeviceLimitStrings converts the given deviceLimits into
+// deviceLimitStrings.  Zero limits are omitted.
+func newDeviceLimitStrings(deviceLimits []DeviceLimit) deviceLimitStrings {
+	var limits deviceLimitStrings
+
+	for _, limit := range deviceLimits {
+		limits.readBps = appendDeviceLimit(limits.readBps, limit.Device, limit.ReadBps)
+		limits.writeBps = appendDeviceLimit(limits.writeBps, limit.Device, limit.WriteBps)
+		limits.readIops = appendDeviceLimit(limits.readIops, limit.Device, limit.ReadIops)
+		limits.writeIops = appendDeviceLimit(limits.writeIops, limit.Device, limit.WriteIops)
+	}
+
+	return limits
+}
+
+// appendDeviceLimit appends the "<major>:<minor> <value>" string for the
+// given device and value to deviceLimits, unless value is zero.
+func appendDeviceLimit(deviceLimits []string, device string, value uint64) []string {
+	if value == 0 {
+		return deviceLimits
+	}
+
+	return append(deviceLimits, fmt.Sprintf("%s %d", device, value))
+}
+
+// mergeDeviceLimits returns the entries of defaults for devices that are not
+// named in overrides, followed by the overrides.
+func mergeDeviceLimits(defaults, overrides []string) []string {
+	overridden := make(map[string]bool, len(overrides))
+	for _, override := range overrides {
+		overridden[deviceOf(override)] = true
+	}
+
+	var merged []string
+	for _, limit := range defaults {
+		if !overridden[deviceOf(limit)] {
+			merged = append(merged, limit)
+		}
+	}
+
+	return append(merged, overrides...)
+}
+
+// deviceOf returns the "<major>:<minor>" portion of the given device limit.
+func deviceOf(deviceLimit string) string {
+	device, _, _ := strings.Cut(deviceLimit, " ")
+
+	return device
+}
+
+// newBlockIOController returns the block IO controller for the given cgroup
+// version that enforces the given deviceLimits.
+func newBlockIOController(version cgroup.Version, deviceLimits []DeviceLimit) cgroup.Controller {
+	limits := newDeviceLimitStrings(deviceLimits)
+
+	if version == cgroup.V2 {
+		return &cgroupv2.IOController{
+			ReadBpsDevices:   limits.readBps,
+			WriteBpsDevices:  limits.writeBps,
+			ReadIopsDevices:  limits.readIops,
+			WriteIopsDevices: limits.writeIops,
+		}
+	}
+
+	return &cgroupv1.BlockIOController{
+		ReadBpsDevices:   limits.readBps,
+		WriteBpsDevices:  limits.writeBps,
+		ReadIopsDevices:  limits.readIops,
+		WriteIopsDevices: limits.writeIops,
+	}
+}
+
+// withBlockIOLimits returns a copy of the given block IO controller in which
+// the given deviceLimits replace the controller's limits for the same devices
+// and directions.  If controller is not a block IO controller, it returns
+// nil.
+func withBlockIOLimits(controller cgroup.Controller, deviceLimits []DeviceLimit) cgroup.Controller {
+	overrides := newDeviceLimitStrings(deviceLimits)
+
+	switch c := controller.(type) {
+	case *cgroupv1.BlockIOController:
+		updated := *c
+		updated.ReadBpsDevices = mergeDeviceLimits(c.ReadBpsDevices, overrides.readBps)
+		updated.WriteBpsDevices = mergeDeviceLimits(c.WriteBpsDevices, overrides.writeBps)
+		updated.ReadIopsDevices = mergeDeviceLimits(c.ReadIopsDevices, overrides.readIops)
+		updated.WriteIopsDevices = mergeDeviceLimits(c.WriteIopsDevices, overrides.writeIops)
+
+		return &updated
+
+	case *cgroupv2.IOController:
+		updated := *c
+		updated.ReadBpsDevices = mergeDeviceLimits(c.ReadBpsDevices, overrides.readBps)
+		updated.WriteBpsDevices = mergeDeviceLimits(c.WriteBpsDevices, overrides.writeBps)
+		updated.ReadIopsDevices = mergeDeviceLimits(c.ReadIopsDevices, overrides.readIops)
+		updated.WriteIopsDevices = mergeDeviceLimits(c.WriteIopsDevices, overrides.writeIops)
+
+		return &updated
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobmanager_test
+
+import (
+	gosyscall "syscall"
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os/ostest"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/syscall"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/syscall/syscalltest"
+	"github.com/adalton/teleport-exercise/pkg/config"
+	"github.com/adalton/teleport-exercise/pkg/jobmanager"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+// newResolveAdapters returns adapters for a host with two disks, 8:0 and
+// 259:0.  /home and /var reside on 8:0; /data resides on 259:0.
+func newResolveAdapters() (*os.Adapter, *syscall.Adapter) {
+	readFileMock := &ostest.ReadFileMock{
+		Files: map[string]string{
+			"/sys/dev/block/8:0/dev":   "8:0\n",
+			"/sys/dev/block/259:0/dev": "259:0\n",
+		},
+	}
+
+	statMock := &syscalltest.StatMock{
+		Files: map[string]gosyscall.Stat_t{
+			"/home":    {Mode: gosyscall.S_IFDIR, Dev: unix.Mkdev(8, 0)},
+			"/var":     {Mode: gosyscall.S_IFDIR, Dev: unix.Mkdev(8, 0)},
+			"/data":    {Mode: gosyscall.S_IFDIR, Dev: unix.Mkdev(259, 0)},
+			"/dev/shm": {Mode: gosyscall.S_IFDIR, Dev: unix.Mkdev(0, 24)},
+		},
+	}
+
+	return &os.Adapter{ReadFileFn: readFileMock.ReadFile},
+		&syscall.Adapter{StatFn: statMock.Stat}
+}
+
+func Test_ResolveBlockIOLimits_MultipleDevices(t *testing.T) {
+	osAdapter, syscallAdapter := newResolveAdapters()
+
+	limits, err := jobmanager.ResolveBlockIOLimitsDetailed(osAdapter, syscallAdapter,
+		[]config.BlkioLimit{
+			{Path: "/home", ReadBps: 1024, WriteBps: 2048},
+			{Path: "/data", WriteBps: 4096},
+		})
+
+	require.Nil(t, err)
+	assert.Equal(t, []jobmanager.DeviceLimit{
+		{Device: "8:0", ReadBps: 1024, WriteBps: 2048},
+		{Device: "259:0", WriteBps: 4096},
+	}, limits)
+}
+
+func Test_ResolveBlockIOLimits_UnknownDevice(t *testing.T) {
+	osAdapter, syscallAdapter := newResolveAdapters()
+
+	_, err := jobmanager.ResolveBlockIOLimitsDetailed(osAdapter, syscallAdapter,
+		[]config.BlkioLimit{{Path: "/dev/shm", ReadBps: 1024}})
+
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument)
+}
+
+func Test_ResolveBlockIOLimits_DuplicateDevice(t *testing.T) {
+	osAdapter, syscallAdapter := newResolveAdapters()
+
+	_, err := jobmanager.ResolveBlockIOLimitsDetailed(osAdapter, syscallAdapter,
+		[]config.BlkioLimit{
+			{Path: "/home", ReadBps: 1024},
+			{Path: "/var", WriteBps: 1024},
+		})
+
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument)
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobmanager
+
+import (
+	"fmt"
+
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2"
+	"github.com/adalton/teleport-exercise/pkg/config"
+)
+
+const (
+	// MinCpuWeight, DefaultCpuWeight and MaxCpuWeight describe the range of
+	// CPU weights.  The range is that of the cgroup v2 cpu.weight file; for
+	// cgroup v1, weights are scaled to cpu.shares.
+	MinCpuWeight     = 1
+	DefaultCpuWeight = 100
+	MaxCpuWeight     = 10000
+
+	// MinCpuPeriodUs and MaxCpuPeriodUs describe the range of CFS periods
+	// that the kernel accepts.
+	MinCpuPeriodUs = 1000
+	MaxCpuPeriodUs = 1000000
+
+	// MinCpuQuotaUs is the smallest CFS quota, the product of a job's CPUs
+	// and its period, that the kernel accepts.
+	MinCpuQuotaUs = 1000
+
+	defaultCpuShares = 1024
+	minCpuShares     = 2
+)
+
+// validateCpuLimits returns an error that wraps ErrInvalidArgument if the CPU
+// limits in the given JobLimits are out of range or contradictory.
+func validateCpuLimits(l *JobLimits) error {
+	if l.Cpus < 0 {
+		return fmt.Errorf("%w: cpus must not be negative: %g", ErrInvalidArgument, l.Cpus)
+	}
+
+	if l.Cpus > 0 && l.UnlimitedCpus {
+		return fmt.Errorf("%w: cpus cannot be combined with unlimited cpus", ErrInvalidArgument)
+	}
+
+	if l.UnlimitedCpus && !config.CgroupAllowUnlimitedCpus {
+		return fmt.Errorf("%w: unlimited cpus are not permitted by this server", ErrInvalidArgument)
+	}
+
+	if max := config.CgroupMaxCpuLimit; max > 0 && l.Cpus > max {
+		return fmt.Errorf("%w: cpus must not exceed %g: %g", ErrInvalidArgument, max, l.Cpus)
+	}
+
+	if l.CpuWeight != 0 && (l.CpuWeight < MinCpuWeight || l.CpuWeight > MaxCpuWeight) {
+		return fmt.Errorf("%w: cpu weight must be in the range [%d, %d]: %d",
+			ErrInvalidArgument, MinCpuWeight, MaxCpuWeight, l.CpuWeight)
+	}
+
+	if l.CpuPeriodUs != 0 && (l.CpuPeriodUs < MinCpuPeriodUs || l.CpuPeriodUs > MaxCpuPeriodUs) {
+		return fmt.Errorf("%w: cpu period must be in the range [%d, %d] microseconds: %d",
+			ErrInvalidArgument, MinCpuPeriodUs, MaxCpuPeriodUs, l.CpuPeriodUs)
+	}
+
+	if quotaUs := cpuQuotaUs(l); quotaUs != 0 && quotaUs < MinCpuQuotaUs {
+		return fmt.Errorf("%w: cpu quota (cpus times period) must be at least %d microseconds: %d",
+			ErrInvalidArgument, MinCpuQuotaUs, quotaUs)
+	}
+
+	return nil
+}
+
+// cpuQuotaUs returns the CFS quota, in microseconds, that the CPU limits in
+// the given JobLimits produce, with the server's defaults for those that are
+// not specified.  It returns zero if the job has no CPU quota, or if the
+// given JobLimits change neither the CPUs nor the period.
+func cpuQuotaUs(l *JobLimits) int64 {
+	if l.UnlimitedCpus || (l.Cpus == 0 && l.CpuPeriodUs == 0) {
+		return 0
+	}
+
+	cpus := l.Cpus
+	if cpus == 0 {
+		cpus = config.CgroupDefaultCpuLimit
+	}
+
+	periodUs := l.CpuPeriodUs
+	if periodUs == 0 {
+		periodUs = config.CgroupDefaultCpuPeriodUs
+	}
+
+	// The cpu controllers truncate the quota in the same way
+	return int64(cpus * float64(periodUs))
+}
+
+// hasCpuLimits returns true if any of the CPU limits in the given JobLimits
+// is specified.
+func hasCpuLimits(l *JobLimits) bool {
+	return l.Cpus > 0 || l.UnlimitedCpus || l.CpuWeight > 0 || l.CpuPeriodUs > 0
+}
+
+// withCpuLimits returns a copy of the given cpu controller with the CPU limits
+// in the given JobLimits applied.  If controller is not a cpu controller, it
+// returns nil.
+func withCpuLimits(controller cgroup.Controller, l *JobLimits) cgroup.Controller {
+	cpus := func(value float64) float64 {
+		if l.UnlimitedCpus {
+			return 0
+		}
+
+		if l.Cpus > 0 {
+			return l.Cpus
+		}
+
+		return value
+	}
+
+	switch c := controller.(type) {
+	case *cgroupv1.CpuController:
+		updated := *c
+		updated.Cpus = cpus(c.Cpus)
+		updated.PeriodUs = overrideLimit(c.PeriodUs, l.CpuPeriodUs)
+		updated.Shares = overrideLimit(c.Shares, cpuWeightToShares(l.CpuWeight))
+
+		return &updated
+
+	case *cgroupv2.CpuController:
+		updated := *c
+		updated.Cpus = cpus(c.Cpus)
+		updated.PeriodUs = overrideLimit(c.PeriodUs, l.CpuPeriodUs)
+		updated.Weight = overrideLimit(c.Weight, l.CpuWeight)
+
+		return &updated
+	}
+
+	return nil
+}
+
+// cpuWeightToShares converts the given CPU weight into the equivalent cgroup
+// v1 cpu.shares value, such that DefaultCpuWeight maps to the kernel's
+// default shares.  A zero weight converts to zero shares.
+func cpuWeightToShares(weight uint64) uint64 {
+	if weight == 0 {
+		return 0
+	}
+
+	shares := weight * defaultCpuShares / DefaultCpuWeight
+	if shares < minCpuShares {
+		shares = minCpuShares
+	}
+
+	return shares
+}
+
+// overrideLimit returns override if it is non-zero, value otherwise.
+func overrideLimit(value, override uint64) uint64 {
+	if override != 0 {
+		return override
+	}
+
+	return value
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobmanager
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2"
+)
+
+const (
+	ProcSelfStatusPath = "/proc/self/status"
+)
+
+// ValidateCpuset returns nil if cpus and mems are well-formed lists (e.g.,
+// "0-3,8") of CPUs and memory nodes that are available to the server.  Empty
+// lists are valid.
+func ValidateCpuset(cpus, mems string) error {
+	return ValidateCpusetDetailed(nil, cpus, mems)
+}
+
+// ValidateCpusetDetailed is wrapped by ValidateCpuset and performs the same
+// operation using the given osAdapter.  The available CPUs and memory nodes
+// are those that the server process itself is allowed to use, as reported by
+// /proc/self/status.  If either list is malformed or names an unavailable
+// CPU or memory node, it returns an error that wraps ErrInvalidArgument.
+func ValidateCpusetDetailed(osAdapter *os.Adapter, cpus, mems string) error {
+	if cpus == "" && mems == "" {
+		return nil
+	}
+
+	content, err := osAdapter.ReadFile(ProcSelfStatusPath)
+	if err != nil {
+		return err
+	}
+
+	status := parseProcStatus(content)
+
+	if err := validateList("CPU", cpus, status["Cpus_allowed_list"]); err != nil {
+		return err
+	}
+
+	return validateList("memory node", mems, status["Mems_allowed_list"])
+}
+
+// validateList returns nil if every member of the given list is also a member
+// of the given availableList.  The kind of the members is used in error
+// messages.
+func validateList(kind, list, availableList string) error {
+	if list == "" {
+		return nil
+	}
+
+	requested, err := parseList(list)
+	if err != nil {
+		return fmt.Errorf("%w: malformed %s list '%s': %v", ErrInvalidArgument, kind, list, err)
+	}
+
+	availableRanges, err := parseList(availableList)
+	if err != nil {
+		return fmt.Errorf("failed to determine the available %ss: %w", kind, err)
+	}
+
+	available := make(map[int]bool)
+	for _, r := range availableRanges {
+		for id := r.first; id <= r.last; id++ {
+			available[id] = true
+		}
+	}
+
+	// Each range is checked one member at a time, but the check stops at the
+	// first unavailable member, so the number of iterations is bounded by the
+	// number of available members.
+	for _, r := range requested {
+		for id := r.first; id <= r.last; id++ {
+			if !available[id] {
+				return fmt.Errorf("%w: %s %d is not available (available: %s)",
+					ErrInvalidArgument, kind, id, availableList)
+			}
+		}
+	}
+
+	return nil
+}
+
+// listRange is an inclusive range of members of a list.
+type listRange struct {
+	first int
+	last  int
+}
+
+// parseList parses a list in the kernel's list format, which is a
+// comma-separated sequence of numbers and inclusive ranges (e.g., "0-3,8").
+func parseList(list string) ([]listRange, error) {
+	var ranges []listRange
+
+	for _, element := range strings.Split(strings.TrimSpace(list), ",") {
+		firstStr, lastStr, isRange := strings.Cut(element, "-")
+		if !isRange {
+			lastStr = firstStr
+		}
+
+		first, err := strconv.ParseUint(firstStr, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid element '%s'", element)
+		}
+
+		last, err := strconv.ParseUint(lastStr, 10, 16)
+		if err != nil || last < first {
+			return nil, fmt.Errorf("invalid element '%s'", element)
+		}
+
+		ranges = append(ranges, listRange{first: int(first), last: int(last)})
+	}
+
+	return ranges, nil
+}
+
+// parseProcStatus parses the content of /proc/<pid>/status, in which each
+// line has the form "<key>:<whitespace><value>".
+func parseProcStatus(content []byte) map[string]string {
+	status := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		if key, value, found := strings.Cut(scanner.Text(), ":"); found {
+			status[key] = strings.TrimSpace(value)
+		}
+	}
+
+	return status
+}
+
+// withCpuset returns a copy of the given cpuset controller that restricts
+// the job to the given cpus and mems.  Empty lists keep the controller's
+// values.  If controller is not a cpuset controller, it returns nil.
+func withCpuset(controller cgroup.Controller, cpus, mems string) cgroup.Controller {
+	switch c := controller.(type) {
+	case *cgroupv1.CpusetController:
+		updated := *c
+		updated.Cpus = overrideString(c.Cpus, cpus)
+		updated.Mems = overrideString(c.Mems, mems)
+
+		return &updated
+
+	case *cgroupv2.CpusetController:
+		updated := *c
+		updated.Cpus = overrideString(c.Cpus, cpus)
+		updated.Mems = overrideString(c.Mems, mems)
+
+		return &updated
+	}
+
+	return nil
+}
+
+// overrideString returns override if it is non-empty, value otherwise.
+func overrideString(value, override string) string {
+	if override != "" {
+		return override
+	}
+
+	return value
+}
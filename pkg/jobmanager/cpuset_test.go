@@ -0,0 +1,73 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobmanager_test
+
+import (
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os/ostest"
+	"github.com/adalton/teleport-exercise/pkg/jobmanager"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newProcStatusAdapter() *os.Adapter {
+	readFileMock := &ostest.ReadFileMock{
+		Files: map[string]string{
+			jobmanager.ProcSelfStatusPath: `Name:	jobmanager
+Cpus_allowed:	f3
+Cpus_allowed_list:	0-1,4-7
+Mems_allowed:	00000000,00000003
+Mems_allowed_list:	0-1
+`,
+		},
+	}
+
+	return &os.Adapter{ReadFileFn: readFileMock.ReadFile}
+}
+
+func Test_ValidateCpuset_Valid(t *testing.T) {
+	adapter := newProcStatusAdapter()
+
+	assert.Nil(t, jobmanager.ValidateCpusetDetailed(adapter, "0,4-7", "1"))
+	assert.Nil(t, jobmanager.ValidateCpusetDetailed(adapter, "", ""))
+}
+
+func Test_ValidateCpuset_UnavailableCpu(t *testing.T) {
+	adapter := newProcStatusAdapter()
+
+	err := jobmanager.ValidateCpusetDetailed(adapter, "1-2", "")
+
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument)
+	assert.Contains(t, err.Error(), "CPU 2 is not available")
+}
+
+func Test_ValidateCpuset_UnavailableMem(t *testing.T) {
+	adapter := newProcStatusAdapter()
+
+	err := jobmanager.ValidateCpusetDetailed(adapter, "", "2")
+
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument)
+}
+
+func Test_ValidateCpuset_Malformed(t *testing.T) {
+	adapter := newProcStatusAdapter()
+
+	for _, cpus := range []string{"a", "1-", "3-1", "1,,2", "-1"} {
+		err := jobmanager.ValidateCpusetDetailed(adapter, cpus, "")
+
+		assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument, cpus)
+	}
+}
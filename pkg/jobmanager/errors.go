@@ -23,4 +23,7 @@ var (
 	ErrInvalidJobID    = errors.New("invalid job id")
 	ErrUnauthenticated = errors.New("unauthenticated")
 	ErrInvalidArgument = errors.New("invalid argument")
+	ErrJobNotRunning   = errors.New("job not running")
+	ErrFailedToStart   = errors.New("job failed to start")
+	ErrInvalidJobState = errors.New("invalid job state")
 )
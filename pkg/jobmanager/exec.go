@@ -0,0 +1,166 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobmanager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/adalton/teleport-exercise/pkg/io"
+	"github.com/adalton/teleport-exercise/pkg/startstatus"
+)
+
+// ExecProcess defines the interface to an additional process that runs in
+// the namespaces and cgroups of a running job; see Job.Exec.
+type ExecProcess interface {
+	// StdoutStream and StderrStream return ByteStreams associated with the
+	// standard output and standard error of the process.
+	StdoutStream() *io.ByteStream
+	StderrStream() *io.ByteStream
+
+	// Wait blocks until the process terminates and returns its status.
+	Wait() *ExecStatus
+
+	// Kill kills the process.  It does not wait for it to terminate.
+	Kill() error
+
+	// Release frees the resources that hold the process's output.  Once it
+	// is released, the process's output can no longer be streamed.
+	Release() error
+}
+
+// ExecStatus describes how an ExecProcess terminated.
+type ExecStatus struct {
+	// ExitCode is the exit status of the process, or 128 plus the number
+	// of the signal that killed it.
+	ExitCode int
+
+	// RunError describes why the process could not be run, if it could
+	// not.
+	RunError error
+}
+
+// concreteExecProcess implements the ExecProcess interface using cgexec's
+// join mode (see command.Cgexec).
+type concreteExecProcess struct {
+	cmd          *exec.Cmd
+	stdoutBuffer io.OutputBuffer
+	stderrBuffer io.OutputBuffer
+	done         chan struct{}
+	status       *ExecStatus
+}
+
+// startExecProcess starts cgexec to run the given program in the namespaces
+// of the process with the given pid, after joining the cgroups with the given
+// task files.
+func startExecProcess(
+	pid int,
+	taskFiles []string,
+	programPath string,
+	arguments []string,
+) (*concreteExecProcess, error) {
+	// See command.JoinOption
+	args := []string{fmt.Sprintf("--join=%d", pid)}
+	// cgexec inherits the status pipe as its first extra file
+	args = append(args, "--status-fd=3") // See command.StatusFdOption
+	args = append(args, taskFiles...)
+	args = append(args, "--")
+	args = append(args, programPath)
+	args = append(args, arguments...)
+
+	p := &concreteExecProcess{
+		cmd:          cgexecCommand(args...),
+		stdoutBuffer: newOutputBuffer(),
+		stderrBuffer: newOutputBuffer(),
+		done:         make(chan struct{}),
+	}
+	p.cmd.Stdout = p.stdoutBuffer
+	p.cmd.Stderr = p.stderrBuffer
+	p.cmd.Env = make([]string, 0) // Do not pass along our environment
+
+	// cgexec reports whether it started the program over the status pipe;
+	// see the startstatus package
+	statusReader, statusWriter, err := os.Pipe()
+	if err != nil {
+		return nil, errors.Join(err, p.Release())
+	}
+	defer statusReader.Close()
+	p.cmd.ExtraFiles = []*os.File{statusWriter}
+
+	err = p.cmd.Start()
+
+	// Our copy of the write end must be closed for the read to end
+	statusWriter.Close()
+	if err != nil {
+		return nil, errors.Join(err, p.Release())
+	}
+
+	if err := startstatus.Read(statusReader); err != nil {
+		// cgexec exits once it has reported the failure
+		_ = p.cmd.Wait()
+		return nil, errors.Join(fmt.Errorf("%w: %w", ErrFailedToStart, err), p.Release())
+	}
+
+	go func() {
+		err := p.cmd.Wait()
+
+		// Once Wait returns, all output has been written to the buffers
+		status := &ExecStatus{ExitCode: p.cmd.ProcessState.ExitCode()}
+
+		if ws, ok := p.cmd.ProcessState.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+			status.ExitCode = 128 + int(ws.Signal())
+		} else if _, exited := err.(*exec.ExitError); err != nil && !exited {
+			status.RunError = err
+		}
+
+		p.stdoutBuffer.Close()
+		p.stderrBuffer.Close()
+
+		p.status = status
+		close(p.done)
+	}()
+
+	return p, nil
+}
+
+func (p *concreteExecProcess) StdoutStream() *io.ByteStream {
+	return io.NewByteStream(p.stdoutBuffer)
+}
+
+func (p *concreteExecProcess) StderrStream() *io.ByteStream {
+	return io.NewByteStream(p.stderrBuffer)
+}
+
+func (p *concreteExecProcess) Wait() *ExecStatus {
+	<-p.done
+
+	return p.status
+}
+
+// Kill asks cgexec to kill the process; killing cgexec itself would leave
+// the process running in the job.
+func (p *concreteExecProcess) Kill() error {
+	if err := p.cmd.Process.Signal(syscall.SIGTERM); err != nil && err != os.ErrProcessDone {
+		return err
+	}
+
+	return nil
+}
+
+func (p *concreteExecProcess) Release() error {
+	return errors.Join(p.stdoutBuffer.Release(), p.stderrBuffer.Release())
+}
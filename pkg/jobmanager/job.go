@@ -14,15 +14,20 @@ limitations under the License.
 package jobmanager
 
 import (
+	"errors"
 	"fmt"
+	goio "io"
+	"log"
 	"os"
 	"os/exec"
 	"sync"
 	"syscall"
+	"time"
 
-	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
 	"github.com/adalton/teleport-exercise/pkg/config"
 	"github.com/adalton/teleport-exercise/pkg/io"
+	"github.com/adalton/teleport-exercise/pkg/startstatus"
 
 	"github.com/google/uuid"
 )
@@ -34,9 +39,134 @@ type JobStatus struct {
 	ID        string
 	Running   bool
 	Pid       int
+	State     JobState
 	ExitCode  int
 	SignalNum syscall.Signal
 	RunError  error
+
+	// PidsLimitReached is true if the job tried to create more processes
+	// than its pids limit allows.
+	PidsLimitReached bool
+
+	// TerminationReason describes why the job terminated.  It is
+	// TerminationReasonNone while the job is running.
+	TerminationReason TerminationReason
+
+	// OomKills is the number of the job's processes that the OOM killer
+	// has killed, and PeakMemoryUsage is the job's maximum memory usage in
+	// bytes.  Both are zero if the job has no memory cgroup.
+	OomKills        uint64
+	PeakMemoryUsage uint64
+
+	// Usage is the final resource usage of the job, recorded when it
+	// terminated.  It is nil while the job is running.
+	Usage *ResourceUsage
+}
+
+// ResourceUsage summarizes the resources that a job consumed over its
+// lifetime.
+type ResourceUsage struct {
+	// UserTime and SystemTime are the CPU time that the job's process and
+	// the descendants that it waited for spent in user and kernel mode.
+	UserTime   time.Duration
+	SystemTime time.Duration
+
+	// PeakMemoryUsage is the maximum memory usage of the job, in bytes.
+	PeakMemoryUsage uint64
+
+	// IoReadBytes and IoWriteBytes are the number of bytes that the job
+	// read from and wrote to block devices.
+	IoReadBytes  uint64
+	IoWriteBytes uint64
+
+	// MaxPids is the maximum number of processes and threads that existed
+	// in the job at any one time, or zero if the kernel does not report it.
+	MaxPids uint64
+}
+
+// JobStats describes the resource usage of a running job, as accounted by its
+// cgroups.  Usage that the job's cgroups do not account for is zero.
+type JobStats struct {
+	CpuUsage        time.Duration
+	MemoryUsage     uint64
+	PeakMemoryUsage uint64
+	IoReadBytes     uint64
+	IoWriteBytes    uint64
+	Pids            uint64
+}
+
+// JobState is the stage of its lifecycle that a job has reached.
+type JobState int
+
+const (
+	// JobStateCreated indicates that the job has been created but not yet
+	// started.
+	JobStateCreated JobState = iota
+
+	// JobStateRunning indicates that the job's program is running.
+	JobStateRunning
+
+	// JobStateTerminated indicates that the job has terminated, was stopped
+	// before it started, or failed to start.
+	JobStateTerminated
+)
+
+func (s JobState) String() string {
+	switch s {
+	case JobStateCreated:
+		return "created"
+	case JobStateRunning:
+		return "running"
+	case JobStateTerminated:
+		return "terminated"
+	}
+
+	return fmt.Sprintf("JobState(%d)", int(s))
+}
+
+// TerminationReason describes why a job terminated.
+type TerminationReason int
+
+const (
+	// TerminationReasonNone indicates that the job has not terminated.
+	TerminationReasonNone TerminationReason = iota
+
+	// TerminationReasonExited indicates that the job exited on its own.
+	TerminationReasonExited
+
+	// TerminationReasonSignaled indicates that the job was killed by a
+	// signal that was sent neither by Stop nor by the OOM killer.
+	TerminationReasonSignaled
+
+	// TerminationReasonStopped indicates that the job was killed by Stop.
+	TerminationReasonStopped
+
+	// TerminationReasonOutOfMemory indicates that the OOM killer killed the
+	// job because it reached its memory limit.
+	TerminationReasonOutOfMemory
+
+	// TerminationReasonFailedToStart indicates that the job's program was
+	// never started (e.g., because it does not exist).
+	TerminationReasonFailedToStart
+)
+
+func (r TerminationReason) String() string {
+	switch r {
+	case TerminationReasonNone:
+		return ""
+	case TerminationReasonExited:
+		return "exited"
+	case TerminationReasonSignaled:
+		return "killed: signal"
+	case TerminationReasonStopped:
+		return "killed: stopped"
+	case TerminationReasonOutOfMemory:
+		return "killed: out of memory"
+	case TerminationReasonFailedToStart:
+		return "failed to start"
+	}
+
+	return fmt.Sprintf("TerminationReason(%d)", int(r))
 }
 
 // concreteJob implements the Job interface and provides the production implementation
@@ -46,21 +176,43 @@ type concreteJob struct {
 	owner         string
 	id            uuid.UUID
 	name          string
-	cgControllers []cgroupv1.Controller
+	cgControllers []cgroup.Controller
 	programName   string
 	programArgs   []string
 	cmd           *exec.Cmd
+	hierarchy     *cgroup.Hierarchy
+	cgroupSet     cgroup.Set
+	oomWatcher    goio.Closer
 	stdoutBuffer  io.OutputBuffer
 	stderrBuffer  io.OutputBuffer
+	created       bool
 	running       bool
+	stopped       bool
 	runErrors     []error
+
+	// initProcess is true if cgexec ran as the init process of the job's
+	// PID namespace (see config.CgexecInit).
+	initProcess bool
+
+	// pidsLimitReached and peakMemoryUsage record the state of the job's
+	// cgroups when the job terminated, before they were destroyed.
+	pidsLimitReached bool
+	peakMemoryUsage  uint64
+
+	// oomKills is updated as the job's memory cgroup reports OOM events.
+	oomKills uint64
+
+	terminationReason TerminationReason
+	usage             *ResourceUsage
 }
 
 // NewJob creates and returns a new concreteJob based on the given values.
+// The job's output is kept in buffers of the kind selected by
+// config.OutputBufferType.
 func NewJob(
 	owner string,
 	name string,
-	cgControllers []cgroupv1.Controller,
+	cgControllers []cgroup.Controller,
 	programName string,
 	programArgs ...string,
 ) Job {
@@ -69,8 +221,8 @@ func NewJob(
 		owner,
 		name,
 		cgControllers,
-		io.NewMemoryBuffer(),
-		io.NewMemoryBuffer(),
+		newOutputBuffer(),
+		newOutputBuffer(),
 		programName,
 		programArgs...,
 	)
@@ -79,7 +231,7 @@ func NewJob(
 func NewJobDetailed(
 	owner string,
 	name string,
-	cgControllers []cgroupv1.Controller,
+	cgControllers []cgroup.Controller,
 	stdoutBuffer io.OutputBuffer,
 	stderrBuffer io.OutputBuffer,
 	programName string,
@@ -98,26 +250,68 @@ func NewJobDetailed(
 	}
 }
 
-// Start starts the job if it hasn't already been started.
-func (j *concreteJob) Start() error {
+// Create creates the job's cgroups without starting the job.  A created job
+// can be inspected and stopped, and is started by Start.
+func (j *concreteJob) Create() error {
 	j.mutex.Lock()
 	defer j.mutex.Unlock()
 
-	if j.cmd != nil {
-		return fmt.Errorf("job %s (%v) has already been started", j.name, j.id)
+	if j.created || j.state() != JobStateCreated {
+		return fmt.Errorf("job %s (%v) has already been created: %w", j.name, j.id, ErrInvalidJobState)
+	}
+
+	return j.create()
+}
+
+// create creates the job's cgroups.  The caller must hold the lock.
+func (j *concreteJob) create() error {
+	hierarchy, err := cgroup.Default()
+	if err != nil {
+		return err
 	}
 
-	cgroupSet := cgroupv1.NewSet(j.id, j.cgControllers...)
+	// Nest the job's cgroups in its owner's group; see Manager.Create
+	cgroupSet := hierarchy.NewSet(j.owner, j.id, j.cgControllers...)
 	if err := cgroupSet.Create(); err != nil {
 		return err
 	}
+	j.hierarchy = hierarchy
+	j.cgroupSet = cgroupSet
+	j.created = true
 
-	args := cgroupSet.TaskFiles()
+	return nil
+}
+
+// Start starts the job, first creating it if Create has not been called.  It
+// fails if the job has already been started or has been stopped.
+func (j *concreteJob) Start() error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if state := j.state(); state != JobStateCreated {
+		return fmt.Errorf("job %s (%v) is %v: %w", j.name, j.id, state, ErrInvalidJobState)
+	}
+
+	if !j.created {
+		if err := j.create(); err != nil {
+			return j.failStart(err)
+		}
+	}
+	cgroupSet := j.cgroupSet
+
+	var args []string
+	if config.CgexecInit {
+		args = append(args, "--init") // See command.InitOption
+		j.initProcess = true
+	}
+	// cgexec inherits the status pipe as its first extra file
+	args = append(args, "--status-fd=3") // See command.StatusFdOption
+	args = append(args, cgroupSet.TaskFiles()...)
 	args = append(args, "--")
 	args = append(args, j.programName)
 	args = append(args, j.programArgs...)
 
-	j.cmd = exec.Command(config.CgexecPath, args...)
+	j.cmd = cgexecCommand(args...)
 	j.cmd.Stdout = j.stdoutBuffer
 	j.cmd.Stderr = j.stderrBuffer
 	j.cmd.Env = make([]string, 0) // Do not pass along our environment
@@ -131,12 +325,37 @@ func (j *concreteJob) Start() error {
 			syscall.CLONE_NEWNET,
 	}
 
+	// cgexec reports whether it started the program over the status pipe;
+	// see the startstatus package
+	statusReader, statusWriter, err := os.Pipe()
+	if err != nil {
+		return j.failStart(err)
+	}
+	defer statusReader.Close()
+	j.cmd.ExtraFiles = []*os.File{statusWriter}
+
+	// Watch before starting so that OOM events right after exec are seen
+	j.watchOom()
+
+	err = j.cmd.Start()
+
+	// Our copy of the write end must be closed for the read to end
+	statusWriter.Close()
+	if err != nil {
+		return j.failStart(err)
+	}
+
+	if err := startstatus.Read(statusReader); err != nil {
+		// cgexec exits once it has reported the failure
+		_ = j.cmd.Wait()
+		return j.failStart(err)
+	}
+
 	j.running = true
 
 	go func() {
-		// Run blocks until the newly-created process terminates.  It calls
-		// Wait internally
-		err := j.cmd.Run()
+		// Wait blocks until the process terminates
+		err := j.cmd.Wait()
 
 		// Once Wait returns, all output has been written to Stdout and Stderr
 		j.lockedOperation(func() {
@@ -152,8 +371,14 @@ func (j *concreteJob) Start() error {
 				j.runErrors = append(j.runErrors, err)
 			}
 
+			j.pidsLimitReached = j.readPidsLimitReached()
+			j.recordMemoryUsage()
+			j.usage = j.readResourceUsage()
+			j.terminationReason = j.readTerminationReason()
+
 			if err := cgroupSet.Destroy(); err != nil {
 				j.runErrors = append(j.runErrors, err)
+				FailedDestroys.Add(j.id, cgroupSet)
 			}
 			j.running = false
 		})
@@ -162,25 +387,112 @@ func (j *concreteJob) Start() error {
 	return nil
 }
 
-// Stop kills the job.
+// cgexecCommand returns a Cmd that runs cgexec with the given arguments; see
+// config.CgexecPath and config.CgexecArgs.
+func cgexecCommand(args ...string) *exec.Cmd {
+	cgexecArgs := append([]string{}, config.CgexecArgs...)
+
+	return exec.Command(config.CgexecPath, append(cgexecArgs, args...)...)
+}
+
+// failStart records that the job failed to start because of the given error
+// and terminates it.  It returns an error that wraps both ErrFailedToStart and
+// the given error.  The caller must hold the lock.
+func (j *concreteJob) failStart(err error) error {
+	j.runErrors = append(j.runErrors, err)
+	j.terminateUnstarted(TerminationReasonFailedToStart)
+
+	return fmt.Errorf("job %s (%v): %w: %w", j.name, j.id, ErrFailedToStart, err)
+}
+
+// terminateUnstarted moves a job whose program is not running to the
+// terminated state for the given reason, closes its output, and destroys its
+// cgroups, if any.  The caller must hold the lock.
+func (j *concreteJob) terminateUnstarted(reason TerminationReason) {
+	j.terminationReason = reason
+	j.stopWatchingOom()
+
+	if err := j.stdoutBuffer.Close(); err != nil {
+		j.runErrors = append(j.runErrors, err)
+	}
+
+	if err := j.stderrBuffer.Close(); err != nil {
+		j.runErrors = append(j.runErrors, err)
+	}
+
+	if j.created {
+		if err := j.cgroupSet.Destroy(); err != nil {
+			j.runErrors = append(j.runErrors, err)
+			FailedDestroys.Add(j.id, j.cgroupSet)
+		}
+	}
+}
+
+// state returns the stage of its lifecycle that the job has reached.  The
+// caller must hold the lock.
+func (j *concreteJob) state() JobState {
+	switch {
+	case j.running:
+		return JobStateRunning
+	case j.cmd == nil && j.terminationReason == TerminationReasonNone:
+		return JobStateCreated
+	}
+
+	return JobStateTerminated
+}
+
+// Stop kills the job, including every process in the job's cgroups, and
+// waits until the cgroups are empty.  The lock is released while Stop waits,
+// which can take seconds, so that the job can be inspected in the meantime.
 func (j *concreteJob) Stop() error {
 	j.mutex.Lock()
-	defer j.mutex.Unlock()
+	killCgroups, err := j.stop()
+	hierarchy, cgroupSet := j.hierarchy, j.cgroupSet
+	j.mutex.Unlock()
+
+	if err != nil || !killCgroups {
+		return err
+	}
+
+	// Descendants may have left the job's process tree (e.g., daemons), or
+	// may not be in the job's PID namespace.  The job's cgroups may be
+	// destroyed once they are empty, before Kill returns.
+	if err := hierarchy.Kill(nil, cgroupSet); err != nil {
+		return fmt.Errorf("failed to kill job %s (%v): %w", j.name, j.id, err)
+	}
+
+	return nil
+}
+
+// stop marks the job as stopped and kills its process.  It returns true if the
+// processes in the job's cgroups must also be killed.  The caller must hold
+// the lock.
+func (j *concreteJob) stop() (bool, error) {
+	if j.state() == JobStateCreated {
+		// The job will never start
+		j.stopped = true
+		j.terminateUnstarted(TerminationReasonStopped)
+		return false, nil
+	}
 
 	if !j.running {
 		// If the job isn't running, it is stopped already
-		return nil
+		return false, nil
 	}
 
 	if j.cmd == nil || j.cmd.Process == nil {
-		return fmt.Errorf("job is in the running state but has not completed start")
+		return false, fmt.Errorf("job is in the running state but has not completed start")
 	}
 
+	// cgexec may not yet have added itself to the job's cgroups
 	if err := j.cmd.Process.Kill(); err != nil && err != os.ErrProcessDone {
-		return err
+		return false, err
 	}
 
-	return nil
+	// The job may terminate as soon as the lock is released
+	j.stopped = true
+
+	return true, nil
 }
 
 // StdoutStream returns a ByteStream associated with the standard output of the job.
@@ -195,6 +507,21 @@ func (j *concreteJob) StderrStream() *io.ByteStream {
 	return io.NewByteStream(j.stderrBuffer)
 }
 
+// Release frees the resources that hold the job's output.  Once it is
+// released, the job's output can no longer be streamed.  If the job is
+// running, or its cgroups have been created and it has not terminated, it
+// returns an error that wraps ErrInvalidJobState.
+func (j *concreteJob) Release() error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if state := j.state(); state == JobStateRunning || (state == JobStateCreated && j.created) {
+		return fmt.Errorf("job %s (%v) is %v: %w", j.name, j.id, state, ErrInvalidJobState)
+	}
+
+	return errors.Join(j.stdoutBuffer.Release(), j.stderrBuffer.Release())
+}
+
 // Status returns the current status of this job.  If the job is running,
 // the information will include the job's PID.  If the job has terminated,
 // the information will include the exit code and termination signal (if any).
@@ -208,6 +535,7 @@ func (j *concreteJob) Status() *JobStatus {
 		ID:        j.id.String(),
 		Running:   j.running,
 		Pid:       -1,
+		State:     j.state(),
 		ExitCode:  -1,
 		SignalNum: syscall.Signal(-1),
 	}
@@ -216,6 +544,26 @@ func (j *concreteJob) Status() *JobStatus {
 		status.RunError = fmt.Errorf("%v", j.runErrors)
 	}
 
+	if j.running {
+		status.PidsLimitReached = j.readPidsLimitReached()
+		status.PeakMemoryUsage = j.readPeakMemoryUsage()
+	} else {
+		status.PidsLimitReached = j.pidsLimitReached
+		status.PeakMemoryUsage = j.peakMemoryUsage
+	}
+	status.OomKills = j.oomKills
+	status.TerminationReason = j.terminationReason
+
+	if j.usage != nil {
+		usage := *j.usage
+		status.Usage = &usage
+	}
+
+	if j.cmd == nil {
+		// The job has not been started
+		return status
+	}
+
 	if j.cmd.Process != nil {
 		status.Pid = j.cmd.Process.Pid
 	}
@@ -232,6 +580,269 @@ func (j *concreteJob) Status() *JobStatus {
 	return status
 }
 
+// Stats returns the current resource usage of this job.  If the job is not
+// running, its cgroups no longer exist and Stats returns ErrJobNotRunning.
+func (j *concreteJob) Stats() (*JobStats, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if !j.running || j.cgroupSet == nil {
+		return nil, fmt.Errorf("%w: %s (%v)", ErrJobNotRunning, j.name, j.id)
+	}
+
+	stats := j.hierarchy.Stats(j.cgroupSet)
+
+	return &JobStats{
+		CpuUsage:        time.Duration(stats.CpuUsageNs),
+		MemoryUsage:     stats.MemoryUsage,
+		PeakMemoryUsage: stats.PeakMemoryUsage,
+		IoReadBytes:     stats.IoReadBytes,
+		IoWriteBytes:    stats.IoWriteBytes,
+		Pids:            stats.Pids,
+	}, nil
+}
+
+// Processes returns the processes in this job's cgroups.  Processes that
+// exit while they are being read are omitted.  If the job is not running,
+// its cgroups no longer exist and Processes returns ErrJobNotRunning.
+func (j *concreteJob) Processes() ([]*Process, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if !j.running || j.cgroupSet == nil {
+		return nil, fmt.Errorf("%w: %s (%v)", ErrJobNotRunning, j.name, j.id)
+	}
+
+	pids, err := j.cgroupSet.Pids()
+	if err != nil {
+		return nil, err
+	}
+
+	processes := make([]*Process, 0, len(pids))
+
+	for _, pid := range pids {
+		process, err := ReadProcess(nil, pid)
+		if err != nil {
+			if os.IsNotExist(err) || errors.Is(err, syscall.ESRCH) {
+				continue
+			}
+			return nil, err
+		}
+
+		processes = append(processes, process)
+	}
+
+	return processes, nil
+}
+
+// Exec runs the given program in the namespaces and cgroups of this job.
+// The program's output is not part of the job's output; it is available from
+// the returned ExecProcess.  If the job is not running, Exec returns
+// ErrJobNotRunning; if cgexec cannot start the program, it returns an error
+// that wraps both ErrFailedToStart and the reason.
+func (j *concreteJob) Exec(programPath string, arguments []string) (ExecProcess, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if !j.running || j.cgroupSet == nil || j.cmd.Process == nil {
+		return nil, fmt.Errorf("%w: %s (%v)", ErrJobNotRunning, j.name, j.id)
+	}
+
+	process, err := startExecProcess(j.cmd.Process.Pid, j.cgroupSet.TaskFiles(), programPath, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return process, nil
+}
+
+// readPidsLimitReached returns true if the job's pids cgroup reports that the
+// job tried to exceed its limit.  If the job has no pids cgroup, it returns
+// false.  The caller must hold the lock and the job's cgroups must exist.
+func (j *concreteJob) readPidsLimitReached() bool {
+	if j.cgroupSet == nil {
+		return false
+	}
+
+	content, err := j.cgroupSet.ReadFile("pids", cgroup.PidsEventsFilename)
+	if err != nil {
+		return false
+	}
+
+	return cgroup.ParseEvents(content)["max"] > 0
+}
+
+// watchOom starts watching the job's memory cgroup for OOM events so that
+// OomKills is current while the job runs.  If the job has no memory cgroup, it
+// does nothing.  It is called before the program starts so that OOM events
+// right after exec are counted; notify waits for the lock that Start holds.
+// Failure to watch is logged but is not fatal; the
+// OOM kill count is still read when the job terminates.  The caller must hold
+// the lock.
+func (j *concreteJob) watchOom() {
+	if !j.hasController("memory") {
+		return
+	}
+
+	hierarchy, cgroupSet := j.hierarchy, j.cgroupSet
+
+	watcher, err := cgroupSet.WatchOom(func() {
+		j.lockedOperation(func() {
+			if !j.running {
+				// The cgroup may already have been destroyed
+				return
+			}
+
+			if oomKills, err := hierarchy.OomKills(cgroupSet); err == nil {
+				j.oomKills = oomKills
+			}
+		})
+	})
+	if err != nil {
+		log.Printf("Failed to watch job %v for OOM events: %v", j.id, err)
+		return
+	}
+
+	j.oomWatcher = watcher
+}
+
+// recordMemoryUsage stops watching for OOM events and records the final OOM
+// kill count and peak memory usage of the job.  The caller must hold the lock
+// and the job's cgroups must exist.
+func (j *concreteJob) recordMemoryUsage() {
+	j.stopWatchingOom()
+
+	if !j.hasController("memory") {
+		return
+	}
+
+	if oomKills, err := j.hierarchy.OomKills(j.cgroupSet); err == nil {
+		j.oomKills = oomKills
+	}
+
+	j.peakMemoryUsage = j.readPeakMemoryUsage()
+}
+
+// stopWatchingOom stops watching for OOM events, if the job is watching.  The
+// caller must hold the lock.
+func (j *concreteJob) stopWatchingOom() {
+	if j.oomWatcher != nil {
+		if err := j.oomWatcher.Close(); err != nil {
+			j.runErrors = append(j.runErrors, err)
+		}
+		j.oomWatcher = nil
+	}
+}
+
+// readPeakMemoryUsage returns the peak memory usage reported by the job's
+// memory cgroup, or 0 if it is unavailable.  The caller must hold the lock
+// and the job's cgroups must exist.
+func (j *concreteJob) readPeakMemoryUsage() uint64 {
+	if j.cgroupSet == nil || !j.hasController("memory") {
+		return 0
+	}
+
+	peak, err := j.hierarchy.PeakMemoryUsage(j.cgroupSet)
+	if err != nil {
+		return 0
+	}
+
+	return peak
+}
+
+// readResourceUsage returns a snapshot of the resources that the job
+// consumed.  Memory, IO and pids usage is read from the job's cgroups; if the
+// job has no memory or block IO cgroup, the process's own rusage is used
+// instead.  The caller must hold the lock, the job's process must have
+// terminated, and the job's cgroups must exist.
+func (j *concreteJob) readResourceUsage() *ResourceUsage {
+	state := j.cmd.ProcessState
+	if state == nil {
+		return nil
+	}
+
+	usage := &ResourceUsage{
+		UserTime:   state.UserTime(),
+		SystemTime: state.SystemTime(),
+	}
+
+	var stats cgroup.Stats
+	if j.cgroupSet != nil {
+		stats = *j.hierarchy.Stats(j.cgroupSet)
+	}
+
+	usage.PeakMemoryUsage = stats.PeakMemoryUsage
+	usage.IoReadBytes = stats.IoReadBytes
+	usage.IoWriteBytes = stats.IoWriteBytes
+	usage.MaxPids = stats.PeakPids
+
+	rusage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return usage
+	}
+
+	if !j.hasController("memory") {
+		// Maxrss is in kilobytes
+		usage.PeakMemoryUsage = uint64(rusage.Maxrss) * 1024
+	}
+
+	if !j.hasController("blkio") && !j.hasController("io") {
+		// Inblock and Oublock are in 512-byte blocks
+		usage.IoReadBytes = uint64(rusage.Inblock) * 512
+		usage.IoWriteBytes = uint64(rusage.Oublock) * 512
+	}
+
+	return usage
+}
+
+// readTerminationReason determines why the job terminated.  A job that was
+// not stopped but was killed by SIGKILL after the OOM killer killed one of its
+// processes is considered to have run out of memory.  The caller must hold the lock, the
+// job's process must have terminated, and the OOM kill count must have been
+// recorded.
+func (j *concreteJob) readTerminationReason() TerminationReason {
+	state := j.cmd.ProcessState
+	if state == nil {
+		return TerminationReasonNone
+	}
+
+	ws, ok := state.Sys().(syscall.WaitStatus)
+	if !ok {
+		return TerminationReasonExited
+	}
+
+	// An init process reports that the OOM killer killed the program, which
+	// is chosen instead of the init process, as 128 plus SIGKILL
+	if j.initProcess && ws.Exited() && ws.ExitStatus() == 128+int(syscall.SIGKILL) && j.oomKills > 0 {
+		return TerminationReasonOutOfMemory
+	}
+
+	if !ws.Signaled() {
+		return TerminationReasonExited
+	}
+
+	switch {
+	case j.stopped:
+		return TerminationReasonStopped
+	case ws.Signal() == syscall.SIGKILL && j.oomKills > 0:
+		return TerminationReasonOutOfMemory
+	}
+
+	return TerminationReasonSignaled
+}
+
+// hasController returns true if the job has a cgroup for the controller with
+// the given name.
+func (j *concreteJob) hasController(name string) bool {
+	for _, controller := range j.cgControllers {
+		if controller.Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
 // ID returns the server-assigned ID of this job.
 func (j *concreteJob) ID() uuid.UUID {
 	return j.id
@@ -0,0 +1,69 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobmanagertest
+
+import (
+	"github.com/adalton/teleport-exercise/pkg/io"
+	"github.com/adalton/teleport-exercise/pkg/jobmanager"
+)
+
+const (
+	DefaultExecStandardOutput = "exec stdout"
+	DefaultExecStandardError  = "exec stderr"
+	DefaultExecExitCode       = 3
+)
+
+// mockExecProcess is a simple implementation of the ExecProcess interface for
+// use by unit tests.  The process has already terminated when it is created.
+type mockExecProcess struct {
+	stdout io.OutputBuffer
+	stderr io.OutputBuffer
+}
+
+// newMockExecProcess creates and returns a new mockExecProcess.
+func newMockExecProcess() *mockExecProcess {
+	p := &mockExecProcess{
+		stdout: io.NewMemoryBuffer(),
+		stderr: io.NewMemoryBuffer(),
+	}
+
+	_, _ = p.stdout.Write([]byte(DefaultExecStandardOutput))
+	_, _ = p.stderr.Write([]byte(DefaultExecStandardError))
+	p.stdout.Close()
+	p.stderr.Close()
+
+	return p
+}
+
+func (p *mockExecProcess) StdoutStream() *io.ByteStream {
+	return io.NewByteStream(p.stdout)
+}
+
+func (p *mockExecProcess) StderrStream() *io.ByteStream {
+	return io.NewByteStream(p.stderr)
+}
+
+func (p *mockExecProcess) Wait() *jobmanager.ExecStatus {
+	return &jobmanager.ExecStatus{ExitCode: DefaultExecExitCode}
+}
+
+func (p *mockExecProcess) Kill() error {
+	return nil
+}
+
+func (p *mockExecProcess) Release() error {
+	p.stdout.Release()
+	p.stderr.Release()
+	return nil
+}
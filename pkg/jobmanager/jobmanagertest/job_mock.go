@@ -15,9 +15,11 @@ package jobmanagertest
 
 import (
 	"fmt"
+	"sync"
 	"syscall"
+	"time"
 
-	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
 	"github.com/adalton/teleport-exercise/pkg/io"
 	"github.com/adalton/teleport-exercise/pkg/jobmanager"
 
@@ -32,13 +34,22 @@ const (
 	DefaultSignalAfterStop        = syscall.SIGKILL
 	DefaultExitStatusWhileRunning = -1
 	DefaultExitStatusAfterStop    = 128 + int(DefaultSignalAfterStop)
+	DefaultCpuUsage               = 1500 * time.Millisecond
+	DefaultMemoryUsage            = 1048576
+	DefaultPeakMemoryUsage        = 2097152
+	DefaultIoReadBytes            = 4096
+	DefaultIoWriteBytes           = 8192
+	DefaultPids                   = 3
+	DefaultProcessRss             = 524288
 )
 
 // mockJob is a simple implementation of the Job interface for use by unit tests
 type mockJob struct {
+	mutex   sync.Mutex
 	owner   string
 	name    string
 	id      uuid.UUID
+	state   jobmanager.JobState
 	running bool
 	stdout  io.OutputBuffer
 	stderr  io.OutputBuffer
@@ -48,7 +59,7 @@ type mockJob struct {
 func NewMockJob(
 	owner string,
 	jobName string,
-	controllers []cgroupv1.Controller,
+	controllers []cgroup.Controller,
 	programPath string,
 	arguments ...string,
 ) jobmanager.Job {
@@ -63,12 +74,19 @@ func NewMockJob(
 	}
 }
 
+func (m *mockJob) Create() error {
+	return nil
+}
+
 func (m *mockJob) Start() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 
-	if m.running {
-		return fmt.Errorf("job %s (%v) has already been started", m.name, m.id)
+	if m.state != jobmanager.JobStateCreated {
+		return fmt.Errorf("job %s (%v) has already been started: %w", m.name, m.id, jobmanager.ErrInvalidJobState)
 	}
 
+	m.state = jobmanager.JobStateRunning
 	m.running = true
 	_, _ = m.stdout.Write([]byte(DefaultStandardOutput))
 	_, _ = m.stderr.Write([]byte(DefaultStandardError))
@@ -77,12 +95,29 @@ func (m *mockJob) Start() error {
 }
 
 func (m *mockJob) Stop() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.state = jobmanager.JobStateTerminated
 	m.running = false
 	m.stdout.Close()
 	m.stderr.Close()
 	return nil
 }
 
+func (m *mockJob) Release() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.running {
+		return fmt.Errorf("job %s (%v) is running: %w", m.name, m.id, jobmanager.ErrInvalidJobState)
+	}
+
+	m.stdout.Release()
+	m.stderr.Release()
+	return nil
+}
+
 func (m *mockJob) StdoutStream() *io.ByteStream {
 	return io.NewByteStream(m.stdout)
 }
@@ -92,12 +127,24 @@ func (m *mockJob) StderrStream() *io.ByteStream {
 }
 
 func (m *mockJob) Status() *jobmanager.JobStatus {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	exitCode := DefaultExitStatusWhileRunning
 	signalNumber := DefaultSignalWhileRunning
 
-	if !m.running {
+	var usage *jobmanager.ResourceUsage
+
+	if m.state == jobmanager.JobStateTerminated {
 		exitCode = DefaultExitStatusAfterStop
 		signalNumber = DefaultSignalAfterStop
+		usage = &jobmanager.ResourceUsage{
+			UserTime:        DefaultCpuUsage,
+			PeakMemoryUsage: DefaultPeakMemoryUsage,
+			IoReadBytes:     DefaultIoReadBytes,
+			IoWriteBytes:    DefaultIoWriteBytes,
+			MaxPids:         DefaultPids,
+		}
 	}
 
 	return &jobmanager.JobStatus{
@@ -106,12 +153,63 @@ func (m *mockJob) Status() *jobmanager.JobStatus {
 		ID:        m.id.String(),
 		Running:   m.running,
 		Pid:       DefaultPID,
+		State:     m.state,
 		SignalNum: signalNumber,
 		ExitCode:  exitCode,
 		RunError:  nil,
+		Usage:     usage,
 	}
 }
 
+func (m *mockJob) Stats() (*jobmanager.JobStats, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.running {
+		return nil, jobmanager.ErrJobNotRunning
+	}
+
+	return &jobmanager.JobStats{
+		CpuUsage:        DefaultCpuUsage,
+		MemoryUsage:     DefaultMemoryUsage,
+		PeakMemoryUsage: DefaultPeakMemoryUsage,
+		IoReadBytes:     DefaultIoReadBytes,
+		IoWriteBytes:    DefaultIoWriteBytes,
+		Pids:            DefaultPids,
+	}, nil
+}
+
+func (m *mockJob) Processes() ([]*jobmanager.Process, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.running {
+		return nil, jobmanager.ErrJobNotRunning
+	}
+
+	return []*jobmanager.Process{
+		{
+			Pid:     DefaultPID,
+			Ppid:    1,
+			Command: []string{"/bin/sh", "-c", "sleep 60"},
+			State:   "S",
+			Rss:     DefaultProcessRss,
+			CpuTime: DefaultCpuUsage,
+		},
+	}, nil
+}
+
+func (m *mockJob) Exec(programPath string, arguments []string) (jobmanager.ExecProcess, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.running {
+		return nil, jobmanager.ErrJobNotRunning
+	}
+
+	return newMockExecProcess(), nil
+}
+
 func (m *mockJob) ID() uuid.UUID {
 	return m.id
 }
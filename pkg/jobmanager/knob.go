@@ -0,0 +1,252 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobmanager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/config"
+)
+
+// Knob is a cgroup interface file, such as "memory.swappiness", and the value
+// to write to it.  Knobs provide access to cgroup settings that have no
+// dedicated limit.  Only the files in config.CgroupAllowedKnobs may be
+// written.
+type Knob struct {
+	File  string
+	Value string
+}
+
+// ResolveKnobs converts the given configured knobs to Knobs.  If any of the
+// knobs names a file that is not allowed, it returns an error that wraps
+// ErrInvalidArgument.
+func ResolveKnobs(knobs []config.CgroupKnob) ([]Knob, error) {
+	resolved := make([]Knob, 0, len(knobs))
+
+	for _, knob := range knobs {
+		resolved = append(resolved, Knob{File: knob.File, Value: knob.Value})
+	}
+
+	if err := validateKnobs(resolved); err != nil {
+		return nil, err
+	}
+
+	return resolved, nil
+}
+
+// PrepareKnobs checks the given knobs, typically produced by ResolveKnobs from
+// the default knobs, against the cgroups of jobs in the given hierarchy, whose
+// JobsParent cgroup must exist.  If any of them names a file that does not
+// exist there, it returns an error that wraps ErrInvalidArgument, since every
+// job would fail to start.  Otherwise, it removes the files that do not exist
+// there from config.CgroupAllowedKnobs, so that jobs cannot request them, and
+// returns the files that it removed.
+func PrepareKnobs(hierarchy *cgroup.Hierarchy, knobs []Knob) (unavailable []string, err error) {
+	return PrepareKnobsDetailed(nil, hierarchy, knobs)
+}
+
+// PrepareKnobsDetailed is wrapped by PrepareKnobs and performs the same
+// operation using the given osAdapter.
+func PrepareKnobsDetailed(
+	osAdapter *os.Adapter,
+	hierarchy *cgroup.Hierarchy,
+	knobs []Knob,
+) (unavailable []string, err error) {
+	for _, knob := range knobs {
+		if !hierarchy.HasKnobFile(osAdapter, knob.File) {
+			return nil, fmt.Errorf("%w: cgroup file '%s' does not exist in the %s hierarchy",
+				ErrInvalidArgument, knob.File, hierarchy.Version)
+		}
+	}
+
+	available := make([]string, 0, len(config.CgroupAllowedKnobs))
+
+	for _, file := range config.CgroupAllowedKnobs {
+		if hierarchy.HasKnobFile(osAdapter, file) {
+			available = append(available, file)
+		} else {
+			unavailable = append(unavailable, file)
+		}
+	}
+
+	config.CgroupAllowedKnobs = available
+
+	return unavailable, nil
+}
+
+// validateKnobs returns an error that wraps ErrInvalidArgument if any of the
+// given knobs names a file that is not allowed.
+func validateKnobs(knobs []Knob) error {
+	for _, knob := range knobs {
+		if !isAllowedKnob(knob.File) {
+			return fmt.Errorf("%w: cgroup file '%s' may not be set", ErrInvalidArgument, knob.File)
+		}
+	}
+
+	return nil
+}
+
+// isAllowedKnob returns true if the given file is in config.CgroupAllowedKnobs
+// and names an interface file of a cgroup controller.
+func isAllowedKnob(file string) bool {
+	if !cgroup.IsKnobFile(knobController(file), file) {
+		return false
+	}
+
+	for _, allowed := range config.CgroupAllowedKnobs {
+		if file == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// knobController returns the name of the controller to which the given
+// cgroup interface file belongs.
+func knobController(file string) string {
+	if i := strings.Index(file, "."); i >= 0 {
+		return file[:i]
+	}
+
+	return ""
+}
+
+// knobControllerNames returns the names of the controllers of the given
+// files, without duplicates, in the order in which they first appear.
+func knobControllerNames(files []string) []string {
+	var names []string
+	seen := make(map[string]bool)
+
+	for _, file := range files {
+		if name := knobController(file); !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// newKnobControllers returns the knob controllers that write the given knobs.
+// If jobKnobs is true, it also returns a knob controller for each controller
+// named in config.CgroupAllowedKnobs so that jobs may request their own knobs.
+func newKnobControllers(knobs []Knob, jobKnobs bool) []cgroup.Controller {
+	var files []string
+
+	for _, knob := range knobs {
+		files = append(files, knob.File)
+	}
+
+	if jobKnobs {
+		for _, file := range config.CgroupAllowedKnobs {
+			if isAllowedKnob(file) {
+				files = append(files, file)
+			}
+		}
+	}
+
+	var controllers []cgroup.Controller
+
+	for _, name := range knobControllerNames(files) {
+		controllers = append(controllers, newKnobController(name, knobsOf(name, knobs)))
+	}
+
+	return controllers
+}
+
+// newKnobController returns the knob controller that writes the given knobs of
+// the named controller and that may write the files in
+// config.CgroupAllowedKnobs.
+func newKnobController(name string, knobs []Knob) cgroup.Controller {
+	return cgroup.NewKnobController(name, toCgroupKnobs(knobs), config.CgroupAllowedKnobs)
+}
+
+// knobsOf returns the knobs, from among the given knobs, that belong to the
+// named controller.
+func knobsOf(name string, knobs []Knob) []Knob {
+	var matching []Knob
+
+	for _, knob := range knobs {
+		if knobController(knob.File) == name {
+			matching = append(matching, knob)
+		}
+	}
+
+	return matching
+}
+
+// withKnobs returns a copy of the given knob controller in which the given
+// knobs, for the controller that it writes, replace the controller's knobs
+// for the same files.  If controller is not a knob controller for the named
+// controller, or if it may not write one of the knobs' files, it returns nil.
+func withKnobs(controller cgroup.Controller, name string, knobs []Knob) cgroup.Controller {
+	overrides := knobsOf(name, knobs)
+
+	c, ok := controller.(*cgroup.KnobController)
+	if !ok || c.Controller != name {
+		return nil
+	}
+
+	for _, knob := range overrides {
+		if !c.IsAllowed(knob.File) {
+			return nil
+		}
+	}
+
+	updated := *c
+	updated.Knobs = toCgroupKnobs(mergeKnobs(fromCgroupKnobs(c.Knobs), overrides))
+
+	return &updated
+}
+
+// mergeKnobs returns the given default knobs, without those for the files
+// that the given overrides name, followed by the overrides.
+func mergeKnobs(defaults, overrides []Knob) []Knob {
+	overridden := make(map[string]bool)
+	for _, knob := range overrides {
+		overridden[knob.File] = true
+	}
+
+	merged := make([]Knob, 0, len(defaults)+len(overrides))
+
+	for _, knob := range defaults {
+		if !overridden[knob.File] {
+			merged = append(merged, knob)
+		}
+	}
+
+	return append(merged, overrides...)
+}
+
+func toCgroupKnobs(knobs []Knob) []cgroup.Knob {
+	var converted []cgroup.Knob
+	for _, knob := range knobs {
+		converted = append(converted, cgroup.Knob(knob))
+	}
+
+	return converted
+}
+
+func fromCgroupKnobs(knobs []cgroup.Knob) []Knob {
+	converted := make([]Knob, 0, len(knobs))
+	for _, knob := range knobs {
+		converted = append(converted, Knob(knob))
+	}
+
+	return converted
+}
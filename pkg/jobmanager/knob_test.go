@@ -0,0 +1,218 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobmanager_test
+
+import (
+	goos "os"
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2"
+	"github.com/adalton/teleport-exercise/pkg/config"
+	"github.com/adalton/teleport-exercise/pkg/jobmanager"
+	"github.com/adalton/teleport-exercise/pkg/jobmanager/jobmanagertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// allowJobKnobs permits job knobs for the duration of the calling test.
+func allowJobKnobs(t *testing.T) {
+	saved := config.CgroupAllowJobKnobs
+	config.CgroupAllowJobKnobs = true
+
+	t.Cleanup(func() {
+		config.CgroupAllowJobKnobs = saved
+	})
+}
+
+func Test_ResolveKnobs(t *testing.T) {
+	knobs, err := jobmanager.ResolveKnobs([]config.CgroupKnob{
+		{File: "memory.swappiness", Value: "10"},
+	})
+
+	require.Nil(t, err)
+	assert.Equal(t, []jobmanager.Knob{{File: "memory.swappiness", Value: "10"}}, knobs)
+}
+
+func Test_ResolveKnobs_NotAllowed(t *testing.T) {
+	for _, file := range []string{"memory.limit_in_bytes", "tasks", "../memory.swappiness", ""} {
+		knobs, err := jobmanager.ResolveKnobs([]config.CgroupKnob{{File: file, Value: "1"}})
+
+		assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument, file)
+		assert.Nil(t, knobs, file)
+	}
+}
+
+func Test_JobManager_NewManager_Knobs(t *testing.T) {
+	allowJobKnobs(t)
+
+	hierarchy := &cgroup.Hierarchy{
+		Version:     cgroup.V2,
+		BasePath:    "/sys/fs/cgroup",
+		Controllers: []string{"cpu", "cpuset", "io", "pids"},
+	}
+
+	jm, missing := jobmanager.NewManager(hierarchy, nil, []jobmanager.Knob{
+		{File: "cpu.idle", Value: "1"},
+	})
+	info := jm.ServerInfo()
+
+	assert.Equal(t, []string{"memory"}, missing)
+	assert.Equal(t, []string{"cpu", "io", "pids", "cpuset"}, info.Capabilities)
+}
+
+func Test_JobManager_Start_Knobs(t *testing.T) {
+	allowJobKnobs(t)
+
+	var controllers []cgroup.Controller
+
+	jm := jobmanager.NewManagerDetailed(recordingJobConstructor(&controllers),
+		[]cgroup.Controller{
+			&cgroupv2.MemoryController{Limit: "2M"},
+			cgroup.NewKnobController("memory", []cgroup.Knob{
+				{File: "memory.oom.group", Value: "1"},
+				{File: "memory.zswap.max", Value: "max"},
+			}, config.CgroupAllowedKnobs),
+		})
+
+	_, err := jm.Start("user1", "job1", "/bin/true", nil, &jobmanager.JobLimits{
+		Knobs: []jobmanager.Knob{{File: "memory.zswap.max", Value: "0"}},
+	})
+
+	require.Nil(t, err)
+	assert.Equal(t, []cgroup.Controller{
+		&cgroupv2.MemoryController{Limit: "2M"},
+		cgroup.NewKnobController("memory", []cgroup.Knob{
+			{File: "memory.oom.group", Value: "1"},
+			{File: "memory.zswap.max", Value: "0"},
+		}, config.CgroupAllowedKnobs),
+	}, controllers)
+}
+
+func Test_JobManager_Start_Knobs_NotPermitted(t *testing.T) {
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob,
+		[]cgroup.Controller{cgroup.NewKnobController("memory", nil, config.CgroupAllowedKnobs)})
+
+	job, err := jm.Start("user1", "job1", "/bin/true", nil, &jobmanager.JobLimits{
+		Knobs: []jobmanager.Knob{{File: "memory.swappiness", Value: "0"}},
+	})
+
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument)
+	assert.Nil(t, job)
+}
+
+func Test_JobManager_Start_Knobs_NotAllowed(t *testing.T) {
+	allowJobKnobs(t)
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob,
+		[]cgroup.Controller{cgroup.NewKnobController("memory", nil, config.CgroupAllowedKnobs)})
+
+	job, err := jm.Start("user1", "job1", "/bin/true", nil, &jobmanager.JobLimits{
+		Knobs: []jobmanager.Knob{{File: "memory.limit_in_bytes", Value: "1G"}},
+	})
+
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument)
+	assert.Nil(t, job)
+}
+
+func Test_JobManager_Start_Knobs_Unsupported(t *testing.T) {
+	allowJobKnobs(t)
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob,
+		[]cgroup.Controller{cgroup.NewKnobController("memory", nil, config.CgroupAllowedKnobs)})
+
+	job, err := jm.Start("user1", "job1", "/bin/true", nil, &jobmanager.JobLimits{
+		Knobs: []jobmanager.Knob{{File: "cpu.idle", Value: "1"}},
+	})
+
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument)
+	assert.Nil(t, job)
+}
+
+func Test_JobManager_Start_Knobs_Unavailable(t *testing.T) {
+	allowJobKnobs(t)
+
+	// The allowed file does not exist in the server's hierarchy
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob,
+		[]cgroup.Controller{cgroup.NewKnobController("memory", nil, []string{"memory.oom.group"})})
+
+	job, err := jm.Start("user1", "job1", "/bin/true", nil, &jobmanager.JobLimits{
+		Knobs: []jobmanager.Knob{{File: "memory.swappiness", Value: "0"}},
+	})
+
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument)
+	assert.Nil(t, job)
+}
+
+// knobHierarchy returns a cgroup v2 hierarchy, and an adapter through which
+// only the given files exist in its JobsParent cgroup.
+func knobHierarchy(files ...string) (*cgroup.Hierarchy, *os.Adapter) {
+	hierarchy := &cgroup.Hierarchy{
+		Version:     cgroup.V2,
+		BasePath:    "/sys/fs/cgroup",
+		Controllers: []string{"cpu", "memory"},
+		JobsParent:  "jobs",
+	}
+
+	adapter := &os.Adapter{
+		StatFn: func(name string) (os.FileInfo, error) {
+			for _, file := range files {
+				if name == "/sys/fs/cgroup/jobs/"+file {
+					return nil, nil
+				}
+			}
+			return nil, goos.ErrNotExist
+		},
+	}
+
+	return hierarchy, adapter
+}
+
+// allowKnobs sets config.CgroupAllowedKnobs to the given files for the
+// duration of the calling test.
+func allowKnobs(t *testing.T, files ...string) {
+	saved := config.CgroupAllowedKnobs
+	config.CgroupAllowedKnobs = files
+
+	t.Cleanup(func() {
+		config.CgroupAllowedKnobs = saved
+	})
+}
+
+func Test_PrepareKnobs(t *testing.T) {
+	allowKnobs(t, "cpu.idle", "memory.oom.group", "memory.swappiness")
+	hierarchy, adapter := knobHierarchy("cpu.idle", "memory.oom.group")
+
+	unavailable, err := jobmanager.PrepareKnobsDetailed(adapter, hierarchy, []jobmanager.Knob{
+		{File: "memory.oom.group", Value: "1"},
+	})
+
+	require.Nil(t, err)
+	assert.Equal(t, []string{"memory.swappiness"}, unavailable)
+	assert.Equal(t, []string{"cpu.idle", "memory.oom.group"}, config.CgroupAllowedKnobs)
+}
+
+func Test_PrepareKnobs_DefaultUnavailable(t *testing.T) {
+	allowKnobs(t, "memory.oom.group", "memory.swappiness")
+	hierarchy, adapter := knobHierarchy("memory.oom.group")
+
+	_, err := jobmanager.PrepareKnobsDetailed(adapter, hierarchy, []jobmanager.Knob{
+		{File: "memory.swappiness", Value: "0"},
+	})
+
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument)
+	assert.Equal(t, []string{"memory.oom.group", "memory.swappiness"}, config.CgroupAllowedKnobs)
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobmanager
+
+import (
+	"fmt"
+
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2"
+	"github.com/adalton/teleport-exercise/pkg/config"
+)
+
+// JobLimits describes the resource limits requested for a single job.  Each
+// limit overrides the corresponding server default; limits that are not
+// specified keep the default.
+type JobLimits struct {
+	// BlockIO limits override the default limits for the devices they name.
+	BlockIO []DeviceLimit
+
+	// MaxPids, if non-zero, is the maximum number of processes and threads
+	// that may exist in the job at any one time.
+	MaxPids uint64
+
+	// CpusetCpus and CpusetMems, if non-empty, are the lists of CPUs and
+	// memory nodes (e.g., "0-3,8") to which the job is restricted.  See
+	// ValidateCpuset.
+	CpusetCpus string
+	CpusetMems string
+
+	// Cpus, if non-zero, is the CPU quota of the job in CPUs (e.g., 1.5).
+	// If UnlimitedCpus is true, the job has no CPU quota; it competes for
+	// CPU time according to its CpuWeight.  Cpus may not exceed
+	// config.CgroupMaxCpuLimit, and UnlimitedCpus requires
+	// config.CgroupAllowUnlimitedCpus.
+	Cpus          float64
+	UnlimitedCpus bool
+
+	// CpuWeight, if non-zero, is the relative share of CPU time that the job
+	// receives when CPUs are contended, in the range [MinCpuWeight,
+	// MaxCpuWeight].  Jobs have DefaultCpuWeight by default.
+	CpuWeight uint64
+
+	// CpuPeriodUs, if non-zero, is the CFS period in microseconds over which
+	// the CPU quota is enforced, in the range [MinCpuPeriodUs,
+	// MaxCpuPeriodUs].  Longer periods tolerate longer bursts.
+	CpuPeriodUs uint64
+
+	// MemoryLimit, if non-zero, is the maximum memory usage of the job in
+	// bytes.  It may not exceed config.CgroupMaxMemoryLimit, nor may
+	// MemorySwapLimit or MemorySoftLimit.  If MemoryLimit is zero, those are
+	// relative to config.CgroupDefaultMemoryLimit instead.
+	MemoryLimit uint64
+
+	// MemorySwapLimit, if non-zero, is the maximum combined memory and swap
+	// usage of the job in bytes; it must not be less than MemoryLimit.  A
+	// MemorySwapLimit equal to the MemoryLimit prevents the job from using
+	// swap.
+	MemorySwapLimit uint64
+
+	// MemorySoftLimit, if non-zero, is the memory usage in bytes above which
+	// the job's memory is reclaimed first when the system is under memory
+	// pressure; it must not exceed MemoryLimit.
+	MemorySoftLimit uint64
+
+	// OomPolicy selects what happens when the job reaches its memory limit.
+	OomPolicy OomPolicy
+
+	// Knobs are written to the job's cgroups after its other limits have
+	// been applied.  They replace the server's default knobs for the same
+	// files.  Knobs may be requested only if config.CgroupAllowJobKnobs is
+	// set.
+	Knobs []Knob
+}
+
+// validate returns an error that wraps ErrInvalidArgument if any of the
+// limits in these JobLimits is invalid.
+func (l *JobLimits) validate() error {
+	if l == nil {
+		return nil
+	}
+
+	if err := validateCpuLimits(l); err != nil {
+		return err
+	}
+
+	if err := validateMemoryLimits(l); err != nil {
+		return err
+	}
+
+	if max := config.CgroupMaxPidsLimit; max > 0 && l.MaxPids > max {
+		return fmt.Errorf("%w: pids limit must not exceed %d: %d", ErrInvalidArgument, max, l.MaxPids)
+	}
+
+	if len(l.Knobs) > 0 && !config.CgroupAllowJobKnobs {
+		return fmt.Errorf("%w: cgroup knobs are not permitted by this server", ErrInvalidArgument)
+	}
+
+	return validateKnobs(l.Knobs)
+}
+
+// limitOverride applies one kind of per-job limit to the controllers that
+// enforce it.
+type limitOverride struct {
+	// name describes the kind of limit in error messages
+	name string
+
+	// apply returns a copy of the given controller with the limit applied,
+	// or nil if the controller does not enforce this kind of limit.
+	apply func(controller cgroup.Controller) cgroup.Controller
+}
+
+// overrides returns the limitOverrides for the limits that are specified in
+// these JobLimits.
+func (l *JobLimits) overrides() []limitOverride {
+	if l == nil {
+		return nil
+	}
+
+	var overrides []limitOverride
+
+	if len(l.BlockIO) > 0 {
+		overrides = append(overrides, limitOverride{
+			name: "block IO",
+			apply: func(controller cgroup.Controller) cgroup.Controller {
+				return withBlockIOLimits(controller, l.BlockIO)
+			},
+		})
+	}
+
+	if l.MaxPids > 0 {
+		overrides = append(overrides, limitOverride{
+			name: "pids",
+			apply: func(controller cgroup.Controller) cgroup.Controller {
+				return withPidsLimit(controller, l.MaxPids)
+			},
+		})
+	}
+
+	if l.CpusetCpus != "" || l.CpusetMems != "" {
+		overrides = append(overrides, limitOverride{
+			name: "cpuset",
+			apply: func(controller cgroup.Controller) cgroup.Controller {
+				return withCpuset(controller, l.CpusetCpus, l.CpusetMems)
+			},
+		})
+	}
+
+	if hasCpuLimits(l) {
+		overrides = append(overrides, limitOverride{
+			name: "cpu",
+			apply: func(controller cgroup.Controller) cgroup.Controller {
+				return withCpuLimits(controller, l)
+			},
+		})
+	}
+
+	if hasMemoryLimits(l) {
+		overrides = append(overrides, limitOverride{
+			name: "memory",
+			apply: func(controller cgroup.Controller) cgroup.Controller {
+				return withMemoryLimits(controller, l)
+			},
+		})
+	}
+
+	files := make([]string, 0, len(l.Knobs))
+	for _, knob := range l.Knobs {
+		files = append(files, knob.File)
+	}
+
+	for _, name := range knobControllerNames(files) {
+		name := name
+
+		overrides = append(overrides, limitOverride{
+			name: name + " knob",
+			apply: func(controller cgroup.Controller) cgroup.Controller {
+				return withKnobs(controller, name, l.Knobs)
+			},
+		})
+	}
+
+	return overrides
+}
+
+// jobControllers returns the cgroup controllers to manage for a job with the
+// given limits.  If a limit requires a controller that this Manager does not
+// manage, jobControllers returns an error that wraps ErrInvalidArgument.
+func (m *Manager) jobControllers(limits *JobLimits) ([]cgroup.Controller, error) {
+	if err := limits.validate(); err != nil {
+		return nil, err
+	}
+
+	overrides := limits.overrides()
+
+	// m.controllers is not modified after creation
+	if len(overrides) == 0 {
+		return m.controllers, nil
+	}
+
+	controllers := make([]cgroup.Controller, 0, len(m.controllers))
+	applied := make([]bool, len(overrides))
+
+	for _, controller := range m.controllers {
+		for i := range overrides {
+			if updated := overrides[i].apply(controller); updated != nil {
+				controller = updated
+				applied[i] = true
+			}
+		}
+
+		controllers = append(controllers, controller)
+	}
+
+	for i := range overrides {
+		if !applied[i] {
+			return nil, fmt.Errorf("%w: %s limits are not supported by this server",
+				ErrInvalidArgument, overrides[i].name)
+		}
+	}
+
+	return controllers, nil
+}
+
+// withPidsLimit returns a copy of the given pids controller that enforces
+// the given limit.  If controller is not a pids controller, it returns nil.
+func withPidsLimit(controller cgroup.Controller, limit uint64) cgroup.Controller {
+	switch c := controller.(type) {
+	case *cgroupv1.PidsController:
+		updated := *c
+		updated.Limit = limit
+
+		return &updated
+
+	case *cgroupv2.PidsController:
+		updated := *c
+		updated.Limit = limit
+
+		return &updated
+	}
+
+	return nil
+}
@@ -14,9 +14,14 @@ limitations under the License.
 package jobmanager
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"sync"
 
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
 	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2"
 	"github.com/adalton/teleport-exercise/pkg/config"
 	"github.com/adalton/teleport-exercise/pkg/io"
 	"github.com/google/uuid"
@@ -31,11 +36,16 @@ const (
 // us to define both a production job type as well a a job type for unit
 // testing.
 type Job interface {
+	Create() error
 	Start() error
 	Stop() error
 	Status() *JobStatus
+	Stats() (*JobStats, error)
+	Processes() ([]*Process, error)
+	Exec(programPath string, arguments []string) (ExecProcess, error)
 	StdoutStream() *io.ByteStream
 	StderrStream() *io.ByteStream
+	Release() error
 	Name() string
 	ID() uuid.UUID
 }
@@ -47,7 +57,7 @@ type Job interface {
 type JobConstructor func(
 	owner string,
 	jobName string,
-	controllers []cgroupv1.Controller,
+	controllers []cgroup.Controller,
 	programPath string,
 	arguments ...string,
 ) Job
@@ -58,22 +68,56 @@ type Manager struct {
 	jobsByUserByJobID   map[string]map[string]Job // userID->jobID->job
 	jobsByUserByJobName map[string]map[string]Job // userID->jobName->job
 	allJobsByJobID      map[string]Job            // jobID->job
-	controllers         []cgroupv1.Controller
+	controllers         []cgroup.Controller
+	cgroupVersion       string
 	jobConstructor      JobConstructor
+
+	// hierarchy, if non-nil, is used to create the per-user groups that
+	// enforce aggregate limits on each user's jobs.
+	hierarchy *cgroup.Hierarchy
 }
 
-// NewManager creates and returns a new standard Manager.
-func NewManager() *Manager {
-	controllers := []cgroupv1.Controller{
-		&cgroupv1.CpuController{Cpus: config.CgroupDefaultCpuLimit},
-		&cgroupv1.MemoryController{Limit: config.CgroupDefaultMemoryLimit},
-		&cgroupv1.BlockIOController{
-			ReadBpsDevice:  config.CgroupDefaultBlkioReadLimit,
-			WriteBpsDevice: config.CgroupDefaultBlkioWriteLimit,
-		},
+// ServerInfo describes the resource enforcement that a Manager applies to
+// the jobs it runs.
+type ServerInfo struct {
+	CgroupVersion string
+	Capabilities  []string
+}
+
+// NewManager creates and returns a new standard Manager that enforces the
+// default job limits using the given cgroup hierarchy.  The given blockIO
+// limits, typically produced by ResolveBlockIOLimits, and the given knobs,
+// typically produced by ResolveKnobs, are applied to every job.  Controllers
+// that are not available in the hierarchy are omitted; their names are
+// returned in missing so that the caller can decide whether to proceed
+// without them.
+func NewManager(
+	hierarchy *cgroup.Hierarchy,
+	blockIO []DeviceLimit,
+	knobs []Knob,
+) (m *Manager, missing []string) {
+	var available []cgroup.Controller
+
+	for _, controller := range defaultControllers(hierarchy.Version, blockIO, knobs) {
+		if hierarchy.HasController(controller.Name()) {
+			available = append(available, controller)
+		} else {
+			missing = appendUnique(missing, controller.Name())
+		}
 	}
 
-	return NewManagerDetailed(NewJob, controllers)
+	// The v1 freezer imposes no limit, so it is not reported as missing;
+	// without it, Stop kills the processes of a job without freezing them
+	// first (see cgroup.Hierarchy.Kill).
+	if hierarchy.Version == cgroup.V1 && hierarchy.HasController("freezer") {
+		available = append(available, &cgroupv1.FreezerController{})
+	}
+
+	m = NewManagerDetailed(NewJob, available)
+	m.cgroupVersion = hierarchy.Version.String()
+	m.hierarchy = hierarchy
+
+	return m, missing
 }
 
 // NewManagerDetailed returns a new Manger with the given values.
@@ -82,7 +126,7 @@ func NewManager() *Manager {
 // constructor function for a mock type.
 // The given controllers is the list of cgroup controllers to manage while
 // running jobs.
-func NewManagerDetailed(jobConstructor JobConstructor, controllers []cgroupv1.Controller) *Manager {
+func NewManagerDetailed(jobConstructor JobConstructor, controllers []cgroup.Controller) *Manager {
 	return &Manager{
 		jobsByUserByJobID:   make(map[string]map[string]Job),
 		jobsByUserByJobName: make(map[string]map[string]Job),
@@ -92,29 +136,100 @@ func NewManagerDetailed(jobConstructor JobConstructor, controllers []cgroupv1.Co
 	}
 }
 
-// Start starts a new job with the given JobName for the given userID.
-// The programPath and arguments are the program the user wants to run and
-// the arguments to that program.
-func (m *Manager) Start(userID, jobName, programPath string, arguments []string) (Job, error) {
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
+// Create creates, but does not start, a new job with the given JobName for
+// the given userID.  The programPath and arguments are the program the user
+// wants to run and the arguments to that program.  The given limits, if
+// non-nil, override the default resource limits for the job.  The job's
+// cgroups are nested in a per-user group that enforces the user's aggregate
+// limits (see config.UserLimits).  The job is registered only if it is
+// created successfully.
+func (m *Manager) Create(
+	userID, jobName, programPath string,
+	arguments []string,
+	limits *JobLimits,
+) (Job, error) {
+	if err := validateGroupName(userID); err != nil {
+		return nil, err
+	}
 
-	if _, exists := m.jobsByUserByJobID[userID]; !exists {
-		m.jobsByUserByJobID[userID] = make(map[string]Job)
-		m.jobsByUserByJobName[userID] = make(map[string]Job)
+	controllers, err := m.jobControllers(limits)
+	if err != nil {
+		return nil, err
 	}
 
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	if _, exists := m.jobsByUserByJobName[userID][jobName]; exists {
 		return nil, ErrJobExists
 	}
 
-	job := m.jobConstructor(userID, jobName, m.controllers, programPath, arguments...)
+	if m.hierarchy != nil {
+		group := m.hierarchy.NewGroup(userID, m.userControllers(userID)...)
+		if err := group.Create(); err != nil {
+			return nil, err
+		}
+	}
+
+	job := m.jobConstructor(userID, jobName, controllers, programPath, arguments...)
+	if err := job.Create(); err != nil {
+		// The job is discarded, so free its output buffers
+		return nil, errors.Join(err, job.Release())
+	}
+
+	if _, exists := m.jobsByUserByJobID[userID]; !exists {
+		m.jobsByUserByJobID[userID] = make(map[string]Job)
+		m.jobsByUserByJobName[userID] = make(map[string]Job)
+	}
 
 	m.jobsByUserByJobID[userID][job.ID().String()] = job
 	m.jobsByUserByJobName[userID][jobName] = job
 	m.allJobsByJobID[job.ID().String()] = job
 
-	return job, job.Start()
+	return job, nil
+}
+
+// Launch starts the created job with the given jobID owned by the given
+// userID.  If the job has already been started or has been stopped, it
+// returns ErrInvalidJobState.  If the job fails to start, it remains
+// registered in the terminated state.  Launch is the second phase of the
+// Create-then-Start lifecycle; it is not named Start because Start keeps its
+// existing meaning of Create followed by Launch.
+func (m *Manager) Launch(userID, jobID string) error {
+	if err := validateJobID(jobID); err != nil {
+		return err
+	}
+
+	job, err := m.lookupJobByUser(userID, jobID)
+	if err != nil {
+		return err
+	}
+
+	return job.Start()
+}
+
+// Start creates and starts a new job; it is equivalent to Create followed by
+// Launch.  If the job is created but fails to start, it is removed, so that
+// its name can be reused, and only the error is returned.
+func (m *Manager) Start(
+	userID, jobName, programPath string,
+	arguments []string,
+	limits *JobLimits,
+) (Job, error) {
+	job, err := m.Create(userID, jobName, programPath, arguments, limits)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := job.Start(); err != nil {
+		m.mutex.Lock()
+		m.unregister(userID, job)
+		m.mutex.Unlock()
+
+		return nil, errors.Join(err, job.Release())
+	}
+
+	return job, nil
 }
 
 // Stop stops an existing job with the given jobID for the given userID.
@@ -123,6 +238,22 @@ func (m *Manager) Stop(userID, jobID string) error {
 		return err
 	}
 
+	job, err := m.lookupJobByUser(userID, jobID)
+	if err != nil {
+		return err
+	}
+
+	return job.Stop()
+}
+
+// Remove removes the job with the given jobID owned by the given userID and
+// releases its output.  If the job is running, or has been created but not
+// stopped, it returns ErrInvalidJobState.
+func (m *Manager) Remove(userID, jobID string) error {
+	if err := validateJobID(jobID); err != nil {
+		return err
+	}
+
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
@@ -131,7 +262,25 @@ func (m *Manager) Stop(userID, jobID string) error {
 		return err
 	}
 
-	return job.Stop()
+	if err := job.Release(); err != nil {
+		return err
+	}
+
+	// The superuser may remove any user's job
+	m.unregister(job.Status().Owner, job)
+
+	return nil
+}
+
+// unregister removes the given job, owned by the given owner, from the
+// Manager.  The caller must own the write lock associated with the given
+// Manager.
+func (m *Manager) unregister(owner string, job Job) {
+	jobID := job.ID().String()
+
+	delete(m.jobsByUserByJobID[owner], jobID)
+	delete(m.jobsByUserByJobName[owner], job.Name())
+	delete(m.allJobsByJobID, jobID)
 }
 
 // List returns a list of the jobs owned by the given userID.
@@ -176,6 +325,60 @@ func (m *Manager) Status(userID, jobID string) (*JobStatus, error) {
 	return job.Status(), nil
 }
 
+// Stats returns the current resource usage of the running job with the given
+// jobID owned by the given userID.  If the job is not running, it returns
+// ErrJobNotRunning.
+func (m *Manager) Stats(userID, jobID string) (*JobStats, error) {
+	if err := validateJobID(jobID); err != nil {
+		return nil, err
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	job, err := m.findJobByUser(userID, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	return job.Stats()
+}
+
+// Processes returns the processes in the cgroups of the running job with the
+// given jobID owned by the given userID.  If the job is not running, it
+// returns ErrJobNotRunning.
+func (m *Manager) Processes(userID, jobID string) ([]*Process, error) {
+	if err := validateJobID(jobID); err != nil {
+		return nil, err
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	job, err := m.findJobByUser(userID, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	return job.Processes()
+}
+
+// Exec runs the given program with the given arguments in the namespaces and
+// cgroups of the running job with the given jobID owned by the given userID.
+// If the job is not running, it returns ErrJobNotRunning.
+func (m *Manager) Exec(userID, jobID, programPath string, arguments []string) (ExecProcess, error) {
+	if err := validateJobID(jobID); err != nil {
+		return nil, err
+	}
+
+	job, err := m.lookupJobByUser(userID, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	return job.Exec(programPath, arguments)
+}
+
 // StdoutStream returns an io.ByteStream for reading the standard output generated
 // by the job with the given jobID own by the given userID.
 func (m *Manager) StdoutStream(userID, jobID string) (*io.ByteStream, error) {
@@ -212,6 +415,34 @@ func (m *Manager) StderrStream(userID, jobID string) (*io.ByteStream, error) {
 	return job.StderrStream(), nil
 }
 
+// ServerInfo returns a description of the resource enforcement that this
+// Manager applies to jobs.  Capabilities lists the names of the cgroup
+// controllers that are configured for every job.
+func (m *Manager) ServerInfo() *ServerInfo {
+	// m.controllers and m.cgroupVersion are not modified after creation
+	capabilities := make([]string, 0, len(m.controllers))
+	for _, controller := range m.controllers {
+		capabilities = appendUnique(capabilities, controller.Name())
+	}
+
+	return &ServerInfo{
+		CgroupVersion: m.cgroupVersion,
+		Capabilities:  capabilities,
+	}
+}
+
+// lookupJobByUser finds the job with the given jobID that is owned by the
+// given userID, like findJobByUser, but holds the read lock only for the
+// lookup.  It is for operations that can block, such as starting or stopping
+// a job, which must not stall the Manager's other operations; the job
+// serializes such operations itself.
+func (m *Manager) lookupJobByUser(userID, jobID string) (Job, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.findJobByUser(userID, jobID)
+}
+
 // findJobByUser finds a the job with the given jobID that is owned by
 // the given userID.  If no such job is found, it returns an error.
 // The caller must own (at least) the read lock associated with the
@@ -232,6 +463,110 @@ func (m *Manager) findJobByUser(userID, jobID string) (Job, error) {
 	return nil, ErrJobNotFound
 }
 
+// defaultControllers returns the cgroup controllers, configured with the
+// default limits, the given blockIO limits and the given knobs, that are
+// suitable for the given cgroup version.  The knob controllers follow the
+// others so that knobs take precedence over limits.
+func defaultControllers(version cgroup.Version, blockIO []DeviceLimit, knobs []Knob) []cgroup.Controller {
+	return append(limitControllers(version, blockIO),
+		newKnobControllers(knobs, config.CgroupAllowJobKnobs)...)
+}
+
+// limitControllers returns the cgroup controllers, configured with the
+// default limits and the given blockIO limits, that are suitable for the
+// given cgroup version.
+func limitControllers(version cgroup.Version, blockIO []DeviceLimit) []cgroup.Controller {
+	if version == cgroup.V2 {
+		return []cgroup.Controller{
+			&cgroupv2.CpuController{
+				Cpus:     config.CgroupDefaultCpuLimit,
+				PeriodUs: config.CgroupDefaultCpuPeriodUs,
+			},
+			&cgroupv2.MemoryController{Limit: formatMemorySize(config.CgroupDefaultMemoryLimit)},
+			newBlockIOController(version, blockIO),
+			&cgroupv2.PidsController{Limit: config.CgroupDefaultPidsLimit},
+			&cgroupv2.CpusetController{},
+		}
+	}
+
+	return []cgroup.Controller{
+		&cgroupv1.CpuController{
+			Cpus:     config.CgroupDefaultCpuLimit,
+			PeriodUs: config.CgroupDefaultCpuPeriodUs,
+		},
+		&cgroupv1.MemoryController{Limit: formatMemorySize(config.CgroupDefaultMemoryLimit)},
+		newBlockIOController(version, blockIO),
+		&cgroupv1.PidsController{Limit: config.CgroupDefaultPidsLimit},
+		&cgroupv1.CpusetController{},
+	}
+}
+
+// userControllers returns the cgroup controllers that enforce the aggregate
+// limits of the given user's jobs.  Controllers that are not available in the
+// Manager's hierarchy are omitted.
+func (m *Manager) userControllers(userID string) []cgroup.Controller {
+	limits, exists := config.CgroupUserLimits[userID]
+	if !exists {
+		limits = config.CgroupDefaultUserLimits
+	}
+
+	var available []cgroup.Controller
+
+	for _, controller := range userGroupControllers(m.hierarchy.Version, limits) {
+		if m.hierarchy.HasController(controller.Name()) {
+			available = append(available, controller)
+		}
+	}
+
+	return available
+}
+
+// userGroupControllers returns the cgroup controllers, configured with the
+// given aggregate limits, that are suitable for the given cgroup version.
+func userGroupControllers(version cgroup.Version, limits config.UserLimits) []cgroup.Controller {
+	if version == cgroup.V2 {
+		return []cgroup.Controller{
+			&cgroupv2.CpuController{
+				Cpus:     limits.Cpus,
+				PeriodUs: config.CgroupDefaultCpuPeriodUs,
+			},
+			&cgroupv2.MemoryController{Limit: formatMemorySize(limits.MemoryLimit)},
+			&cgroupv2.PidsController{Limit: limits.MaxPids},
+		}
+	}
+
+	return []cgroup.Controller{
+		&cgroupv1.CpuController{
+			Cpus:     limits.Cpus,
+			PeriodUs: config.CgroupDefaultCpuPeriodUs,
+		},
+		&cgroupv1.MemoryController{Limit: formatMemorySize(limits.MemoryLimit)},
+		&cgroupv1.PidsController{Limit: limits.MaxPids},
+	}
+}
+
+// validateGroupName ensures that the given userID can name the user's group
+// cgroup.  If it cannot, it returns an error that wraps ErrInvalidArgument.
+func validateGroupName(userID string) error {
+	if userID == "" || userID == "." || userID == ".." || strings.Contains(userID, "/") {
+		return fmt.Errorf("%w: user ID '%s' cannot name a cgroup", ErrInvalidArgument, userID)
+	}
+
+	return nil
+}
+
+// appendUnique appends the given name to names if names does not already
+// contain it.
+func appendUnique(names []string, name string) []string {
+	for _, existing := range names {
+		if existing == name {
+			return names
+		}
+	}
+
+	return append(names, name)
+}
+
 // validateJobID ensures that the given jobID is in the supported format.
 // If it is not, it returns an InvalidJobID error.
 func validateJobID(jobID string) error {
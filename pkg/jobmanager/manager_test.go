@@ -14,12 +14,19 @@ limitations under the License.
 package jobmanager_test
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2"
+	"github.com/adalton/teleport-exercise/pkg/config"
 	"github.com/adalton/teleport-exercise/pkg/jobmanager"
 	"github.com/adalton/teleport-exercise/pkg/jobmanager/jobmanagertest"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_JobManager_Start(t *testing.T) {
@@ -29,7 +36,7 @@ func Test_JobManager_Start(t *testing.T) {
 
 	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
 
-	job, err := jm.Start(userName1, jobName, programPath, nil)
+	job, err := jm.Start(userName1, jobName, programPath, nil, nil)
 
 	assert.Nil(t, err)
 	assert.NotNil(t, job)
@@ -42,8 +49,8 @@ func Test_JobManager_DuplicateJobName_Error(t *testing.T) {
 
 	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
 
-	_, _ = jm.Start(userName1, jobName, programPath, nil)
-	job, err := jm.Start(userName1, jobName, programPath, nil)
+	_, _ = jm.Start(userName1, jobName, programPath, nil, nil)
+	job, err := jm.Start(userName1, jobName, programPath, nil, nil)
 
 	assert.Error(t, err)
 	assert.Nil(t, job)
@@ -56,7 +63,7 @@ func Test_JobManager_Status_MatchingUser(t *testing.T) {
 
 	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
 
-	job, _ := jm.Start(userName1, jobName, programPath, nil)
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
 	status, err := jm.Status(userName1, job.ID().String())
 
 	assert.Nil(t, err)
@@ -72,7 +79,7 @@ func Test_JobManager_Status_NonMatchingUser(t *testing.T) {
 
 	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
 
-	job, _ := jm.Start(userName1, jobName, programPath, nil)
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
 	_, err := jm.Status("someOtherUser", job.ID().String())
 
 	assert.Error(t, err)
@@ -85,7 +92,7 @@ func Test_JobManager_Status_Superuser(t *testing.T) {
 
 	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
 
-	job, _ := jm.Start(userName1, jobName, programPath, nil)
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
 	status, err := jm.Status(jobmanager.Superuser, job.ID().String())
 
 	assert.Nil(t, err)
@@ -94,6 +101,106 @@ func Test_JobManager_Status_Superuser(t *testing.T) {
 	assert.Equal(t, userName1, status.Owner)
 }
 
+func Test_JobManager_Stats_MatchingUser(t *testing.T) {
+	const userName1 = "user1"
+	const jobName = "user1-job"
+	const programPath = "/bin/true"
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
+	stats, err := jm.Stats(userName1, job.ID().String())
+
+	assert.Nil(t, err)
+	assert.Equal(t, jobmanagertest.DefaultCpuUsage, stats.CpuUsage)
+	assert.Equal(t, uint64(jobmanagertest.DefaultPids), stats.Pids)
+}
+
+func Test_JobManager_Stats_NonMatchingUser(t *testing.T) {
+	const userName1 = "user1"
+	const jobName = "user1-job"
+	const programPath = "/bin/true"
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
+	_, err := jm.Stats("someOtherUser", job.ID().String())
+
+	assert.ErrorIs(t, err, jobmanager.ErrJobNotFound)
+}
+
+func Test_JobManager_Stats_NotRunning(t *testing.T) {
+	const userName1 = "user1"
+	const jobName = "user1-job"
+	const programPath = "/bin/true"
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
+	require.Nil(t, jm.Stop(userName1, job.ID().String()))
+
+	_, err := jm.Stats(userName1, job.ID().String())
+
+	assert.ErrorIs(t, err, jobmanager.ErrJobNotRunning)
+}
+
+func Test_JobManager_Processes_MatchingUser(t *testing.T) {
+	const userName1 = "user1"
+	const jobName = "user1-job"
+	const programPath = "/bin/true"
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
+	processes, err := jm.Processes(userName1, job.ID().String())
+
+	assert.Nil(t, err)
+	require.Equal(t, 1, len(processes))
+	assert.Equal(t, jobmanagertest.DefaultPID, processes[0].Pid)
+}
+
+func Test_JobManager_Processes_NonMatchingUser(t *testing.T) {
+	const userName1 = "user1"
+	const jobName = "user1-job"
+	const programPath = "/bin/true"
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
+	_, err := jm.Processes("someOtherUser", job.ID().String())
+
+	assert.ErrorIs(t, err, jobmanager.ErrJobNotFound)
+}
+
+func Test_JobManager_Processes_Superuser(t *testing.T) {
+	const userName1 = "user1"
+	const jobName = "user1-job"
+	const programPath = "/bin/true"
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
+	processes, err := jm.Processes(jobmanager.Superuser, job.ID().String())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(processes))
+}
+
+func Test_JobManager_Processes_NotRunning(t *testing.T) {
+	const userName1 = "user1"
+	const jobName = "user1-job"
+	const programPath = "/bin/true"
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
+	require.Nil(t, jm.Stop(userName1, job.ID().String()))
+
+	_, err := jm.Processes(userName1, job.ID().String())
+
+	assert.ErrorIs(t, err, jobmanager.ErrJobNotRunning)
+}
+
 func Test_JobManager_Stop_MatchingUser(t *testing.T) {
 	const userName1 = "user1"
 	const jobName = "user1-job"
@@ -101,7 +208,7 @@ func Test_JobManager_Stop_MatchingUser(t *testing.T) {
 
 	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
 
-	job, _ := jm.Start(userName1, jobName, programPath, nil)
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
 	_ = jm.Stop(userName1, job.ID().String())
 	status, err := jm.Status(userName1, job.ID().String())
 
@@ -117,7 +224,7 @@ func Test_JobManager_Stop_NonmatchingUser(t *testing.T) {
 
 	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
 
-	job, _ := jm.Start(userName1, jobName, programPath, nil)
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
 	err := jm.Stop("someOtherUser", job.ID().String())
 
 	assert.Error(t, err)
@@ -129,7 +236,7 @@ func Test_JobManager_Stop_Superuser(t *testing.T) {
 
 	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
 
-	job, _ := jm.Start(userName1, jobName, programPath, nil)
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
 	_ = jm.Stop(jobmanager.Superuser, job.ID().String())
 
 	status, err := jm.Status(userName1, job.ID().String())
@@ -147,8 +254,8 @@ func Test_JobManager_List_MatchingUser(t *testing.T) {
 
 	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
 
-	_, _ = jm.Start(userName1, jobName, programPath, nil)
-	_, _ = jm.Start(userName2, jobName, programPath, nil)
+	_, _ = jm.Start(userName1, jobName, programPath, nil, nil)
+	_, _ = jm.Start(userName2, jobName, programPath, nil, nil)
 	jobList := jm.List(userName1)
 
 	assert.Equal(t, 1, len(jobList))
@@ -163,7 +270,7 @@ func Test_JobManager_List_NonmatchingUser(t *testing.T) {
 
 	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
 
-	_, _ = jm.Start(userName1, jobName, programPath, nil)
+	_, _ = jm.Start(userName1, jobName, programPath, nil, nil)
 	jobList := jm.List("someOtherUser")
 
 	assert.Equal(t, 0, len(jobList))
@@ -176,7 +283,7 @@ func Test_JobManager_List_Superuser(t *testing.T) {
 
 	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
 
-	_, _ = jm.Start(userName1, jobName, programPath, nil)
+	_, _ = jm.Start(userName1, jobName, programPath, nil, nil)
 	jobList := jm.List(jobmanager.Superuser)
 
 	assert.Equal(t, 1, len(jobList))
@@ -192,8 +299,8 @@ func Test_JobManager_List_Superuser_MultipleUsersJobs(t *testing.T) {
 
 	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
 
-	_, _ = jm.Start(userName1, jobName, programPath, nil)
-	_, _ = jm.Start(userName2, jobName, programPath, nil)
+	_, _ = jm.Start(userName1, jobName, programPath, nil, nil)
+	_, _ = jm.Start(userName2, jobName, programPath, nil, nil)
 
 	jobList := jm.List(jobmanager.Superuser)
 
@@ -207,7 +314,7 @@ func Test_JobManager_StdoutStream_MatchingUser(t *testing.T) {
 
 	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
 
-	job, _ := jm.Start(userName1, jobName, programPath, nil)
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
 	_ = job.Stop()
 
 	stream, err := jm.StdoutStream(userName1, job.ID().String())
@@ -223,7 +330,7 @@ func Test_JobManager_StdoutStream_NonmatchingUser(t *testing.T) {
 
 	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
 
-	job, _ := jm.Start(userName1, jobName, programPath, nil)
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
 	_ = job.Stop()
 
 	_, err := jm.StdoutStream("someOtherUser", job.ID().String())
@@ -238,7 +345,7 @@ func Test_JobManager_StdoutStream_Superuser(t *testing.T) {
 
 	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
 
-	job, _ := jm.Start(userName1, jobName, programPath, nil)
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
 	_ = job.Stop()
 
 	stream, err := jm.StdoutStream(jobmanager.Superuser, job.ID().String())
@@ -254,7 +361,7 @@ func Test_JobManager_StderrStream_MatchingUser(t *testing.T) {
 
 	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
 
-	job, _ := jm.Start(userName1, jobName, programPath, nil)
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
 	_ = job.Stop()
 
 	stream, err := jm.StderrStream(userName1, job.ID().String())
@@ -270,7 +377,7 @@ func Test_JobManager_StderrStream_NonmatchingUser(t *testing.T) {
 
 	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
 
-	job, _ := jm.Start(userName1, jobName, programPath, nil)
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
 	_ = job.Stop()
 
 	_, err := jm.StderrStream("someOtherUser", job.ID().String())
@@ -285,7 +392,7 @@ func Test_JobManager_StderrStream_Superuser(t *testing.T) {
 
 	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
 
-	job, _ := jm.Start(userName1, jobName, programPath, nil)
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
 	_ = job.Stop()
 
 	stream, err := jm.StderrStream(jobmanager.Superuser, job.ID().String())
@@ -293,3 +400,793 @@ func Test_JobManager_StderrStream_Superuser(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, jobmanagertest.DefaultStandardError, string(<-stream.Stream()))
 }
+
+func Test_JobManager_NewManager_AllControllersAvailable(t *testing.T) {
+	hierarchy := &cgroup.Hierarchy{
+		Version:     cgroup.V1,
+		BasePath:    "/sys/fs/cgroup",
+		Controllers: []string{"blkio", "cpu", "cpuacct", "cpuset", "memory", "pids"},
+	}
+
+	jm, missing := jobmanager.NewManager(hierarchy, nil, nil)
+	info := jm.ServerInfo()
+
+	assert.Equal(t, 0, len(missing))
+	assert.Equal(t, "v1", info.CgroupVersion)
+	assert.Equal(t, []string{"cpu", "memory", "blkio", "pids", "cpuset"}, info.Capabilities)
+}
+
+func Test_JobManager_NewManager_Freezer(t *testing.T) {
+	hierarchy := &cgroup.Hierarchy{
+		Version:     cgroup.V1,
+		BasePath:    "/sys/fs/cgroup",
+		Controllers: []string{"blkio", "cpu", "cpuacct", "cpuset", "freezer", "memory", "pids"},
+	}
+
+	jm, missing := jobmanager.NewManager(hierarchy, nil, nil)
+	info := jm.ServerInfo()
+
+	assert.Equal(t, 0, len(missing))
+	assert.Equal(t, []string{"cpu", "memory", "blkio", "pids", "cpuset", "freezer"}, info.Capabilities)
+}
+
+func Test_JobManager_NewManager_MissingControllers(t *testing.T) {
+	hierarchy := &cgroup.Hierarchy{
+		Version:     cgroup.V2,
+		BasePath:    "/sys/fs/cgroup",
+		Controllers: []string{"cpu", "cpuset", "io", "pids"},
+	}
+
+	jm, missing := jobmanager.NewManager(hierarchy, nil, nil)
+	info := jm.ServerInfo()
+
+	assert.Equal(t, []string{"memory"}, missing)
+	assert.Equal(t, "v2", info.CgroupVersion)
+	assert.Equal(t, []string{"cpu", "io", "pids", "cpuset"}, info.Capabilities)
+}
+
+// recordingJobConstructor returns a JobConstructor that creates mock jobs
+// and records the controllers with which the most recent job was created.
+func recordingJobConstructor(controllers *[]cgroup.Controller) jobmanager.JobConstructor {
+	return func(
+		owner string,
+		jobName string,
+		jobControllers []cgroup.Controller,
+		programPath string,
+		arguments ...string,
+	) jobmanager.Job {
+		*controllers = jobControllers
+		return jobmanagertest.NewMockJob(owner, jobName, jobControllers, programPath, arguments...)
+	}
+}
+
+func Test_JobManager_Start_BlockIOLimits(t *testing.T) {
+	var controllers []cgroup.Controller
+
+	defaults := &cgroupv1.BlockIOController{
+		ReadBpsDevices: []string{"8:0 1048576"},
+	}
+	jm := jobmanager.NewManagerDetailed(recordingJobConstructor(&controllers),
+		[]cgroup.Controller{defaults})
+
+	_, err := jm.Start("user1", "job1", "/bin/true", nil, &jobmanager.JobLimits{
+		BlockIO: []jobmanager.DeviceLimit{
+			{Device: "259:0", ReadBps: 2097152, ReadIops: 100, WriteIops: 50},
+		},
+	})
+
+	require.Nil(t, err)
+	require.Equal(t, 1, len(controllers))
+	assert.Equal(t, &cgroupv1.BlockIOController{
+		ReadBpsDevices:   []string{"8:0 1048576", "259:0 2097152"},
+		ReadIopsDevices:  []string{"259:0 100"},
+		WriteIopsDevices: []string{"259:0 50"},
+	}, controllers[0])
+
+	// The defaults of the manager are unchanged
+	assert.Equal(t, []string{"8:0 1048576"}, defaults.ReadBpsDevices)
+}
+
+func Test_JobManager_Start_BlockIOLimits_OverrideDefault(t *testing.T) {
+	var controllers []cgroup.Controller
+
+	jm := jobmanager.NewManagerDetailed(recordingJobConstructor(&controllers),
+		[]cgroup.Controller{
+			&cgroupv2.IOController{
+				ReadBpsDevices:  []string{"8:0 1048576"},
+				WriteBpsDevices: []string{"8:0 1048576", "259:0 1048576"},
+			},
+		})
+
+	_, err := jm.Start("user1", "job1", "/bin/true", nil, &jobmanager.JobLimits{
+		BlockIO: []jobmanager.DeviceLimit{
+			{Device: "8:0", WriteBps: 4194304, WriteIops: 50},
+		},
+	})
+
+	require.Nil(t, err)
+	require.Equal(t, 1, len(controllers))
+	assert.Equal(t, &cgroupv2.IOController{
+		ReadBpsDevices:   []string{"8:0 1048576"},
+		WriteBpsDevices:  []string{"259:0 1048576", "8:0 4194304"},
+		WriteIopsDevices: []string{"8:0 50"},
+	}, controllers[0])
+}
+
+func Test_JobManager_Start_BlockIOLimits_Unsupported(t *testing.T) {
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+
+	job, err := jm.Start("user1", "job1", "/bin/true", nil, &jobmanager.JobLimits{
+		BlockIO: []jobmanager.DeviceLimit{{Device: "8:0", ReadIops: 100}},
+	})
+
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument)
+	assert.Nil(t, job)
+}
+
+func Test_JobManager_Start_PidsLimit(t *testing.T) {
+	var controllers []cgroup.Controller
+
+	jm := jobmanager.NewManagerDetailed(recordingJobConstructor(&controllers),
+		[]cgroup.Controller{
+			&cgroupv2.CpuController{Cpus: 0.5},
+			&cgroupv2.PidsController{Limit: 1024},
+		})
+
+	_, err := jm.Start("user1", "job1", "/bin/true", nil, &jobmanager.JobLimits{
+		MaxPids: 16,
+	})
+
+	require.Nil(t, err)
+	assert.Equal(t, []cgroup.Controller{
+		&cgroupv2.CpuController{Cpus: 0.5},
+		&cgroupv2.PidsController{Limit: 16},
+	}, controllers)
+}
+
+func Test_JobManager_Start_PidsLimit_Unsupported(t *testing.T) {
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob,
+		[]cgroup.Controller{&cgroupv1.BlockIOController{}})
+
+	job, err := jm.Start("user1", "job1", "/bin/true", nil, &jobmanager.JobLimits{
+		MaxPids: 16,
+	})
+
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument)
+	assert.Nil(t, job)
+}
+
+func Test_JobManager_Start_PidsLimit_Maximum(t *testing.T) {
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob,
+		[]cgroup.Controller{&cgroupv2.PidsController{Limit: 1024}})
+
+	job, err := jm.Start("user1", "job1", "/bin/true", nil, &jobmanager.JobLimits{
+		MaxPids: config.CgroupMaxPidsLimit + 1,
+	})
+
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument)
+	assert.Nil(t, job)
+
+	job, err = jm.Start("user1", "job2", "/bin/true", nil, &jobmanager.JobLimits{
+		MaxPids: config.CgroupMaxPidsLimit,
+	})
+
+	assert.Nil(t, err)
+	assert.NotNil(t, job)
+}
+
+func Test_JobManager_Start_Cpuset(t *testing.T) {
+	var controllers []cgroup.Controller
+
+	jm := jobmanager.NewManagerDetailed(recordingJobConstructor(&controllers),
+		[]cgroup.Controller{&cgroupv1.CpusetController{}})
+
+	_, err := jm.Start("user1", "job1", "/bin/true", nil, &jobmanager.JobLimits{
+		CpusetCpus: "2-3",
+	})
+
+	require.Nil(t, err)
+	assert.Equal(t, []cgroup.Controller{
+		&cgroupv1.CpusetController{Cpus: "2-3"},
+	}, controllers)
+}
+
+func Test_JobManager_Start_CpuWeight_V1(t *testing.T) {
+	var controllers []cgroup.Controller
+
+	jm := jobmanager.NewManagerDetailed(recordingJobConstructor(&controllers),
+		[]cgroup.Controller{&cgroupv1.CpuController{Cpus: 0.5, PeriodUs: 100000}})
+
+	_, err := jm.Start("user1", "job1", "/bin/true", nil, &jobmanager.JobLimits{
+		UnlimitedCpus: true,
+		CpuWeight:     200,
+	})
+
+	require.Nil(t, err)
+	assert.Equal(t, []cgroup.Controller{
+		&cgroupv1.CpuController{PeriodUs: 100000, Shares: 2048},
+	}, controllers)
+}
+
+func Test_JobManager_Start_CpuQuotaAndPeriod_V2(t *testing.T) {
+	var controllers []cgroup.Controller
+
+	jm := jobmanager.NewManagerDetailed(recordingJobConstructor(&controllers),
+		[]cgroup.Controller{&cgroupv2.CpuController{Cpus: 0.5, PeriodUs: 100000}})
+
+	_, err := jm.Start("user1", "job1", "/bin/true", nil, &jobmanager.JobLimits{
+		Cpus:        2,
+		CpuPeriodUs: 500000,
+		CpuWeight:   50,
+	})
+
+	require.Nil(t, err)
+	assert.Equal(t, []cgroup.Controller{
+		&cgroupv2.CpuController{Cpus: 2, PeriodUs: 500000, Weight: 50},
+	}, controllers)
+}
+
+func Test_JobManager_Start_InvalidCpuLimits(t *testing.T) {
+	invalidLimits := []*jobmanager.JobLimits{
+		{Cpus: -1},
+		{Cpus: 1, UnlimitedCpus: true},
+		{CpuWeight: jobmanager.MaxCpuWeight + 1},
+		{CpuPeriodUs: jobmanager.MinCpuPeriodUs - 1},
+		{CpuPeriodUs: jobmanager.MaxCpuPeriodUs + 1},
+		{Cpus: 0.005},                            // 500us with the default period
+		{Cpus: 0.5, CpuPeriodUs: 1999},           // 999us
+		{CpuPeriodUs: jobmanager.MinCpuPeriodUs}, // 500us with the default cpus
+		{Cpus: config.CgroupMaxCpuLimit + 1},
+	}
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob,
+		[]cgroup.Controller{&cgroupv2.CpuController{Cpus: 0.5}})
+
+	for i, limits := range invalidLimits {
+		job, err := jm.Start("user1", "job1", "/bin/true", nil, limits)
+
+		assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument, i)
+		assert.Nil(t, job)
+	}
+}
+
+func Test_JobManager_Start_CpuLimits_Maximum(t *testing.T) {
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob,
+		[]cgroup.Controller{&cgroupv2.CpuController{Cpus: 0.5}})
+
+	// The maximum caps the quota; it does not forbid unlimited cpus
+	for i, limits := range []*jobmanager.JobLimits{
+		{Cpus: config.CgroupMaxCpuLimit},
+		{UnlimitedCpus: true},
+	} {
+		job, err := jm.Start("user1", fmt.Sprintf("job%d", i), "/bin/true", nil, limits)
+
+		assert.Nil(t, err, i)
+		assert.NotNil(t, job, i)
+	}
+}
+
+func Test_JobManager_Start_UnlimitedCpus_NotAllowed(t *testing.T) {
+	saved := config.CgroupAllowUnlimitedCpus
+	config.CgroupAllowUnlimitedCpus = false
+	t.Cleanup(func() { config.CgroupAllowUnlimitedCpus = saved })
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob,
+		[]cgroup.Controller{&cgroupv2.CpuController{Cpus: 0.5}})
+
+	job, err := jm.Start("user1", "job1", "/bin/true", nil, &jobmanager.JobLimits{
+		UnlimitedCpus: true,
+	})
+
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument)
+	assert.Nil(t, job)
+}
+
+func Test_JobManager_Start_MinCpuQuota(t *testing.T) {
+	var controllers []cgroup.Controller
+
+	jm := jobmanager.NewManagerDetailed(recordingJobConstructor(&controllers),
+		[]cgroup.Controller{&cgroupv2.CpuController{Cpus: 0.5, PeriodUs: 100000}})
+
+	// 10m CPUs with the default period is exactly the minimum quota
+	_, err := jm.Start("user1", "job1", "/bin/true", nil, &jobmanager.JobLimits{Cpus: 0.01})
+
+	require.Nil(t, err)
+	assert.Equal(t, []cgroup.Controller{
+		&cgroupv2.CpuController{Cpus: 0.01, PeriodUs: 100000},
+	}, controllers)
+}
+
+func Test_JobManager_Start_MemoryLimits_V1(t *testing.T) {
+	var controllers []cgroup.Controller
+
+	jm := jobmanager.NewManagerDetailed(recordingJobConstructor(&controllers),
+		[]cgroup.Controller{&cgroupv1.MemoryController{Limit: "2M"}})
+
+	_, err := jm.Start("user1", "job1", "/bin/true", nil, &jobmanager.JobLimits{
+		MemoryLimit:     64 << 20,
+		MemorySwapLimit: 64 << 20,
+		MemorySoftLimit: 32 << 20,
+		OomPolicy:       jobmanager.OomPolicyPause,
+	})
+
+	require.Nil(t, err)
+	assert.Equal(t, []cgroup.Controller{
+		&cgroupv1.MemoryController{
+			Limit:          "67108864",
+			MemswLimit:     "67108864",
+			SoftLimit:      "33554432",
+			OomKillDisable: true,
+		},
+	}, controllers)
+}
+
+func Test_JobManager_Start_MemoryLimits_V2(t *testing.T) {
+	var controllers []cgroup.Controller
+
+	jm := jobmanager.NewManagerDetailed(recordingJobConstructor(&controllers),
+		[]cgroup.Controller{&cgroupv2.MemoryController{Limit: "2M"}})
+
+	_, err := jm.Start("user1", "job1", "/bin/true", nil, &jobmanager.JobLimits{
+		MemorySwapLimit: 3 << 20,
+		MemorySoftLimit: 1 << 20,
+		OomPolicy:       jobmanager.OomPolicyKill,
+	})
+
+	require.Nil(t, err)
+	assert.Equal(t, []cgroup.Controller{
+		&cgroupv2.MemoryController{
+			Limit:     "2M",
+			Low:       "1048576",
+			SwapLimit: "1048576",
+			OomGroup:  true,
+		},
+	}, controllers)
+}
+
+func Test_JobManager_Start_MemoryPause_V2(t *testing.T) {
+	var controllers []cgroup.Controller
+
+	jm := jobmanager.NewManagerDetailed(recordingJobConstructor(&controllers),
+		[]cgroup.Controller{&cgroupv2.MemoryController{Limit: "2M"}})
+
+	_, err := jm.Start("user1", "job1", "/bin/true", nil, &jobmanager.JobLimits{
+		OomPolicy: jobmanager.OomPolicyPause,
+	})
+
+	require.Nil(t, err)
+	assert.Equal(t, []cgroup.Controller{
+		&cgroupv2.MemoryController{Limit: "2M", High: "1887436"},
+	}, controllers)
+}
+
+func Test_JobManager_Start_MemoryPause_V2_KeepsLimit(t *testing.T) {
+	var controllers []cgroup.Controller
+
+	jm := jobmanager.NewManagerDetailed(recordingJobConstructor(&controllers),
+		[]cgroup.Controller{&cgroupv2.MemoryController{Limit: "2M"}})
+
+	for _, memoryLimit := range []uint64{0, 64 << 20} {
+		controllers = nil
+
+		_, err := jm.Start("user1", fmt.Sprintf("job-%d", memoryLimit), "/bin/true", nil,
+			&jobmanager.JobLimits{MemoryLimit: memoryLimit, OomPolicy: jobmanager.OomPolicyPause})
+
+		require.Nil(t, err)
+		require.Len(t, controllers, 1)
+
+		memory := controllers[0].(*cgroupv2.MemoryController)
+		assert.NotEqual(t, "max", memory.Limit, memoryLimit)
+		assert.NotEqual(t, "", memory.Limit, memoryLimit)
+		assert.NotEqual(t, "", memory.High, memoryLimit)
+	}
+}
+
+func Test_JobManager_Start_MemoryLimits_Maximum(t *testing.T) {
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob,
+		[]cgroup.Controller{&cgroupv2.MemoryController{Limit: "2M"}})
+
+	job, err := jm.Start("user1", "job1", "/bin/true", nil, &jobmanager.JobLimits{
+		MemoryLimit:     uint64(config.CgroupMaxMemoryLimit),
+		MemorySwapLimit: uint64(config.CgroupMaxMemoryLimit),
+		MemorySoftLimit: uint64(config.CgroupMaxMemoryLimit),
+	})
+
+	assert.Nil(t, err)
+	assert.NotNil(t, job)
+}
+
+func Test_JobManager_Start_InvalidMemoryLimits(t *testing.T) {
+	invalidLimits := []*jobmanager.JobLimits{
+		{MemoryLimit: 2 << 20, MemorySwapLimit: 1 << 20},
+		{MemorySwapLimit: uint64(config.CgroupDefaultMemoryLimit) - 1},
+		{MemoryLimit: uint64(config.CgroupMaxMemoryLimit) + 1},
+		{MemorySwapLimit: uint64(config.CgroupMaxMemoryLimit) + 1},
+		{MemorySoftLimit: uint64(config.CgroupMaxMemoryLimit) + 1},
+		{MemorySoftLimit: uint64(config.CgroupDefaultMemoryLimit) + 1},
+		{MemoryLimit: 1 << 20, MemorySoftLimit: 2 << 20},
+		{OomPolicy: jobmanager.OomPolicyPause + 1},
+	}
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob,
+		[]cgroup.Controller{&cgroupv2.MemoryController{Limit: "2M"}})
+
+	for i, limits := range invalidLimits {
+		job, err := jm.Start("user1", "job1", "/bin/true", nil, limits)
+
+		assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument, i)
+		assert.Nil(t, job)
+	}
+}
+
+func Test_JobManager_Start_InvalidUserID(t *testing.T) {
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+
+	for _, userID := range []string{"", ".", "..", "user/1"} {
+		job, err := jm.Start(userID, "job1", "/bin/true", nil, nil)
+
+		assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument, userID)
+		assert.Nil(t, job, userID)
+	}
+}
+
+func Test_JobManager_Exec_MatchingUser(t *testing.T) {
+	const userName1 = "user1"
+	const jobName = "user1-job"
+	const programPath = "/bin/true"
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
+	process, err := jm.Exec(userName1, job.ID().String(), "/bin/ps", nil)
+
+	assert.Nil(t, err)
+	require.NotNil(t, process)
+	assert.Equal(t, jobmanagertest.DefaultExecExitCode, process.Wait().ExitCode)
+}
+
+func Test_JobManager_Exec_NonMatchingUser(t *testing.T) {
+	const userName1 = "user1"
+	const jobName = "user1-job"
+	const programPath = "/bin/true"
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
+	_, err := jm.Exec("someOtherUser", job.ID().String(), "/bin/ps", nil)
+
+	assert.ErrorIs(t, err, jobmanager.ErrJobNotFound)
+}
+
+func Test_JobManager_Exec_NotRunning(t *testing.T) {
+	const userName1 = "user1"
+	const jobName = "user1-job"
+	const programPath = "/bin/true"
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
+	require.Nil(t, jm.Stop(userName1, job.ID().String()))
+
+	_, err := jm.Exec(userName1, job.ID().String(), "/bin/ps", nil)
+
+	assert.ErrorIs(t, err, jobmanager.ErrJobNotRunning)
+}
+
+func Test_JobManager_Create(t *testing.T) {
+	const userName1 = "user1"
+	const jobName = "user1-job"
+	const programPath = "/bin/true"
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+
+	job, err := jm.Create(userName1, jobName, programPath, nil, nil)
+	require.Nil(t, err)
+	require.NotNil(t, job)
+
+	status, err := jm.Status(userName1, job.ID().String())
+
+	assert.Nil(t, err)
+	assert.Equal(t, jobmanager.JobStateCreated, status.State)
+	assert.False(t, status.Running)
+}
+
+// failingCreateJob is a Job whose Create always fails.
+type failingCreateJob struct {
+	jobmanager.Job
+	released bool
+}
+
+var errCreateFailed = errors.New("create failed")
+
+func (f *failingCreateJob) Create() error {
+	return errCreateFailed
+}
+
+func (f *failingCreateJob) Release() error {
+	f.released = true
+	return f.Job.Release()
+}
+
+func Test_JobManager_Create_Failure(t *testing.T) {
+	const userName1 = "user1"
+	const jobName = "user1-job"
+	const programPath = "/bin/true"
+
+	var createdJob *failingCreateJob
+	jobConstructor := func(
+		owner string,
+		jobName string,
+		controllers []cgroup.Controller,
+		programPath string,
+		arguments ...string,
+	) jobmanager.Job {
+		createdJob = &failingCreateJob{
+			Job: jobmanagertest.NewMockJob(owner, jobName, controllers, programPath, arguments...),
+		}
+		return createdJob
+	}
+
+	jm := jobmanager.NewManagerDetailed(jobConstructor, nil)
+
+	job, err := jm.Create(userName1, jobName, programPath, nil, nil)
+
+	assert.ErrorIs(t, err, errCreateFailed)
+	assert.Nil(t, job)
+
+	// The job that failed to be created is not registered
+	require.NotNil(t, createdJob)
+	_, err = jm.Status(userName1, createdJob.ID().String())
+	assert.ErrorIs(t, err, jobmanager.ErrJobNotFound)
+	assert.Empty(t, jm.List(userName1))
+
+	// The job's output buffers are freed
+	assert.True(t, createdJob.released)
+}
+
+func Test_JobManager_Launch(t *testing.T) {
+	const userName1 = "user1"
+	const jobName = "user1-job"
+	const programPath = "/bin/true"
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+
+	job, err := jm.Create(userName1, jobName, programPath, nil, nil)
+	require.Nil(t, err)
+
+	err = jm.Launch(userName1, job.ID().String())
+	require.Nil(t, err)
+
+	status, err := jm.Status(userName1, job.ID().String())
+
+	assert.Nil(t, err)
+	assert.Equal(t, jobmanager.JobStateRunning, status.State)
+	assert.True(t, status.Running)
+}
+
+func Test_JobManager_Launch_Twice(t *testing.T) {
+	const userName1 = "user1"
+	const jobName = "user1-job"
+	const programPath = "/bin/true"
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+
+	job, _ := jm.Create(userName1, jobName, programPath, nil, nil)
+	require.Nil(t, jm.Launch(userName1, job.ID().String()))
+
+	err := jm.Launch(userName1, job.ID().String())
+
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidJobState)
+}
+
+func Test_JobManager_Launch_Stopped(t *testing.T) {
+	const userName1 = "user1"
+	const jobName = "user1-job"
+	const programPath = "/bin/true"
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+
+	job, _ := jm.Create(userName1, jobName, programPath, nil, nil)
+	require.Nil(t, jm.Stop(userName1, job.ID().String()))
+
+	err := jm.Launch(userName1, job.ID().String())
+
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidJobState)
+
+	status, err := jm.Status(userName1, job.ID().String())
+	assert.Nil(t, err)
+	assert.Equal(t, jobmanager.JobStateTerminated, status.State)
+}
+
+func Test_JobManager_Launch_NonMatchingUser(t *testing.T) {
+	const userName1 = "user1"
+	const jobName = "user1-job"
+	const programPath = "/bin/true"
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+
+	job, _ := jm.Create(userName1, jobName, programPath, nil, nil)
+	err := jm.Launch("someOtherUser", job.ID().String())
+
+	assert.ErrorIs(t, err, jobmanager.ErrJobNotFound)
+}
+
+func Test_JobManager_Remove(t *testing.T) {
+	const userName1 = "user1"
+	const jobName = "user1-job"
+	const programPath = "/bin/true"
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
+	require.Nil(t, jm.Stop(userName1, job.ID().String()))
+
+	err := jm.Remove(userName1, job.ID().String())
+	require.Nil(t, err)
+
+	_, err = jm.Status(userName1, job.ID().String())
+	assert.ErrorIs(t, err, jobmanager.ErrJobNotFound)
+	assert.Empty(t, jm.List(userName1))
+
+	// The job's name can be reused
+	_, err = jm.Start(userName1, jobName, programPath, nil, nil)
+	assert.Nil(t, err)
+}
+
+func Test_JobManager_Remove_Running(t *testing.T) {
+	const userName1 = "user1"
+	const jobName = "user1-job"
+	const programPath = "/bin/true"
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
+
+	err := jm.Remove(userName1, job.ID().String())
+
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidJobState)
+	assert.Len(t, jm.List(userName1), 1)
+}
+
+func Test_JobManager_Remove_Superuser(t *testing.T) {
+	const userName1 = "user1"
+	const jobName = "user1-job"
+	const programPath = "/bin/true"
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
+	require.Nil(t, jm.Stop(userName1, job.ID().String()))
+
+	err := jm.Remove(jobmanager.Superuser, job.ID().String())
+	require.Nil(t, err)
+
+	assert.Empty(t, jm.List(userName1))
+
+	_, err = jm.Start(userName1, jobName, programPath, nil, nil)
+	assert.Nil(t, err)
+}
+
+func Test_JobManager_Remove_NonMatchingUser(t *testing.T) {
+	const userName1 = "user1"
+	const jobName = "user1-job"
+	const programPath = "/bin/true"
+
+	jm := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+
+	job, _ := jm.Start(userName1, jobName, programPath, nil, nil)
+	require.Nil(t, jm.Stop(userName1, job.ID().String()))
+
+	err := jm.Remove("someOtherUser", job.ID().String())
+
+	assert.ErrorIs(t, err, jobmanager.ErrJobNotFound)
+	assert.Len(t, jm.List(userName1), 1)
+}
+
+// blockingJob is a Job whose Start and Stop block until released.
+type blockingJob struct {
+	jobmanager.Job
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingJob) Start() error {
+	b.entered <- struct{}{}
+	<-b.release
+	return b.Job.Start()
+}
+
+func (b *blockingJob) Stop() error {
+	b.entered <- struct{}{}
+	<-b.release
+	return b.Job.Stop()
+}
+
+func Test_JobManager_LaunchAndStop_DoNotBlockOtherOperations(t *testing.T) {
+	const userName1 = "user1"
+	const programPath = "/bin/true"
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	jobConstructor := func(
+		owner string,
+		jobName string,
+		controllers []cgroup.Controller,
+		programPath string,
+		arguments ...string,
+	) jobmanager.Job {
+		return &blockingJob{
+			Job:     jobmanagertest.NewMockJob(owner, jobName, controllers, programPath, arguments...),
+			entered: entered,
+			release: release,
+		}
+	}
+
+	jm := jobmanager.NewManagerDetailed(jobConstructor, nil)
+
+	job, err := jm.Create(userName1, "job1", programPath, nil, nil)
+	require.Nil(t, err)
+
+	for _, operation := range []func() error{
+		func() error { return jm.Launch(userName1, job.ID().String()) },
+		func() error { return jm.Stop(userName1, job.ID().String()) },
+	} {
+		done := make(chan error)
+		go func() { done <- operation() }()
+
+		<-entered
+
+		// The operation is blocked in the job, but other operations proceed
+		jobCount := len(jm.List(userName1))
+		_, err = jm.Create(userName1, fmt.Sprintf("other-%d", jobCount), programPath, nil, nil)
+		assert.Nil(t, err)
+		assert.Len(t, jm.List(userName1), jobCount+1)
+
+		close(release)
+		assert.Nil(t, <-done)
+		release = make(chan struct{})
+	}
+}
+
+// failingStartJob is a Job whose Start always fails.
+type failingStartJob struct {
+	jobmanager.Job
+}
+
+var errStartFailed = errors.New("start failed")
+
+func (f *failingStartJob) Start() error {
+	return errStartFailed
+}
+
+func Test_JobManager_Start_Failure(t *testing.T) {
+	const userName1 = "user1"
+	const jobName = "user1-job"
+	const programPath = "/bin/true"
+
+	jobConstructor := func(
+		owner string,
+		jobName string,
+		controllers []cgroup.Controller,
+		programPath string,
+		arguments ...string,
+	) jobmanager.Job {
+		return &failingStartJob{
+			Job: jobmanagertest.NewMockJob(owner, jobName, controllers, programPath, arguments...),
+		}
+	}
+
+	jm := jobmanager.NewManagerDetailed(jobConstructor, nil)
+
+	job, err := jm.Start(userName1, jobName, programPath, nil, nil)
+
+	assert.ErrorIs(t, err, errStartFailed)
+	assert.Nil(t, job)
+
+	// The job that failed to start is not kept, so its name can be reused
+	assert.Empty(t, jm.List(userName1))
+
+	_, err = jm.Create(userName1, jobName, programPath, nil, nil)
+	assert.Nil(t, err)
+}
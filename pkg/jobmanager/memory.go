@@ -0,0 +1,206 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobmanager
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2"
+	"github.com/adalton/teleport-exercise/pkg/config"
+	"github.com/adalton/teleport-exercise/pkg/quantity"
+)
+
+// OomPolicy describes what happens to a job whose memory usage reaches its
+// memory limit.
+type OomPolicy int
+
+const (
+	// OomPolicyDefault keeps the server's default behavior.
+	OomPolicyDefault OomPolicy = iota
+
+	// OomPolicyKill kills the job when it runs out of memory.
+	OomPolicyKill
+
+	// OomPolicyPause pauses the job when it runs out of memory, until memory
+	// is freed or the limit is raised.  Cgroup v2 cannot pause a cgroup on
+	// OOM, so on v2 the job is instead throttled once its usage reaches
+	// oomPauseHighPercent of its memory limit; the limit itself still
+	// applies.
+	OomPolicyPause
+)
+
+// oomPauseHighPercent is the percentage of a job's memory limit at which a
+// job with OomPolicyPause is throttled on cgroup v2.
+const oomPauseHighPercent = 90
+
+func (p OomPolicy) String() string {
+	switch p {
+	case OomPolicyDefault:
+		return "default"
+	case OomPolicyKill:
+		return "kill"
+	case OomPolicyPause:
+		return "pause"
+	}
+
+	return fmt.Sprintf("OomPolicy(%d)", int(p))
+}
+
+// ParseOomPolicy returns the OomPolicy with the given name.  An empty name
+// is OomPolicyDefault.  If the name is unknown, it returns an error that wraps
+// ErrInvalidArgument.
+func ParseOomPolicy(name string) (OomPolicy, error) {
+	switch name {
+	case "", OomPolicyDefault.String():
+		return OomPolicyDefault, nil
+	case OomPolicyKill.String():
+		return OomPolicyKill, nil
+	case OomPolicyPause.String():
+		return OomPolicyPause, nil
+	}
+
+	return OomPolicyDefault, fmt.Errorf("%w: unknown OOM policy '%s'; expected '%s' or '%s'",
+		ErrInvalidArgument, name, OomPolicyKill, OomPolicyPause)
+}
+
+// validateMemoryLimits returns an error that wraps ErrInvalidArgument if the
+// memory limits in the given JobLimits are out of range or contradictory.
+func validateMemoryLimits(l *JobLimits) error {
+	if max := uint64(config.CgroupMaxMemoryLimit); max > 0 {
+		if l.MemoryLimit > max {
+			return fmt.Errorf("%w: memory limit must not exceed %d bytes: %d",
+				ErrInvalidArgument, max, l.MemoryLimit)
+		}
+
+		if l.MemorySwapLimit > max {
+			return fmt.Errorf("%w: memory+swap limit must not exceed %d bytes: %d",
+				ErrInvalidArgument, max, l.MemorySwapLimit)
+		}
+
+		if l.MemorySoftLimit > max {
+			return fmt.Errorf("%w: memory soft limit must not exceed %d bytes: %d",
+				ErrInvalidArgument, max, l.MemorySoftLimit)
+		}
+	}
+
+	// The swap and soft limits are relative to the job's memory limit, which
+	// is the default one unless the job sets its own
+	limit := effectiveMemoryLimit(l)
+
+	if l.MemorySwapLimit != 0 && l.MemorySwapLimit < limit {
+		return fmt.Errorf("%w: memory+swap limit (%d bytes) must not be less than the memory limit (%d bytes)",
+			ErrInvalidArgument, l.MemorySwapLimit, limit)
+	}
+
+	if l.MemorySoftLimit > limit {
+		return fmt.Errorf("%w: memory soft limit (%d bytes) must not exceed the memory limit (%d bytes)",
+			ErrInvalidArgument, l.MemorySoftLimit, limit)
+	}
+
+	if l.OomPolicy < OomPolicyDefault || l.OomPolicy > OomPolicyPause {
+		return fmt.Errorf("%w: unknown OOM policy: %s", ErrInvalidArgument, l.OomPolicy)
+	}
+
+	return nil
+}
+
+// effectiveMemoryLimit returns the memory limit, in bytes, that a job with the
+// given JobLimits will have: its own memory limit if specified, the default
+// memory limit otherwise.
+func effectiveMemoryLimit(l *JobLimits) uint64 {
+	if l.MemoryLimit != 0 {
+		return l.MemoryLimit
+	}
+
+	return uint64(config.CgroupDefaultMemoryLimit)
+}
+
+// hasMemoryLimits returns true if any of the memory limits in the given
+// JobLimits is specified.
+func hasMemoryLimits(l *JobLimits) bool {
+	return l.MemoryLimit > 0 || l.MemorySwapLimit > 0 || l.MemorySoftLimit > 0 ||
+		l.OomPolicy != OomPolicyDefault
+}
+
+// withMemoryLimits returns a copy of the given memory controller with the
+// memory limits in the given JobLimits applied.  If controller is not a memory
+// controller, it returns nil.
+func withMemoryLimits(controller cgroup.Controller, l *JobLimits) cgroup.Controller {
+	switch c := controller.(type) {
+	case *cgroupv1.MemoryController:
+		updated := *c
+		updated.Limit = overrideMemorySize(c.Limit, l.MemoryLimit)
+		updated.MemswLimit = overrideMemorySize(c.MemswLimit, l.MemorySwapLimit)
+		updated.SoftLimit = overrideMemorySize(c.SoftLimit, l.MemorySoftLimit)
+
+		if l.OomPolicy != OomPolicyDefault {
+			updated.OomKillDisable = l.OomPolicy == OomPolicyPause
+		}
+
+		return &updated
+
+	case *cgroupv2.MemoryController:
+		updated := *c
+		updated.Limit = overrideMemorySize(c.Limit, l.MemoryLimit)
+		updated.Low = overrideMemorySize(c.Low, l.MemorySoftLimit)
+
+		// memory.swap.max limits swap alone, so the swap limit is whatever
+		// the memory+swap limit leaves beyond the memory limit.
+		if l.MemorySwapLimit > 0 {
+			memoryLimit, err := quantity.ParseBytes(updated.Limit)
+			if err != nil || uint64(memoryLimit) > l.MemorySwapLimit {
+				memoryLimit = 0
+			}
+
+			updated.SwapLimit = strconv.FormatUint(l.MemorySwapLimit-uint64(memoryLimit), 10)
+		}
+
+		switch l.OomPolicy {
+		case OomPolicyKill:
+			updated.OomGroup = true
+
+		case OomPolicyPause:
+			// Throttle the job below its limit so that it is unlikely to
+			// reach memory.max, where the OOM killer would be invoked.
+			// memory.max stays in place so the limit is still enforced.
+			if memoryLimit, err := quantity.ParseBytes(updated.Limit); err == nil && memoryLimit > 0 {
+				updated.High = strconv.FormatUint(uint64(memoryLimit)*oomPauseHighPercent/100, 10)
+			}
+			updated.OomGroup = false
+		}
+
+		return &updated
+	}
+
+	return nil
+}
+
+// overrideMemorySize returns override formatted as a number of bytes if it is
+// non-zero, value otherwise.
+func overrideMemorySize(value string, override uint64) string {
+	if override != 0 {
+		return strconv.FormatUint(override, 10)
+	}
+
+	return value
+}
+
+// formatMemorySize returns the given size in the form that memory controllers
+// expect.  A zero size is empty, leaving the memory unlimited.
+func formatMemorySize(size quantity.Bytes) string {
+	return overrideMemorySize("", uint64(size))
+}
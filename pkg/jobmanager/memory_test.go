@@ -0,0 +1,38 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobmanager_test
+
+import (
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/jobmanager"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseOomPolicy(t *testing.T) {
+	for _, policy := range []jobmanager.OomPolicy{
+		jobmanager.OomPolicyDefault,
+		jobmanager.OomPolicyKill,
+		jobmanager.OomPolicyPause,
+	} {
+		actual, err := jobmanager.ParseOomPolicy(policy.String())
+
+		assert.Nil(t, err)
+		assert.Equal(t, policy, actual)
+	}
+
+	_, err := jobmanager.ParseOomPolicy("ignore")
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument)
+}
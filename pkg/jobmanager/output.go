@@ -0,0 +1,70 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobmanager
+
+import (
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/adalton/teleport-exercise/pkg/config"
+	"github.com/adalton/teleport-exercise/pkg/io"
+	"github.com/adalton/teleport-exercise/pkg/quantity"
+)
+
+// PrepareOutputBuffers checks that the given bufferType names a kind of
+// output buffer (see config.OutputBufferType) and, if the buffers are spooled
+// to files, creates the given spoolDir if it does not exist.  If bufferType is
+// unknown, or if the buffers are ring buffers and ringCapacity is zero or too
+// large, it returns an error that wraps ErrInvalidArgument.
+func PrepareOutputBuffers(bufferType, spoolDir string, ringCapacity quantity.Bytes) error {
+	switch bufferType {
+	case config.OutputBufferMemory:
+		return nil
+
+	case config.OutputBufferRing:
+		if ringCapacity == 0 || ringCapacity > math.MaxInt32 {
+			return fmt.Errorf("%w: invalid output ring buffer capacity: %d", ErrInvalidArgument, ringCapacity)
+		}
+
+		return nil
+
+	case config.OutputBufferFile:
+		if spoolDir == "" {
+			return nil
+		}
+
+		if err := os.MkdirAll(spoolDir, 0700); err != nil {
+			return fmt.Errorf("failed to create output spool directory: %w", err)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("%w: unknown output buffer type '%s'", ErrInvalidArgument, bufferType)
+}
+
+// newOutputBuffer creates and returns an empty buffer for the output of a job
+// or of a process started in a job, of the kind selected by
+// config.OutputBufferType.
+func newOutputBuffer() io.OutputBuffer {
+	switch config.OutputBufferType {
+	case config.OutputBufferFile:
+		return io.NewFileBuffer(config.OutputSpoolDir)
+	case config.OutputBufferRing:
+		return io.NewRingBuffer(int(config.OutputRingBufferCapacity))
+	}
+
+	return io.NewMemoryBuffer()
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobmanager_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/config"
+	"github.com/adalton/teleport-exercise/pkg/jobmanager"
+	"github.com/adalton/teleport-exercise/pkg/quantity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PrepareOutputBuffers_Memory(t *testing.T) {
+	assert.Nil(t, jobmanager.PrepareOutputBuffers(config.OutputBufferMemory, "", 0))
+}
+
+func Test_PrepareOutputBuffers_File_CreatesDirectory(t *testing.T) {
+	spoolDir := filepath.Join(t.TempDir(), "spool", "output")
+
+	err := jobmanager.PrepareOutputBuffers(config.OutputBufferFile, spoolDir, 0)
+
+	assert.Nil(t, err)
+	info, err := os.Stat(spoolDir)
+	if assert.Nil(t, err) {
+		assert.True(t, info.IsDir())
+	}
+}
+
+func Test_PrepareOutputBuffers_File_DefaultDirectory(t *testing.T) {
+	assert.Nil(t, jobmanager.PrepareOutputBuffers(config.OutputBufferFile, "", 0))
+}
+
+func Test_PrepareOutputBuffers_File_NotADirectory(t *testing.T) {
+	spoolDir := filepath.Join(t.TempDir(), "file")
+	assert.Nil(t, os.WriteFile(spoolDir, nil, 0600))
+
+	err := jobmanager.PrepareOutputBuffers(config.OutputBufferFile, spoolDir, 0)
+
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "failed to create output spool directory")
+	}
+}
+
+func Test_PrepareOutputBuffers_UnknownType(t *testing.T) {
+	err := jobmanager.PrepareOutputBuffers("tape", "", 0)
+
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument)
+}
+
+func Test_PrepareOutputBuffers_Ring(t *testing.T) {
+	assert.Nil(t, jobmanager.PrepareOutputBuffers(config.OutputBufferRing, "", quantity.Mi))
+}
+
+func Test_PrepareOutputBuffers_Ring_InvalidCapacity(t *testing.T) {
+	for _, capacity := range []quantity.Bytes{0, 4 * quantity.Gi} {
+		err := jobmanager.PrepareOutputBuffers(config.OutputBufferRing, "", capacity)
+
+		assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument, capacity)
+	}
+}
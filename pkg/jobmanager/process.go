@@ -0,0 +1,150 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobmanager
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+)
+
+const (
+	// clockTicksPerSecond is the unit of the CPU times in /proc/<pid>/stat
+	// (USER_HZ), which is 100 on all architectures that Linux supports.
+	clockTicksPerSecond = 100
+
+	// statUtimeField is the index of the utime field of /proc/<pid>/stat
+	// among the fields that follow the command name; stime follows it.
+	statUtimeField = 11
+)
+
+// Process describes a process in a job's cgroups, as reported by procfs.
+type Process struct {
+	// Pid and Ppid are the IDs of the process and of its parent in the
+	// server's PID namespace.
+	Pid  int
+	Ppid int
+
+	// Command is the process's command line.  For processes without one
+	// (e.g., zombies), it is the process's name in brackets.
+	Command []string
+
+	// State is the process's state code (e.g., "R" for running or "S" for
+	// sleeping); see proc(5).
+	State string
+
+	// Rss is the process's resident set size, in bytes.
+	Rss uint64
+
+	// CpuTime is the user and system CPU time that the process has
+	// consumed.
+	CpuTime time.Duration
+}
+
+// ReadProcess reads the description of the process with the given pid from
+// procfs.
+func ReadProcess(osAdapter *os.Adapter, pid int) (*Process, error) {
+	dir := fmt.Sprintf("/proc/%d", pid)
+
+	statusContent, err := osAdapter.ReadFile(dir + "/status")
+	if err != nil {
+		return nil, err
+	}
+	status := parseProcStatus(statusContent)
+
+	statContent, err := osAdapter.ReadFile(dir + "/stat")
+	if err != nil {
+		return nil, err
+	}
+
+	cpuTime, err := parseProcStatCpuTime(statContent)
+	if err != nil {
+		return nil, fmt.Errorf("%s/stat: %w", dir, err)
+	}
+
+	cmdline, err := osAdapter.ReadFile(dir + "/cmdline")
+	if err != nil {
+		return nil, err
+	}
+
+	process := &Process{
+		Pid:     pid,
+		Command: parseCmdline(cmdline),
+		CpuTime: cpuTime,
+	}
+
+	if len(process.Command) == 0 {
+		process.Command = []string{"[" + status["Name"] + "]"}
+	}
+
+	// e.g., "State:	S (sleeping)"
+	if fields := strings.Fields(status["State"]); len(fields) > 0 {
+		process.State = fields[0]
+	}
+
+	if ppid, err := strconv.Atoi(status["PPid"]); err == nil {
+		process.Ppid = ppid
+	}
+
+	// e.g., "VmRSS:	    1234 kB"; absent for kernel threads and zombies
+	if fields := strings.Fields(status["VmRSS"]); len(fields) > 0 {
+		if rss, err := strconv.ParseUint(fields[0], 10, 64); err == nil {
+			process.Rss = rss * 1024
+		}
+	}
+
+	return process, nil
+}
+
+// parseProcStatCpuTime returns the sum of the utime and stime fields of the
+// given content of /proc/<pid>/stat.  The command name, the second field, is
+// in parentheses and may itself contain spaces and parentheses, so the
+// fields are counted from the last closing parenthesis.
+func parseProcStatCpuTime(content []byte) (time.Duration, error) {
+	end := bytes.LastIndexByte(content, ')')
+	if end < 0 {
+		return 0, fmt.Errorf("malformed stat")
+	}
+
+	fields := strings.Fields(string(content[end+1:]))
+	if len(fields) <= statUtimeField+1 {
+		return 0, fmt.Errorf("malformed stat")
+	}
+
+	var ticks uint64
+	for _, field := range fields[statUtimeField : statUtimeField+2] {
+		value, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("malformed stat: %w", err)
+		}
+		ticks += value
+	}
+
+	return time.Duration(ticks) * time.Second / clockTicksPerSecond, nil
+}
+
+// parseCmdline splits the given content of /proc/<pid>/cmdline, in which
+// each argument is terminated by a NUL byte, into arguments.
+func parseCmdline(content []byte) []string {
+	content = bytes.TrimRight(content, "\x00")
+	if len(content) == 0 {
+		return nil
+	}
+
+	return strings.Split(string(content), "\x00")
+}
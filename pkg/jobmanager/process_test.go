@@ -0,0 +1,105 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobmanager_test
+
+import (
+	goos "os"
+	"testing"
+	"time"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os/ostest"
+	"github.com/adalton/teleport-exercise/pkg/jobmanager"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const procStatus = `Name:	sleep
+Umask:	0022
+State:	S (sleeping)
+Tgid:	4321
+Pid:	4321
+PPid:	4300
+VmRSS:	    1024 kB
+Threads:	1
+`
+
+// The command name contains a space and a parenthesis; utime is 250 and
+// stime is 50 clock ticks.
+const procStat = "4321 (my sleep)) S 4300 4321 4300 0 -1 4194304 100 0 0 0 250 50 0 0 20 0 1 0 12345 8192 256\n"
+
+func newProcessAdapter(files map[string]string) *os.Adapter {
+	readFileMock := &ostest.ReadFileMock{Files: files}
+
+	return &os.Adapter{
+		ReadFileFn: readFileMock.ReadFile,
+	}
+}
+
+func Test_ReadProcess(t *testing.T) {
+	adapter := newProcessAdapter(map[string]string{
+		"/proc/4321/status":  procStatus,
+		"/proc/4321/stat":    procStat,
+		"/proc/4321/cmdline": "/bin/sleep\x00600\x00",
+	})
+
+	process, err := jobmanager.ReadProcess(adapter, 4321)
+
+	require.Nil(t, err)
+	assert.Equal(t, 4321, process.Pid)
+	assert.Equal(t, 4300, process.Ppid)
+	assert.Equal(t, []string{"/bin/sleep", "600"}, process.Command)
+	assert.Equal(t, "S", process.State)
+	assert.Equal(t, uint64(1024*1024), process.Rss)
+	assert.Equal(t, 3*time.Second, process.CpuTime)
+}
+
+func Test_ReadProcess_Zombie(t *testing.T) {
+	adapter := newProcessAdapter(map[string]string{
+		"/proc/4321/status":  "Name:	sleep\nState:	Z (zombie)\nPPid:	4300\n",
+		"/proc/4321/stat":    "4321 (sleep) Z 4300 4321 4300 0 -1 4194304 100 0 0 0 7 3 0 0 20 0 1 0 12345 0 0\n",
+		"/proc/4321/cmdline": "",
+	})
+
+	process, err := jobmanager.ReadProcess(adapter, 4321)
+
+	require.Nil(t, err)
+	assert.Equal(t, []string{"[sleep]"}, process.Command)
+	assert.Equal(t, "Z", process.State)
+	assert.Equal(t, uint64(0), process.Rss)
+	assert.Equal(t, 100*time.Millisecond, process.CpuTime)
+}
+
+func Test_ReadProcess_MalformedStat(t *testing.T) {
+	adapter := newProcessAdapter(map[string]string{
+		"/proc/4321/status":  procStatus,
+		"/proc/4321/stat":    "4321 (sleep) S 4300\n",
+		"/proc/4321/cmdline": "/bin/sleep\x00",
+	})
+
+	_, err := jobmanager.ReadProcess(adapter, 4321)
+
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "malformed stat")
+	}
+}
+
+func Test_ReadProcess_Exited(t *testing.T) {
+	adapter := newProcessAdapter(map[string]string{})
+
+	_, err := jobmanager.ReadProcess(adapter, 4321)
+
+	assert.True(t, goos.IsNotExist(err))
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobmanager
+
+import (
+	"fmt"
+
+	"github.com/adalton/teleport-exercise/pkg/quantity"
+)
+
+// ParseSize parses the value of the named size limit (see
+// quantity.ParseBytes).  An empty value is zero.  If the value is malformed,
+// it returns an error that wraps ErrInvalidArgument and names the limit.
+func ParseSize(name, value string) (uint64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	size, err := quantity.ParseBytes(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s: %v", ErrInvalidArgument, name, err)
+	}
+
+	return uint64(size), nil
+}
+
+// ParseByteRate parses the value of the named limit in bytes per second (see
+// quantity.ParseByteRate).  An empty value is zero.  If the value is
+// malformed, it returns an error that wraps ErrInvalidArgument and names the
+// limit.
+func ParseByteRate(name, value string) (uint64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	rate, err := quantity.ParseByteRate(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s: %v", ErrInvalidArgument, name, err)
+	}
+
+	return uint64(rate), nil
+}
+
+// ParseRate parses the value of the named limit in operations per second
+// (see quantity.ParseRate).  An empty value is zero.  If the value is
+// malformed, it returns an error that wraps ErrInvalidArgument and names the
+// limit.
+func ParseRate(name, value string) (uint64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	rate, err := quantity.ParseRate(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s: %v", ErrInvalidArgument, name, err)
+	}
+
+	return rate, nil
+}
+
+// ParseCpus parses the value of the named limit in CPUs (see
+// quantity.ParseCpus).  An empty value is zero.  If the value is malformed,
+// it returns an error that wraps ErrInvalidArgument and names the limit.
+func ParseCpus(name, value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	cpus, err := quantity.ParseCpus(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s: %v", ErrInvalidArgument, name, err)
+	}
+
+	return cpus, nil
+}
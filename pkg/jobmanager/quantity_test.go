@@ -0,0 +1,90 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobmanager_test
+
+import (
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/jobmanager"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseSize_Valid(t *testing.T) {
+	sizes := map[string]uint64{
+		"":       0,
+		"0":      0,
+		"4096":   4096,
+		"4096B":  4096,
+		"8k":     8 << 10,
+		"512M":   512 << 20,
+		"512Mi":  512 << 20,
+		"512MiB": 512 << 20,
+		"2G":     2 << 30,
+		"1T":     1 << 40,
+	}
+
+	for size, expected := range sizes {
+		actual, err := jobmanager.ParseSize("memory limit", size)
+
+		assert.Nil(t, err, size)
+		assert.Equal(t, expected, actual, size)
+	}
+}
+
+func Test_ParseSize_Invalid(t *testing.T) {
+	for _, size := range []string{"M", "-1", "1.5G", "12X", "1I", "1MBB", "16777216T"} {
+		_, err := jobmanager.ParseSize("memory limit", size)
+
+		assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument, size)
+	}
+}
+
+func Test_ParseSize_Message(t *testing.T) {
+	_, err := jobmanager.ParseSize("memoryLimit", "512Q")
+
+	assert.EqualError(t, err,
+		"invalid argument: memoryLimit: '512Q' is not a valid size: unknown unit 'Q'; expected K, M, G or T")
+}
+
+func Test_ParseByteRate(t *testing.T) {
+	rate, err := jobmanager.ParseByteRate("readBps", "40M/s")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(40<<20), rate)
+
+	_, err = jobmanager.ParseByteRate("readBps", "fast")
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument)
+}
+
+func Test_ParseRate(t *testing.T) {
+	rate, err := jobmanager.ParseRate("readIops", "100/s")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(100), rate)
+
+	_, err = jobmanager.ParseRate("readIops", "-5")
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument)
+}
+
+func Test_ParseCpus(t *testing.T) {
+	cpus, err := jobmanager.ParseCpus("cpus", "250m")
+	assert.Nil(t, err)
+	assert.Equal(t, 0.25, cpus)
+
+	cpus, err = jobmanager.ParseCpus("cpus", "")
+	assert.Nil(t, err)
+	assert.Equal(t, 0.0, cpus)
+
+	_, err = jobmanager.ParseCpus("cpus", "-1")
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument)
+}
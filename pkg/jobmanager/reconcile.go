@@ -0,0 +1,132 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/syscall"
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+
+	"github.com/google/uuid"
+)
+
+// ReconcileOrphans kills the processes in, and removes, the cgroups of the
+// jobs that a previous instance of the server left in the hierarchy's
+// JobsParent cgroup (e.g., because it crashed).  It must be called before
+// the server starts any job, since every job cgroup is assumed to be an
+// orphan.  Each orphan that is removed is logged and returned; orphans that
+// cannot be removed are skipped and reported in the returned error.
+func ReconcileOrphans(hierarchy *cgroup.Hierarchy) ([]*cgroup.Orphan, error) {
+	return ReconcileOrphansDetailed(nil, nil, hierarchy)
+}
+
+// ReconcileOrphansDetailed is wrapped by ReconcileOrphans and performs the
+// same operation using the given adapters.
+func ReconcileOrphansDetailed(
+	osAdapter *os.Adapter,
+	syscallAdapter *syscall.Adapter,
+	hierarchy *cgroup.Hierarchy,
+) ([]*cgroup.Orphan, error) {
+	orphans, err := hierarchy.FindOrphans(osAdapter)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		removed []*cgroup.Orphan
+		errs    []error
+	)
+
+	for _, orphan := range orphans {
+		if err := hierarchy.RemoveOrphan(osAdapter, syscallAdapter, orphan); err != nil {
+			errs = append(errs, fmt.Errorf("job %v: %w", orphan.JobID, err))
+			continue
+		}
+
+		log.Printf("Removed orphaned cgroups of job %v (group %q); killed %d processes %v",
+			orphan.JobID, orphan.Group, len(orphan.Pids), orphan.Pids)
+		removed = append(removed, orphan)
+	}
+
+	return removed, errors.Join(errs...)
+}
+
+// FailedDestroys records the cgroups of the terminated jobs that could not be
+// destroyed when the jobs terminated.  The server retries destroying them
+// periodically; see DestroyRetrier.Run.
+var FailedDestroys = NewDestroyRetrier()
+
+// DestroyRetrier maintains a collection of cgroup Sets that could not be
+// destroyed, and retries destroying them.
+type DestroyRetrier struct {
+	mutex sync.Mutex
+	sets  map[uuid.UUID]cgroup.Set // jobID->set
+}
+
+// NewDestroyRetrier creates and returns a new, empty DestroyRetrier.
+func NewDestroyRetrier() *DestroyRetrier {
+	return &DestroyRetrier{
+		sets: make(map[uuid.UUID]cgroup.Set),
+	}
+}
+
+// Add records that the given Set of the job with the given jobID could not
+// be destroyed.
+func (r *DestroyRetrier) Add(jobID uuid.UUID, set cgroup.Set) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.sets[jobID] = set
+}
+
+// Retry tries once to destroy each recorded Set, forgets those that are
+// destroyed, and returns the number that remain.
+func (r *DestroyRetrier) Retry() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for jobID, set := range r.sets {
+		if err := set.Destroy(); err != nil {
+			log.Printf("Retry failed for job %v: %v", jobID, err)
+			continue
+		}
+
+		log.Printf("Destroyed cgroups of job %v on retry", jobID)
+		delete(r.sets, jobID)
+	}
+
+	return len(r.sets)
+}
+
+// Run calls Retry at the given interval until the given context is done.
+func (r *DestroyRetrier) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Retry()
+		}
+	}
+}
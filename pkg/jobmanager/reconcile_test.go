@@ -0,0 +1,124 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobmanager_test
+
+import (
+	"fmt"
+	goio "io"
+	goos "os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os/ostest"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/syscall"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/syscall/syscalltest"
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/jobmanager"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReconcileOrphans(t *testing.T) {
+	base := t.TempDir()
+	jobID := uuid.New()
+	dir := filepath.Join(base, "jobs", "user1", jobID.String())
+	require.Nil(t, goos.MkdirAll(dir, 0755))
+	require.Nil(t, goos.WriteFile(filepath.Join(dir, cgroup.ProcsFilename), []byte("51\n"), 0644))
+
+	hierarchy := &cgroup.Hierarchy{Version: cgroup.V2, BasePath: base, JobsParent: "jobs"}
+	removeMock := &ostest.RemoveMock{}
+	killMock := &syscalltest.KillMock{}
+
+	removed, err := jobmanager.ReconcileOrphansDetailed(
+		&os.Adapter{RemoveFn: removeMock.Remove},
+		&syscall.Adapter{KillFn: killMock.Kill},
+		hierarchy,
+	)
+
+	assert.Nil(t, err)
+	require.Equal(t, 1, len(removed))
+	assert.Equal(t, "user1", removed[0].Group)
+	assert.Equal(t, jobID, removed[0].JobID)
+	assert.Equal(t, []int{51}, removed[0].Pids)
+	require.Equal(t, 1, len(removeMock.Events))
+	assert.Equal(t, dir, removeMock.Events[0].Path)
+}
+
+func Test_ReconcileOrphans_KillFails(t *testing.T) {
+	base := t.TempDir()
+	jobID := uuid.New()
+	dir := filepath.Join(base, "jobs", jobID.String())
+	require.Nil(t, goos.MkdirAll(dir, 0755))
+	require.Nil(t, goos.WriteFile(filepath.Join(dir, cgroup.ProcsFilename), []byte("52\n"), 0644))
+
+	hierarchy := &cgroup.Hierarchy{Version: cgroup.V2, BasePath: base, JobsParent: "jobs"}
+	removeMock := &ostest.RemoveMock{}
+	killMock := &syscalltest.KillMock{NextError: fmt.Errorf("operation not permitted")}
+
+	removed, err := jobmanager.ReconcileOrphansDetailed(
+		&os.Adapter{RemoveFn: removeMock.Remove},
+		&syscall.Adapter{KillFn: killMock.Kill},
+		hierarchy,
+	)
+
+	assert.Nil(t, removed)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), jobID.String())
+	}
+	assert.Equal(t, 0, len(removeMock.Events))
+}
+
+// destroySetMock is a cgroup.Set whose Destroy fails until it has been called
+// failures+1 times.
+type destroySetMock struct {
+	failures int
+	calls    int
+}
+
+func (s *destroySetMock) Create() error                           { return nil }
+func (s *destroySetMock) TaskFiles() []string                     { return nil }
+func (s *destroySetMock) ReadFile(string, string) ([]byte, error) { return nil, nil }
+func (s *destroySetMock) WatchOom(func()) (goio.Closer, error)    { return nil, nil }
+func (s *destroySetMock) Freeze() error                           { return nil }
+func (s *destroySetMock) Thaw() error                             { return nil }
+func (s *destroySetMock) Frozen() (bool, error)                   { return true, nil }
+func (s *destroySetMock) Pids() ([]int, error)                    { return nil, nil }
+
+func (s *destroySetMock) Destroy() error {
+	s.calls++
+	if s.calls <= s.failures {
+		return fmt.Errorf("device or resource busy")
+	}
+
+	return nil
+}
+
+func Test_DestroyRetrier_Retry(t *testing.T) {
+	retrier := jobmanager.NewDestroyRetrier()
+	once := &destroySetMock{failures: 0}
+	twice := &destroySetMock{failures: 1}
+
+	retrier.Add(uuid.New(), once)
+	retrier.Add(uuid.New(), twice)
+
+	assert.Equal(t, 1, retrier.Retry())
+	assert.Equal(t, 0, retrier.Retry())
+	assert.Equal(t, 0, retrier.Retry())
+
+	assert.Equal(t, 1, once.calls)
+	assert.Equal(t, 2, twice.calls)
+}
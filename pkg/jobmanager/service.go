@@ -0,0 +1,182 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobmanager
+
+import (
+	"fmt"
+	goos "os"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2"
+	"github.com/adalton/teleport-exercise/pkg/config"
+	"github.com/adalton/teleport-exercise/pkg/quantity"
+)
+
+const (
+	ProcMeminfoPath = "/proc/meminfo"
+)
+
+// ReserveServiceResources moves the server into its own cgroup
+// (config.CgroupServiceCgroup) and limits the cgroup under which jobs are
+// created (the hierarchy's JobsParent) so that config.CgroupReservedCpus and
+// config.CgroupReservedMemory remain available to the server however heavily
+// the jobs use the host.
+func ReserveServiceResources(hierarchy *cgroup.Hierarchy) error {
+	return ReserveServiceResourcesDetailed(nil, hierarchy, goos.Getpid(), runtime.NumCPU())
+}
+
+// ReserveServiceResourcesDetailed is wrapped by ReserveServiceResources and
+// performs the same operation for the process with the given pid on a host
+// with the given number of CPUs, reading the host's memory size using the
+// given osAdapter.
+func ReserveServiceResourcesDetailed(
+	osAdapter *os.Adapter,
+	hierarchy *cgroup.Hierarchy,
+	pid int,
+	numCpus int,
+) error {
+	serviceCgroup := path.Clean("/" + config.CgroupServiceCgroup)
+	jobsParent := path.Clean("/" + hierarchy.JobsParent)
+
+	if jobsParent == "/" {
+		return fmt.Errorf("the jobs cgroup must not be the root of the hierarchy")
+	}
+
+	if config.CgroupServiceCgroup != "" {
+		if isWithin(serviceCgroup, jobsParent) || isWithin(jobsParent, serviceCgroup) {
+			return fmt.Errorf("the service cgroup %s and the jobs cgroup %s must not be nested",
+				serviceCgroup, jobsParent)
+		}
+
+		// The server moves itself first: in the unified hierarchy, the
+		// controllers cannot be enabled for the jobs cgroup while the server
+		// is in one of the jobs cgroup's ancestors (other than the root).
+		service := hierarchy.NewGroupAt(serviceCgroup, availableControllers(hierarchy,
+			serviceControllers(hierarchy.Version, config.CgroupReservedMemory))...)
+
+		if err := service.Create(); err != nil {
+			return fmt.Errorf("failed to create service cgroup %s: %w", serviceCgroup, err)
+		}
+
+		if err := service.AddProcess(pid); err != nil {
+			return fmt.Errorf("failed to join service cgroup %s: %w", serviceCgroup, err)
+		}
+	}
+
+	totalMemory, err := readTotalMemory(osAdapter)
+	if err != nil {
+		return err
+	}
+
+	cpus := float64(numCpus) - config.CgroupReservedCpus
+	if config.CgroupReservedCpus <= 0 || cpus <= 0 {
+		cpus = 0
+	}
+
+	var memory quantity.Bytes
+	if config.CgroupReservedMemory > 0 && config.CgroupReservedMemory < totalMemory {
+		memory = totalMemory - config.CgroupReservedMemory
+	}
+
+	jobs := hierarchy.NewGroupAt(jobsParent, availableControllers(hierarchy,
+		jobsParentControllers(hierarchy.Version, cpus, memory))...)
+
+	if err := jobs.Create(); err != nil {
+		return fmt.Errorf("failed to create jobs cgroup %s: %w", jobsParent, err)
+	}
+
+	return nil
+}
+
+// serviceControllers returns the cgroup controllers for the server's own
+// cgroup, suitable for the given cgroup version, that protect the given
+// amount of the server's memory from reclaim.  Cgroup v1 offers no such
+// protection; there, the server's headroom comes from the limits on the jobs
+// cgroup alone.
+func serviceControllers(version cgroup.Version, reservedMemory quantity.Bytes) []cgroup.Controller {
+	if version == cgroup.V2 {
+		return []cgroup.Controller{
+			&cgroupv2.CpuController{},
+			&cgroupv2.MemoryController{Low: formatMemorySize(reservedMemory)},
+		}
+	}
+
+	return []cgroup.Controller{
+		&cgroupv1.CpuController{},
+		&cgroupv1.MemoryController{},
+	}
+}
+
+// jobsParentControllers returns the cgroup controllers, suitable for the
+// given cgroup version, that limit all of the jobs combined to the given
+// number of CPUs and amount of memory.  Zero limits leave the resource
+// unlimited.
+func jobsParentControllers(version cgroup.Version, cpus float64, memory quantity.Bytes) []cgroup.Controller {
+	if version == cgroup.V2 {
+		return []cgroup.Controller{
+			&cgroupv2.CpuController{Cpus: cpus, PeriodUs: config.CgroupDefaultCpuPeriodUs},
+			&cgroupv2.MemoryController{Limit: formatMemorySize(memory)},
+		}
+	}
+
+	return []cgroup.Controller{
+		&cgroupv1.CpuController{Cpus: cpus, PeriodUs: config.CgroupDefaultCpuPeriodUs},
+		&cgroupv1.MemoryController{Limit: formatMemorySize(memory)},
+	}
+}
+
+// availableControllers returns those of the given controllers that are
+// available in the given hierarchy.
+func availableControllers(hierarchy *cgroup.Hierarchy, controllers []cgroup.Controller) []cgroup.Controller {
+	var available []cgroup.Controller
+
+	for _, controller := range controllers {
+		if hierarchy.HasController(controller.Name()) {
+			available = append(available, controller)
+		}
+	}
+
+	return available
+}
+
+// readTotalMemory returns the amount of physical memory on the host, as
+// reported by /proc/meminfo.
+func readTotalMemory(osAdapter *os.Adapter) (quantity.Bytes, error) {
+	content, err := osAdapter.ReadFile(ProcMeminfoPath)
+	if err != nil {
+		return 0, err
+	}
+
+	// MemTotal has the form "<n> kB"
+	value := strings.TrimSuffix(parseProcStatus(content)["MemTotal"], " kB")
+
+	kb, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse MemTotal in %s: '%s'", ProcMeminfoPath, value)
+	}
+
+	return quantity.Bytes(kb) * quantity.Ki, nil
+}
+
+// isWithin returns true if the cgroup at path is dir or one of its
+// descendants.  Both must be clean, absolute paths.
+func isWithin(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, "/")+"/")
+}
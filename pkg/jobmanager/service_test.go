@@ -0,0 +1,115 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobmanager_test
+
+import (
+	goos "os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os"
+	"github.com/adalton/teleport-exercise/pkg/adaptation/os/ostest"
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv2"
+	"github.com/adalton/teleport-exercise/pkg/config"
+	"github.com/adalton/teleport-exercise/pkg/jobmanager"
+	"github.com/adalton/teleport-exercise/pkg/quantity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const servicePid = 1234
+
+// newMeminfoAdapter returns an adapter for a host with 1GiB of memory.
+func newMeminfoAdapter() *os.Adapter {
+	readFileMock := &ostest.ReadFileMock{
+		Files: map[string]string{
+			jobmanager.ProcMeminfoPath: "MemTotal:        1048576 kB\nMemFree:          524288 kB\n",
+		},
+	}
+
+	return &os.Adapter{ReadFileFn: readFileMock.ReadFile}
+}
+
+// readCgroupFile returns the content of the file at the given path relative
+// to basePath.
+func readCgroupFile(t *testing.T, basePath, name string) string {
+	content, err := goos.ReadFile(filepath.Join(basePath, name))
+	require.Nil(t, err, name)
+
+	return string(content)
+}
+
+func Test_ReserveServiceResources_V2(t *testing.T) {
+	hierarchy := &cgroup.Hierarchy{
+		Version:     cgroup.V2,
+		BasePath:    t.TempDir(),
+		Controllers: []string{"cpu", "memory", "pids"},
+		JobsParent:  "jobs",
+	}
+
+	err := jobmanager.ReserveServiceResourcesDetailed(newMeminfoAdapter(), hierarchy, servicePid, 4)
+
+	require.Nil(t, err)
+	assert.Equal(t, strconv.Itoa(servicePid),
+		readCgroupFile(t, hierarchy.BasePath, "jobmanager/"+cgroupv2.ProcsFilename))
+	assert.Equal(t, strconv.FormatUint(uint64(config.CgroupReservedMemory), 10),
+		readCgroupFile(t, hierarchy.BasePath, "jobmanager/"+cgroupv2.MemoryLowFilename))
+	assert.Equal(t, "350000 100000",
+		readCgroupFile(t, hierarchy.BasePath, "jobs/"+cgroupv2.CpuMaxFilename))
+	assert.Equal(t, strconv.FormatUint(uint64(quantity.Gi-config.CgroupReservedMemory), 10),
+		readCgroupFile(t, hierarchy.BasePath, "jobs/"+cgroupv2.MemoryMaxFilename))
+}
+
+func Test_ReserveServiceResources_V1(t *testing.T) {
+	hierarchy := &cgroup.Hierarchy{
+		Version:     cgroup.V1,
+		BasePath:    t.TempDir(),
+		Controllers: []string{"cpu", "memory"},
+		JobsParent:  "system.slice/jobmanager.service/jobs",
+	}
+
+	err := jobmanager.ReserveServiceResourcesDetailed(newMeminfoAdapter(), hierarchy, servicePid, 2)
+
+	require.Nil(t, err)
+	assert.Equal(t, strconv.Itoa(servicePid),
+		readCgroupFile(t, hierarchy.BasePath, "cpu/jobmanager/"+cgroupv1.ProcsFilename))
+	assert.Equal(t, strconv.Itoa(servicePid),
+		readCgroupFile(t, hierarchy.BasePath, "memory/jobmanager/"+cgroupv1.ProcsFilename))
+	assert.Equal(t, "150000",
+		readCgroupFile(t, hierarchy.BasePath, "cpu/system.slice/jobmanager.service/jobs/"+cgroupv1.CpuQuotaFilename))
+	assert.Equal(t, strconv.FormatUint(uint64(quantity.Gi-config.CgroupReservedMemory), 10),
+		readCgroupFile(t, hierarchy.BasePath,
+			"memory/system.slice/jobmanager.service/jobs/"+cgroupv1.MemoryLimitInBytesFilename))
+}
+
+func Test_ReserveServiceResources_Nested(t *testing.T) {
+	saved := config.CgroupServiceCgroup
+	config.CgroupServiceCgroup = "jobs/server"
+	defer func() { config.CgroupServiceCgroup = saved }()
+
+	hierarchy := &cgroup.Hierarchy{
+		Version:     cgroup.V2,
+		BasePath:    t.TempDir(),
+		Controllers: []string{"cpu", "memory"},
+		JobsParent:  "jobs",
+	}
+
+	err := jobmanager.ReserveServiceResourcesDetailed(newMeminfoAdapter(), hierarchy, servicePid, 4)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quantity
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Bytes is a size in bytes, or a rate in bytes per second.
+type Bytes uint64
+
+// Binary multiples of Bytes.  As in the kernel, the K, M, G and T suffixes
+// that ParseBytes accepts are also binary multiples.
+const (
+	Ki Bytes = 1 << 10
+	Mi Bytes = 1 << 20
+	Gi Bytes = 1 << 30
+	Ti Bytes = 1 << 40
+)
+
+// byteUnits maps the normalized suffixes that ParseBytes accepts to their
+// multipliers.
+var byteUnits = map[string]Bytes{
+	"":  1,
+	"K": Ki,
+	"M": Mi,
+	"G": Gi,
+	"T": Ti,
+}
+
+// String returns b in the largest binary unit that represents it exactly
+// (e.g., "512Mi").  ParseBytes accepts the result.
+func (b Bytes) String() string {
+	for _, unit := range []struct {
+		suffix string
+		size   Bytes
+	}{{"Ti", Ti}, {"Gi", Gi}, {"Mi", Mi}, {"Ki", Ki}} {
+		if b != 0 && b%unit.size == 0 {
+			return strconv.FormatUint(uint64(b/unit.size), 10) + unit.suffix
+		}
+	}
+
+	return strconv.FormatUint(uint64(b), 10)
+}
+
+// ParseBytes parses a size in bytes with an optional K, M, G or T suffix
+// (e.g., "512M").  The suffixes are binary multiples, may be followed by "i"
+// and/or "B", and are not case-sensitive, so "512M", "512m", "512Mi" and
+// "512MiB" are equivalent.
+func ParseBytes(s string) (Bytes, error) {
+	return parseBytes("size", s)
+}
+
+// ParseByteRate parses a rate in bytes per second.  It accepts the same
+// forms as ParseBytes, optionally followed by "/s" (e.g., "40M/s").
+func ParseByteRate(s string) (Bytes, error) {
+	return parseBytes("rate", strings.TrimSuffix(s, "/s"))
+}
+
+// ParseRate parses a rate in operations per second: a whole number,
+// optionally followed by "/s" (e.g., "100/s").
+func ParseRate(s string) (uint64, error) {
+	value, err := strconv.ParseUint(strings.TrimSuffix(s, "/s"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not a valid rate; expected a whole number per second", s)
+	}
+
+	return value, nil
+}
+
+// ParseCpus parses an amount of CPU time in CPUs, either as a decimal number
+// (e.g., "1.5") or as a whole number of millicpus with an "m" suffix (e.g.,
+// "1500m").
+func ParseCpus(s string) (float64, error) {
+	if millis := strings.TrimSuffix(s, "m"); millis != s {
+		value, err := strconv.ParseUint(millis, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("'%s' is not a valid CPU amount; expected a whole number of millicpus", s)
+		}
+
+		return float64(value) / 1000, nil
+	}
+
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("'%s' is not a valid CPU amount; expected a number of CPUs (e.g., 1.5) "+
+			"or of millicpus (e.g., 1500m)", s)
+	}
+
+	if value < 0 {
+		return 0, fmt.Errorf("'%s' is not a valid CPU amount; it must not be negative", s)
+	}
+
+	return value, nil
+}
+
+// parseBytes implements ParseBytes.  The kind names the quantity in errors.
+func parseBytes(kind, s string) (Bytes, error) {
+	unit := strings.TrimLeft(s, "0123456789")
+	digits := s[:len(s)-len(unit)]
+
+	if digits == "" {
+		return 0, fmt.Errorf("'%s' is not a valid %s; expected a whole number of bytes "+
+			"with an optional K, M, G or T suffix", s, kind)
+	}
+
+	if strings.HasPrefix(unit, ".") {
+		return 0, fmt.Errorf("'%s' is not a valid %s: fractions are not supported; "+
+			"use a smaller unit (e.g., 1536M rather than 1.5G)", s, kind)
+	}
+
+	normalized := strings.TrimSuffix(strings.ToUpper(unit), "B")
+	if len(normalized) == 2 {
+		normalized = strings.TrimSuffix(normalized, "I")
+	}
+
+	multiplier, ok := byteUnits[normalized]
+	if !ok {
+		return 0, fmt.Errorf("'%s' is not a valid %s: unknown unit '%s'; expected K, M, G or T",
+			s, kind, unit)
+	}
+
+	value, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil || Bytes(value) > math.MaxUint64/multiplier {
+		return 0, fmt.Errorf("'%s' is not a valid %s: it is too large", s, kind)
+	}
+
+	return Bytes(value) * multiplier, nil
+}
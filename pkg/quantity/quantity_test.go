@@ -0,0 +1,132 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quantity_test
+
+import (
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/quantity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseBytes_Valid(t *testing.T) {
+	sizes := map[string]quantity.Bytes{
+		"0":      0,
+		"4096":   4096,
+		"4096B":  4096,
+		"8k":     8 * quantity.Ki,
+		"8Ki":    8 * quantity.Ki,
+		"512M":   512 * quantity.Mi,
+		"512Mi":  512 * quantity.Mi,
+		"512MiB": 512 * quantity.Mi,
+		"2G":     2 * quantity.Gi,
+		"2gi":    2 * quantity.Gi,
+		"1T":     quantity.Ti,
+	}
+
+	for size, expected := range sizes {
+		actual, err := quantity.ParseBytes(size)
+
+		assert.Nil(t, err, size)
+		assert.Equal(t, expected, actual, size)
+	}
+}
+
+func Test_ParseBytes_Invalid(t *testing.T) {
+	errors := map[string]string{
+		"":          "'' is not a valid size; expected a whole number of bytes with an optional K, M, G or T suffix",
+		"M":         "'M' is not a valid size; expected a whole number of bytes with an optional K, M, G or T suffix",
+		"-1":        "'-1' is not a valid size; expected a whole number of bytes with an optional K, M, G or T suffix",
+		"1.5G":      "'1.5G' is not a valid size: fractions are not supported; use a smaller unit (e.g., 1536M rather than 1.5G)",
+		"12X":       "'12X' is not a valid size: unknown unit 'X'; expected K, M, G or T",
+		"1I":        "'1I' is not a valid size: unknown unit 'I'; expected K, M, G or T",
+		"1MBB":      "'1MBB' is not a valid size: unknown unit 'MBB'; expected K, M, G or T",
+		"16777216T": "'16777216T' is not a valid size: it is too large",
+	}
+
+	for size, expected := range errors {
+		_, err := quantity.ParseBytes(size)
+
+		assert.EqualError(t, err, expected, size)
+	}
+}
+
+func Test_Bytes_String(t *testing.T) {
+	sizes := map[quantity.Bytes]string{
+		0:                  "0",
+		1000:               "1000",
+		4096:               "4Ki",
+		512 * quantity.Mi:  "512Mi",
+		1536 * quantity.Mi: "1536Mi",
+		2 * quantity.Ti:    "2Ti",
+	}
+
+	for size, expected := range sizes {
+		assert.Equal(t, expected, size.String())
+
+		parsed, err := quantity.ParseBytes(size.String())
+		assert.Nil(t, err)
+		assert.Equal(t, size, parsed)
+	}
+}
+
+func Test_ParseByteRate(t *testing.T) {
+	rate, err := quantity.ParseByteRate("40M/s")
+	assert.Nil(t, err)
+	assert.Equal(t, 40*quantity.Mi, rate)
+
+	rate, err = quantity.ParseByteRate("1024")
+	assert.Nil(t, err)
+	assert.Equal(t, quantity.Bytes(1024), rate)
+
+	_, err = quantity.ParseByteRate("40M/m")
+	assert.EqualError(t, err, "'40M/m' is not a valid rate: unknown unit 'M/m'; expected K, M, G or T")
+}
+
+func Test_ParseRate(t *testing.T) {
+	rate, err := quantity.ParseRate("100/s")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(100), rate)
+
+	rate, err = quantity.ParseRate("50")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(50), rate)
+
+	_, err = quantity.ParseRate("1.5")
+	assert.EqualError(t, err, "'1.5' is not a valid rate; expected a whole number per second")
+}
+
+func Test_ParseCpus(t *testing.T) {
+	cpus := map[string]float64{
+		"1":     1,
+		"1.5":   1.5,
+		"0.25":  0.25,
+		"1500m": 1.5,
+		"250m":  0.25,
+	}
+
+	for s, expected := range cpus {
+		actual, err := quantity.ParseCpus(s)
+
+		assert.Nil(t, err, s)
+		assert.Equal(t, expected, actual, s)
+	}
+
+	for _, s := range []string{"", "m", "-1", "-1m", "1.5m", "NaN", "Inf", "one"} {
+		_, err := quantity.ParseCpus(s)
+
+		assert.Error(t, err, s)
+	}
+}
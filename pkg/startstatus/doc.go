@@ -0,0 +1,19 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package startstatus implements the status pipe over which cgexec reports
+// to the server whether it managed to start a job's command.  cgexec writes
+// nothing to the pipe when it starts the command; the pipe is closed on exec,
+// so the server reads end-of-file.  Otherwise, cgexec writes an Error that
+// describes why it failed.
+package startstatus
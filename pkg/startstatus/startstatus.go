@@ -0,0 +1,114 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package startstatus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"syscall"
+)
+
+// Error describes why cgexec failed to start a command.
+type Error struct {
+	// Op is the operation that failed (e.g., "join cgroup" or "exec").
+	Op string `json:"op"`
+
+	// Path is the file or program involved in the operation, if any.
+	Path string `json:"path,omitempty"`
+
+	// Errno is the error number that the failed system call returned, or
+	// zero if the failure was not a system call error.
+	Errno syscall.Errno `json:"errno,omitempty"`
+
+	// Message describes the failure.
+	Message string `json:"message"`
+
+	// err is the original error; it is not reported over the status pipe.
+	err error
+}
+
+// NewError creates and returns a new Error that describes the failure of
+// the given operation on the given path with the given error.
+func NewError(op, path string, err error) *Error {
+	e := &Error{
+		Op:      op,
+		Path:    path,
+		Message: err.Error(),
+		err:     err,
+	}
+
+	// A PathError's message repeats the path
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		e.Message = pathErr.Err.Error()
+	}
+
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		e.Errno = errno
+	}
+
+	return e
+}
+
+func (e *Error) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("cgexec: failed to %s: %s", e.Op, e.Message)
+	}
+
+	return fmt.Sprintf("cgexec: failed to %s %s: %s", e.Op, e.Path, e.Message)
+}
+
+// Unwrap returns the original error, or the error number for an Error that
+// was read from a status pipe.
+func (e *Error) Unwrap() error {
+	if e.err != nil {
+		return e.err
+	}
+
+	if e.Errno != 0 {
+		return e.Errno
+	}
+
+	return nil
+}
+
+// Write writes the given error to the given status pipe.
+func Write(w io.Writer, err *Error) error {
+	return json.NewEncoder(w).Encode(err)
+}
+
+// Read reads the given status pipe until end-of-file.  It returns nil if
+// nothing was written to the pipe, which indicates that the command started.
+// Otherwise, it returns the *Error that was written.
+func Read(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read start status: %w", err)
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	statusErr := &Error{}
+	if err := json.Unmarshal(data, statusErr); err != nil {
+		return fmt.Errorf("malformed start status '%s': %w", string(data), err)
+	}
+
+	return statusErr
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package startstatus_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/startstatus"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewError_Errno(t *testing.T) {
+	err := startstatus.NewError("exec", "/bin/missing", syscall.ENOENT)
+
+	assert.Equal(t, "exec", err.Op)
+	assert.Equal(t, "/bin/missing", err.Path)
+	assert.Equal(t, syscall.ENOENT, err.Errno)
+	assert.Equal(t, "cgexec: failed to exec /bin/missing: no such file or directory", err.Error())
+	assert.ErrorIs(t, err, syscall.ENOENT)
+}
+
+func Test_NewError_PathError(t *testing.T) {
+	pathErr := &fs.PathError{Op: "open", Path: "/sys/fs/cgroup/tasks", Err: syscall.EACCES}
+
+	err := startstatus.NewError("join cgroup", pathErr.Path, pathErr)
+
+	assert.Equal(t, syscall.EACCES, err.Errno)
+	assert.Equal(t, "cgexec: failed to join cgroup /sys/fs/cgroup/tasks: permission denied", err.Error())
+	assert.ErrorIs(t, err, pathErr)
+}
+
+func Test_NewError_NoPath(t *testing.T) {
+	injected := errors.New("injected error")
+
+	err := startstatus.NewError("parse arguments", "", injected)
+
+	assert.Equal(t, syscall.Errno(0), err.Errno)
+	assert.Equal(t, "cgexec: failed to parse arguments: injected error", err.Error())
+	assert.ErrorIs(t, err, injected)
+}
+
+func Test_WriteRead(t *testing.T) {
+	var pipe bytes.Buffer
+
+	require.Nil(t, startstatus.Write(&pipe, startstatus.NewError("exec", "/bin/missing", syscall.ENOENT)))
+
+	err := startstatus.Read(&pipe)
+
+	var statusErr *startstatus.Error
+	if assert.ErrorAs(t, err, &statusErr) {
+		assert.Equal(t, "exec", statusErr.Op)
+		assert.Equal(t, "/bin/missing", statusErr.Path)
+		assert.Equal(t, syscall.ENOENT, statusErr.Errno)
+		assert.Equal(t, "no such file or directory", statusErr.Message)
+	}
+	assert.ErrorIs(t, err, syscall.ENOENT)
+}
+
+func Test_Read_Started(t *testing.T) {
+	assert.Nil(t, startstatus.Read(strings.NewReader("")))
+}
+
+func Test_Read_Malformed(t *testing.T) {
+	err := startstatus.Read(strings.NewReader("cgexec failed: oops"))
+
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "malformed start status")
+	}
+
+	var statusErr *startstatus.Error
+	assert.False(t, errors.As(err, &statusErr))
+}
+
+func Test_Read_Failure(t *testing.T) {
+	injected := fmt.Errorf("injected error")
+
+	err := startstatus.Read(&failingReader{err: injected})
+
+	assert.ErrorIs(t, err, injected)
+}
+
+type failingReader struct {
+	err error
+}
+
+func (r *failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
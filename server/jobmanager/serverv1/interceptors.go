@@ -141,6 +141,12 @@ func errorToGRPCErrorCode(err error) codes.Code {
 		code = codes.InvalidArgument
 	} else if errors.Is(err, jobmanager.ErrUnauthenticated) {
 		code = codes.Unauthenticated
+	} else if errors.Is(err, jobmanager.ErrJobNotRunning) {
+		code = codes.FailedPrecondition
+	} else if errors.Is(err, jobmanager.ErrFailedToStart) {
+		code = codes.FailedPrecondition
+	} else if errors.Is(err, jobmanager.ErrInvalidJobState) {
+		code = codes.FailedPrecondition
 	} else if errors.Is(err, context.DeadlineExceeded) {
 		code = codes.DeadlineExceeded
 	}
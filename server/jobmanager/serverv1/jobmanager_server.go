@@ -15,9 +15,12 @@ package serverv1
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/adalton/teleport-exercise/pkg/config"
 	"github.com/adalton/teleport-exercise/pkg/io"
 	"github.com/adalton/teleport-exercise/pkg/jobmanager"
+	"github.com/adalton/teleport-exercise/pkg/quantity"
 	"github.com/adalton/teleport-exercise/service/jobmanager/jobmanagerv1"
 )
 
@@ -27,11 +30,6 @@ type jobmanagerServer struct {
 	jm *jobmanager.Manager
 }
 
-// NewJobmanagerServer creates and returns a jobmanagerServer.
-func NewJobmanagerServer() *jobmanagerServer {
-	return NewJobManagerServerDetailed(jobmanager.NewManager())
-}
-
 // NewJobManagerServerDetailed creates a new jobmanagerServer with a custom
 // underlying jobmanager.Manager.
 func NewJobManagerServerDetailed(manager *jobmanager.Manager) *jobmanagerServer {
@@ -50,7 +48,12 @@ func (s *jobmanagerServer) Start(
 		return nil, err
 	}
 
-	job, err := s.jm.Start(userID, jcr.GetName(), jcr.GetProgramPath(), jcr.GetArguments())
+	limits, err := externalToInternalLimitsV1(jcr.GetLimits())
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.jm.Start(userID, jcr.GetName(), jcr.GetProgramPath(), jcr.GetArguments(), limits)
 	if err != nil {
 		return nil, err
 	}
@@ -63,6 +66,52 @@ func (s *jobmanagerServer) Start(
 	return jobResponse, nil
 }
 
+func (s *jobmanagerServer) Create(
+	ctx context.Context,
+	jcr *jobmanagerv1.JobCreationRequest,
+) (*jobmanagerv1.Job, error) {
+
+	userID, err := GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limits, err := externalToInternalLimitsV1(jcr.GetLimits())
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.jm.Create(userID, jcr.GetName(), jcr.GetProgramPath(), jcr.GetArguments(), limits)
+	if err != nil {
+		return nil, err
+	}
+
+	jobResponse := &jobmanagerv1.Job{
+		Id:   &jobmanagerv1.JobID{Id: job.ID().String()},
+		Name: job.Name(),
+	}
+
+	return jobResponse, nil
+}
+
+func (s *jobmanagerServer) Launch(
+	ctx context.Context,
+	requestJobID *jobmanagerv1.JobID,
+) (*jobmanagerv1.NilMessage, error) {
+
+	userID, err := GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.jm.Launch(userID, requestJobID.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jobmanagerv1.NilMessage{}, nil
+}
+
 func (s *jobmanagerServer) Stop(
 	ctx context.Context,
 	requestJobID *jobmanagerv1.JobID,
@@ -81,6 +130,110 @@ func (s *jobmanagerServer) Stop(
 	return &jobmanagerv1.NilMessage{}, nil
 }
 
+// externalToInternalLimitsV1 converts the given limits from a job creation
+// request into a jobmanager.JobLimits.  The paths in the block IO limits are
+// resolved to the block devices to which they apply; paths that do not
+// resolve to a known block device are rejected, as are CPUs and memory nodes
+// that are not available to the server.
+func externalToInternalLimitsV1(externalLimits *jobmanagerv1.ResourceLimits) (*jobmanager.JobLimits, error) {
+	if externalLimits == nil {
+		return nil, nil
+	}
+
+	blkioLimits := make([]config.BlkioLimit, 0, len(externalLimits.GetBlockIO()))
+	for i, limit := range externalLimits.GetBlockIO() {
+		readBps, err := jobmanager.ParseByteRate(fmt.Sprintf("blockIO[%d].readBps", i), limit.GetReadBps())
+		if err != nil {
+			return nil, err
+		}
+
+		writeBps, err := jobmanager.ParseByteRate(fmt.Sprintf("blockIO[%d].writeBps", i), limit.GetWriteBps())
+		if err != nil {
+			return nil, err
+		}
+
+		blkioLimits = append(blkioLimits, config.BlkioLimit{
+			Path:      limit.GetPath(),
+			ReadBps:   quantity.Bytes(readBps),
+			WriteBps:  quantity.Bytes(writeBps),
+			ReadIops:  limit.GetReadIops(),
+			WriteIops: limit.GetWriteIops(),
+		})
+	}
+
+	blockIO, err := jobmanager.ResolveBlockIOLimits(blkioLimits)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := jobmanager.ValidateCpuset(externalLimits.GetCpusetCpus(), externalLimits.GetCpusetMems()); err != nil {
+		return nil, err
+	}
+
+	memoryLimit, err := jobmanager.ParseSize("memoryLimit", externalLimits.GetMemoryLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	memorySwapLimit, err := jobmanager.ParseSize("memorySwapLimit", externalLimits.GetMemorySwapLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	memorySoftLimit, err := jobmanager.ParseSize("memorySoftLimit", externalLimits.GetMemorySoftLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	cgroupKnobs := make([]config.CgroupKnob, 0, len(externalLimits.GetKnobs()))
+	for _, knob := range externalLimits.GetKnobs() {
+		cgroupKnobs = append(cgroupKnobs, config.CgroupKnob{
+			File:  knob.GetFile(),
+			Value: knob.GetValue(),
+		})
+	}
+
+	knobs, err := jobmanager.ResolveKnobs(cgroupKnobs)
+	if err != nil {
+		return nil, err
+	}
+
+	var oomPolicy jobmanager.OomPolicy
+
+	switch externalLimits.GetOomPolicy() {
+	case jobmanagerv1.OomPolicy_OomPolicy_DEFAULT:
+		oomPolicy = jobmanager.OomPolicyDefault
+
+	case jobmanagerv1.OomPolicy_OomPolicy_KILL:
+		oomPolicy = jobmanager.OomPolicyKill
+
+	case jobmanagerv1.OomPolicy_OomPolicy_PAUSE:
+		oomPolicy = jobmanager.OomPolicyPause
+
+	default:
+		return nil, fmt.Errorf("%w: unknown OOM policy: %v", jobmanager.ErrInvalidArgument,
+			externalLimits.GetOomPolicy())
+	}
+
+	return &jobmanager.JobLimits{
+		BlockIO:       blockIO,
+		MaxPids:       externalLimits.GetMaxPids(),
+		CpusetCpus:    externalLimits.GetCpusetCpus(),
+		CpusetMems:    externalLimits.GetCpusetMems(),
+		Cpus:          externalLimits.GetCpus(),
+		UnlimitedCpus: externalLimits.GetUnlimitedCpus(),
+		CpuWeight:     externalLimits.GetCpuWeight(),
+		CpuPeriodUs:   externalLimits.GetCpuPeriodUs(),
+
+		MemoryLimit:     memoryLimit,
+		MemorySwapLimit: memorySwapLimit,
+		MemorySoftLimit: memorySoftLimit,
+		OomPolicy:       oomPolicy,
+
+		Knobs: knobs,
+	}, nil
+}
+
 func internalToExternalStatusV1(internalStatus *jobmanager.JobStatus) *jobmanagerv1.JobStatus {
 	errMsg := ""
 
@@ -95,13 +248,56 @@ func internalToExternalStatusV1(internalStatus *jobmanager.JobStatus) *jobmanage
 			},
 			Name: internalStatus.Name,
 		},
-		Owner:        internalStatus.Owner,
-		IsRunning:    internalStatus.Running,
-		Pid:          int32(internalStatus.Pid),
-		ExitCode:     int32(internalStatus.ExitCode),
-		SignalNumber: int32(internalStatus.SignalNum),
-		ErrorMessage: errMsg,
+		Owner:            internalStatus.Owner,
+		IsRunning:        internalStatus.Running,
+		Pid:              int32(internalStatus.Pid),
+		ExitCode:         int32(internalStatus.ExitCode),
+		SignalNumber:     int32(internalStatus.SignalNum),
+		ErrorMessage:     errMsg,
+		PidsLimitReached: internalStatus.PidsLimitReached,
+
+		// The TerminationReason enumerations have matching values
+		TerminationReason: jobmanagerv1.TerminationReason(internalStatus.TerminationReason),
+		OomKills:          internalStatus.OomKills,
+		PeakMemoryUsage:   internalStatus.PeakMemoryUsage,
+		Usage:             internalToExternalUsageV1(internalStatus.Usage),
+
+		// The JobState enumerations have matching values
+		State: jobmanagerv1.JobState(internalStatus.State),
+	}
+}
+
+func internalToExternalUsageV1(internalUsage *jobmanager.ResourceUsage) *jobmanagerv1.ResourceUsage {
+	if internalUsage == nil {
+		return nil
+	}
+
+	return &jobmanagerv1.ResourceUsage{
+		UserTimeNs:      uint64(internalUsage.UserTime.Nanoseconds()),
+		SystemTimeNs:    uint64(internalUsage.SystemTime.Nanoseconds()),
+		PeakMemoryUsage: internalUsage.PeakMemoryUsage,
+		IoReadBytes:     internalUsage.IoReadBytes,
+		IoWriteBytes:    internalUsage.IoWriteBytes,
+		MaxPids:         internalUsage.MaxPids,
+	}
+}
+
+func (s *jobmanagerServer) Remove(
+	ctx context.Context,
+	requestJobID *jobmanagerv1.JobID,
+) (*jobmanagerv1.NilMessage, error) {
+
+	userID, err := GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.jm.Remove(userID, requestJobID.Id)
+	if err != nil {
+		return nil, err
 	}
+
+	return &jobmanagerv1.NilMessage{}, nil
 }
 
 func (s *jobmanagerServer) Query(
@@ -122,6 +318,64 @@ func (s *jobmanagerServer) Query(
 	return internalToExternalStatusV1(jobStatus), nil
 }
 
+func (s *jobmanagerServer) Stats(
+	ctx context.Context,
+	requestJobID *jobmanagerv1.JobID,
+) (*jobmanagerv1.JobStats, error) {
+
+	userID, err := GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	jobStats, err := s.jm.Stats(userID, requestJobID.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jobmanagerv1.JobStats{
+		CpuUsageNs:      uint64(jobStats.CpuUsage.Nanoseconds()),
+		MemoryUsage:     jobStats.MemoryUsage,
+		PeakMemoryUsage: jobStats.PeakMemoryUsage,
+		IoReadBytes:     jobStats.IoReadBytes,
+		IoWriteBytes:    jobStats.IoWriteBytes,
+		Pids:            jobStats.Pids,
+	}, nil
+}
+
+func (s *jobmanagerServer) Processes(
+	ctx context.Context,
+	requestJobID *jobmanagerv1.JobID,
+) (*jobmanagerv1.ProcessList, error) {
+
+	userID, err := GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	processes, err := s.jm.Processes(userID, requestJobID.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	processList := &jobmanagerv1.ProcessList{
+		Processes: make([]*jobmanagerv1.Process, 0, len(processes)),
+	}
+
+	for _, process := range processes {
+		processList.Processes = append(processList.Processes, &jobmanagerv1.Process{
+			Pid:       int32(process.Pid),
+			Ppid:      int32(process.Ppid),
+			Command:   process.Command,
+			State:     process.State,
+			Rss:       process.Rss,
+			CpuTimeNs: uint64(process.CpuTime.Nanoseconds()),
+		})
+	}
+
+	return processList, nil
+}
+
 func (s *jobmanagerServer) List(
 	ctx context.Context,
 	_ *jobmanagerv1.NilMessage,
@@ -179,11 +433,102 @@ func (s *jobmanagerServer) StreamOutput(
 		case <-response.Context().Done():
 			return response.Context().Err()
 
-		case data, ok := <-byteStream.Stream():
+		case chunk, ok := <-byteStream.Chunks():
 			if !ok {
 				return nil
 			}
-			response.Send(&jobmanagerv1.JobOutput{Output: data})
+			response.Send(&jobmanagerv1.JobOutput{
+				Output:         chunk.Data,
+				TruncatedBytes: uint64(chunk.Truncated),
+			})
+		}
+	}
+}
+
+func (s *jobmanagerServer) Exec(
+	request *jobmanagerv1.ExecRequest,
+	response jobmanagerv1.JobManager_ExecServer,
+) error {
+
+	userID, err := GetUserIDFromContext(response.Context())
+	if err != nil {
+		return err
+	}
+
+	process, err := s.jm.Exec(userID, request.GetJobID().GetId(),
+		request.GetProgramPath(), request.GetArguments())
+	if err != nil {
+		return err
+	}
+
+	// The output is not needed once it has been sent, or the client is gone
+	defer process.Release()
+
+	stdoutStream := process.StdoutStream()
+	defer stdoutStream.Close()
+
+	stderrStream := process.StderrStream()
+	defer stderrStream.Close()
+
+	stdout, stderr := stdoutStream.Chunks(), stderrStream.Chunks()
+
+	// Each stream is set to nil once it is complete
+	for stdout != nil || stderr != nil {
+		output := &jobmanagerv1.ExecOutput{}
+
+		select {
+		case <-response.Context().Done():
+			_ = process.Kill()
+			return response.Context().Err()
+
+		case chunk, ok := <-stdout:
+			if !ok {
+				stdout = nil
+				continue
+			}
+			output.OutputStream = jobmanagerv1.OutputStream_OutputStream_STDOUT
+			output.Output = chunk.Data
+			output.TruncatedBytes = uint64(chunk.Truncated)
+
+		case chunk, ok := <-stderr:
+			if !ok {
+				stderr = nil
+				continue
+			}
+			output.OutputStream = jobmanagerv1.OutputStream_OutputStream_STDERR
+			output.Output = chunk.Data
+			output.TruncatedBytes = uint64(chunk.Truncated)
+		}
+
+		if err := response.Send(output); err != nil {
+			_ = process.Kill()
+			return err
 		}
 	}
+
+	status := process.Wait()
+
+	exitStatus := &jobmanagerv1.ExecExitStatus{ExitCode: int32(status.ExitCode)}
+	if status.RunError != nil {
+		exitStatus.ErrorMessage = status.RunError.Error()
+	}
+
+	return response.Send(&jobmanagerv1.ExecOutput{ExitStatus: exitStatus})
+}
+
+func (s *jobmanagerServer) Info(
+	ctx context.Context,
+	_ *jobmanagerv1.NilMessage,
+) (*jobmanagerv1.ServerInfo, error) {
+
+	if _, err := GetUserIDFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	info := s.jm.ServerInfo()
+
+	return &jobmanagerv1.ServerInfo{
+		CgroupVersion: info.CgroupVersion,
+		Capabilities:  info.Capabilities,
+	}, nil
 }
@@ -17,6 +17,10 @@ import (
 	"context"
 	"testing"
 
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/config"
+	"github.com/adalton/teleport-exercise/pkg/io"
 	"github.com/adalton/teleport-exercise/pkg/jobmanager"
 	"github.com/adalton/teleport-exercise/pkg/jobmanager/jobmanagertest"
 	"github.com/adalton/teleport-exercise/server/jobmanager/serverv1"
@@ -91,6 +95,169 @@ func Test_jobmanagerServer_Start_NameExists(t *testing.T) {
 	assert.ErrorIs(t, err, jobmanager.ErrJobExists)
 }
 
+func Test_jobmanagerServer_Start_UnknownBlockIODevice(t *testing.T) {
+	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob,
+		[]cgroup.Controller{&cgroupv1.BlockIOController{}})
+	server := serverv1.NewJobManagerServerDetailed(jobManager)
+
+	ctx := serverv1.AttachUserIDToContext(context.Background(), "user1")
+
+	_, err := server.Start(ctx, &jobmanagerv1.JobCreationRequest{
+		Name:        "myJob",
+		ProgramPath: "/bin/ls",
+		Limits: &jobmanagerv1.ResourceLimits{
+			BlockIO: []*jobmanagerv1.BlockIOLimit{
+				{Path: "/no/such/path", ReadIops: 100},
+			},
+		},
+	})
+
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument)
+}
+
+func Test_jobmanagerServer_Start_UnavailableCpu(t *testing.T) {
+	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob,
+		[]cgroup.Controller{&cgroupv1.CpusetController{}})
+	server := serverv1.NewJobManagerServerDetailed(jobManager)
+
+	ctx := serverv1.AttachUserIDToContext(context.Background(), "user1")
+
+	_, err := server.Start(ctx, &jobmanagerv1.JobCreationRequest{
+		Name:        "myJob",
+		ProgramPath: "/bin/ls",
+		Limits: &jobmanagerv1.ResourceLimits{
+			CpusetCpus: "65535",
+		},
+	})
+
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument)
+}
+
+func Test_jobmanagerServer_Start_MalformedMemoryLimit(t *testing.T) {
+	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob,
+		[]cgroup.Controller{&cgroupv1.MemoryController{}})
+	server := serverv1.NewJobManagerServerDetailed(jobManager)
+
+	ctx := serverv1.AttachUserIDToContext(context.Background(), "user1")
+
+	_, err := server.Start(ctx, &jobmanagerv1.JobCreationRequest{
+		Name:        "myJob",
+		ProgramPath: "/bin/ls",
+		Limits: &jobmanagerv1.ResourceLimits{
+			MemoryLimit: "512Q",
+		},
+	})
+
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument)
+}
+
+func Test_jobmanagerServer_Start_KnobNotAllowed(t *testing.T) {
+	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob,
+		[]cgroup.Controller{cgroup.NewKnobController("memory", nil, config.CgroupAllowedKnobs)})
+	server := serverv1.NewJobManagerServerDetailed(jobManager)
+
+	ctx := serverv1.AttachUserIDToContext(context.Background(), "user1")
+
+	_, err := server.Start(ctx, &jobmanagerv1.JobCreationRequest{
+		Name:        "myJob",
+		ProgramPath: "/bin/ls",
+		Limits: &jobmanagerv1.ResourceLimits{
+			Knobs: []*jobmanagerv1.CgroupKnob{
+				{File: "memory/../../tasks", Value: "1"},
+			},
+		},
+	})
+
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument)
+}
+
+func Test_jobmanagerServer_Start_MalformedBlockIORate(t *testing.T) {
+	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob,
+		[]cgroup.Controller{&cgroupv1.BlockIOController{}})
+	server := serverv1.NewJobManagerServerDetailed(jobManager)
+
+	ctx := serverv1.AttachUserIDToContext(context.Background(), "user1")
+
+	_, err := server.Start(ctx, &jobmanagerv1.JobCreationRequest{
+		Name:        "myJob",
+		ProgramPath: "/bin/ls",
+		Limits: &jobmanagerv1.ResourceLimits{
+			BlockIO: []*jobmanagerv1.BlockIOLimit{{Path: "/", ReadBps: "40X/s"}},
+		},
+	})
+
+	if assert.ErrorIs(t, err, jobmanager.ErrInvalidArgument) {
+		assert.Contains(t, err.Error(), "blockIO[0].readBps")
+	}
+}
+
+func Test_jobmanagerServer_Create_NoUserID(t *testing.T) {
+	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+	server := serverv1.NewJobManagerServerDetailed(jobManager)
+
+	_, err := server.Create(context.Background(), &jobmanagerv1.JobCreationRequest{})
+
+	assert.ErrorIs(t, err, jobmanager.ErrUnauthenticated)
+}
+
+func Test_jobmanagerServer_Create_Launch(t *testing.T) {
+	const (
+		jobName     = "myJob"
+		programPath = "/bin/ls"
+	)
+	args := []string{"-l", "/"}
+
+	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+	server := serverv1.NewJobManagerServerDetailed(jobManager)
+	ctx := serverv1.AttachUserIDToContext(context.Background(), "user1")
+
+	job, err := server.Create(ctx, &jobmanagerv1.JobCreationRequest{
+		Name:        jobName,
+		ProgramPath: programPath,
+		Arguments:   args,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, jobName, job.Name)
+
+	jobStatus, err := server.Query(ctx, job.Id)
+	require.Nil(t, err)
+	assert.Equal(t, jobmanagerv1.JobState_JobState_CREATED, jobStatus.State)
+	assert.False(t, jobStatus.IsRunning)
+
+	_, err = server.Launch(ctx, job.Id)
+	require.Nil(t, err)
+
+	jobStatus, err = server.Query(ctx, job.Id)
+	require.Nil(t, err)
+	assert.Equal(t, jobmanagerv1.JobState_JobState_RUNNING, jobStatus.State)
+	assert.True(t, jobStatus.IsRunning)
+}
+
+func Test_jobmanagerServer_Launch_AlreadyStarted(t *testing.T) {
+	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+	server := serverv1.NewJobManagerServerDetailed(jobManager)
+	ctx := serverv1.AttachUserIDToContext(context.Background(), "user1")
+
+	job, err := server.Start(ctx, &jobmanagerv1.JobCreationRequest{
+		Name:        "myJob",
+		ProgramPath: "/bin/ls",
+	})
+	require.Nil(t, err)
+
+	_, err = server.Launch(ctx, job.Id)
+
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidJobState)
+}
+
+func Test_jobmanagerServer_Launch_NoUserID(t *testing.T) {
+	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+	server := serverv1.NewJobManagerServerDetailed(jobManager)
+
+	_, err := server.Launch(context.Background(), &jobmanagerv1.JobID{Id: "b13620d4-db7f-46d5-b445-b29af0f87d2c"})
+
+	assert.ErrorIs(t, err, jobmanager.ErrUnauthenticated)
+}
+
 func Test_jobmanagerServer_Stop_NoUserID(t *testing.T) {
 	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
 	server := serverv1.NewJobManagerServerDetailed(jobManager)
@@ -142,6 +309,187 @@ func Test_jobmanagerServer_Stop_JobExists(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func Test_jobmanagerServer_Remove_JobExists(t *testing.T) {
+	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+	server := serverv1.NewJobManagerServerDetailed(jobManager)
+	ctx := serverv1.AttachUserIDToContext(context.Background(), "user1")
+
+	job, err := server.Start(ctx, &jobmanagerv1.JobCreationRequest{
+		Name:        "myJob",
+		ProgramPath: "/bin/ls",
+	})
+	require.Nil(t, err)
+
+	_, err = server.Remove(ctx, job.Id)
+	assert.ErrorIs(t, err, jobmanager.ErrInvalidJobState)
+
+	_, err = server.Stop(ctx, job.Id)
+	require.Nil(t, err)
+
+	_, err = server.Remove(ctx, job.Id)
+	assert.Nil(t, err)
+
+	_, err = server.Query(ctx, job.Id)
+	assert.ErrorIs(t, err, jobmanager.ErrJobNotFound)
+}
+
+func Test_jobmanagerServer_Remove_NoUserID(t *testing.T) {
+	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+	server := serverv1.NewJobManagerServerDetailed(jobManager)
+
+	_, err := server.Remove(context.Background(), &jobmanagerv1.JobID{Id: "b13620d4-db7f-46d5-b445-b29af0f87d2c"})
+
+	assert.ErrorIs(t, err, jobmanager.ErrUnauthenticated)
+}
+
+func Test_jobmanagerServer_Stats_JobExists(t *testing.T) {
+	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+	server := serverv1.NewJobManagerServerDetailed(jobManager)
+	ctx := serverv1.AttachUserIDToContext(context.Background(), "user1")
+
+	job, err := server.Start(ctx, &jobmanagerv1.JobCreationRequest{
+		Name:        "myJob",
+		ProgramPath: "/bin/ls",
+	})
+	assert.Nil(t, err)
+
+	jobStats, err := server.Stats(ctx, &jobmanagerv1.JobID{Id: job.Id.Id})
+
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(jobmanagertest.DefaultCpuUsage.Nanoseconds()), jobStats.CpuUsageNs)
+	assert.Equal(t, uint64(jobmanagertest.DefaultMemoryUsage), jobStats.MemoryUsage)
+	assert.Equal(t, uint64(jobmanagertest.DefaultPeakMemoryUsage), jobStats.PeakMemoryUsage)
+	assert.Equal(t, uint64(jobmanagertest.DefaultIoReadBytes), jobStats.IoReadBytes)
+	assert.Equal(t, uint64(jobmanagertest.DefaultIoWriteBytes), jobStats.IoWriteBytes)
+	assert.Equal(t, uint64(jobmanagertest.DefaultPids), jobStats.Pids)
+}
+
+func Test_jobmanagerServer_Stats_NoUserID(t *testing.T) {
+	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+	server := serverv1.NewJobManagerServerDetailed(jobManager)
+
+	_, err := server.Stats(context.Background(), &jobmanagerv1.JobID{Id: "3e3d8936-5fd7-46bb-9fd2-8423c607a0b2"})
+
+	assert.ErrorIs(t, err, jobmanager.ErrUnauthenticated)
+}
+
+func Test_jobmanagerServer_Processes_JobExists(t *testing.T) {
+	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+	server := serverv1.NewJobManagerServerDetailed(jobManager)
+	ctx := serverv1.AttachUserIDToContext(context.Background(), "user1")
+
+	job, err := server.Start(ctx, &jobmanagerv1.JobCreationRequest{
+		Name:        "myJob",
+		ProgramPath: "/bin/ls",
+	})
+	assert.Nil(t, err)
+
+	processList, err := server.Processes(ctx, &jobmanagerv1.JobID{Id: job.Id.Id})
+
+	assert.Nil(t, err)
+	require.Equal(t, 1, len(processList.Processes))
+	process := processList.Processes[0]
+	assert.Equal(t, int32(jobmanagertest.DefaultPID), process.Pid)
+	assert.Equal(t, int32(1), process.Ppid)
+	assert.Equal(t, []string{"/bin/sh", "-c", "sleep 60"}, process.Command)
+	assert.Equal(t, "S", process.State)
+	assert.Equal(t, uint64(jobmanagertest.DefaultProcessRss), process.Rss)
+	assert.Equal(t, uint64(jobmanagertest.DefaultCpuUsage.Nanoseconds()), process.CpuTimeNs)
+}
+
+func Test_jobmanagerServer_Processes_NoUserID(t *testing.T) {
+	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+	server := serverv1.NewJobManagerServerDetailed(jobManager)
+
+	_, err := server.Processes(context.Background(), &jobmanagerv1.JobID{Id: "3e3d8936-5fd7-46bb-9fd2-8423c607a0b2"})
+
+	assert.ErrorIs(t, err, jobmanager.ErrUnauthenticated)
+}
+
+func Test_jobmanagerServer_Exec_JobExists(t *testing.T) {
+	ctx := serverv1.AttachUserIDToContext(context.Background(), "user1")
+	mockServer := &testserverv1.MockJobmanagerExecServer{
+		NextContext: ctx,
+	}
+
+	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+	server := serverv1.NewJobManagerServerDetailed(jobManager)
+	job, err := server.Start(ctx, &jobmanagerv1.JobCreationRequest{
+		Name:        "myJob",
+		ProgramPath: "/bin/sleep",
+		Arguments:   []string{"60"},
+	})
+	assert.Nil(t, err)
+
+	err = server.Exec(&jobmanagerv1.ExecRequest{
+		JobID:       &jobmanagerv1.JobID{Id: job.Id.Id},
+		ProgramPath: "/bin/ps",
+	}, mockServer)
+
+	assert.Nil(t, err)
+
+	var stdout, stderr []byte
+	var exitStatus *jobmanagerv1.ExecExitStatus
+	for _, output := range mockServer.Outputs {
+		switch {
+		case output.ExitStatus != nil:
+			exitStatus = output.ExitStatus
+		case output.OutputStream == jobmanagerv1.OutputStream_OutputStream_STDERR:
+			stderr = append(stderr, output.Output...)
+		default:
+			stdout = append(stdout, output.Output...)
+		}
+	}
+
+	assert.Equal(t, jobmanagertest.DefaultExecStandardOutput, string(stdout))
+	assert.Equal(t, jobmanagertest.DefaultExecStandardError, string(stderr))
+	require.NotNil(t, exitStatus)
+	assert.Equal(t, int32(jobmanagertest.DefaultExecExitCode), exitStatus.ExitCode)
+	assert.Equal(t, "", exitStatus.ErrorMessage)
+	assert.NotNil(t, mockServer.Outputs[len(mockServer.Outputs)-1].ExitStatus)
+}
+
+func Test_jobmanagerServer_Exec_NotRunning(t *testing.T) {
+	ctx := serverv1.AttachUserIDToContext(context.Background(), "user1")
+	mockServer := &testserverv1.MockJobmanagerExecServer{
+		NextContext: ctx,
+	}
+
+	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+	server := serverv1.NewJobManagerServerDetailed(jobManager)
+	job, err := server.Start(ctx, &jobmanagerv1.JobCreationRequest{
+		Name:        "myJob",
+		ProgramPath: "/bin/ls",
+	})
+	assert.Nil(t, err)
+
+	_, err = server.Stop(ctx, &jobmanagerv1.JobID{Id: job.Id.Id})
+	assert.Nil(t, err)
+
+	err = server.Exec(&jobmanagerv1.ExecRequest{
+		JobID:       &jobmanagerv1.JobID{Id: job.Id.Id},
+		ProgramPath: "/bin/ps",
+	}, mockServer)
+
+	assert.ErrorIs(t, err, jobmanager.ErrJobNotRunning)
+	assert.Equal(t, 0, len(mockServer.Outputs))
+}
+
+func Test_jobmanagerServer_Exec_NoUserID(t *testing.T) {
+	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+	server := serverv1.NewJobManagerServerDetailed(jobManager)
+	mockServer := &testserverv1.MockJobmanagerExecServer{
+		NextContext: context.Background(),
+	}
+
+	err := server.Exec(&jobmanagerv1.ExecRequest{
+		JobID:       &jobmanagerv1.JobID{Id: "3e3d8936-5fd7-46bb-9fd2-8423c607a0b2"},
+		ProgramPath: "/bin/ps",
+	}, mockServer)
+
+	assert.ErrorIs(t, err, jobmanager.ErrUnauthenticated)
+}
+
 func Test_jobmanagerServer_Query_NoUserID(t *testing.T) {
 	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
 	server := serverv1.NewJobManagerServerDetailed(jobManager)
@@ -187,11 +535,42 @@ func Test_jobmanagerServer_Query_JobExists(t *testing.T) {
 	assert.Equal(t, job.Id, jobStatus.Job.Id)
 	assert.Equal(t, owner, jobStatus.Owner)
 	assert.True(t, jobStatus.IsRunning)
+	assert.Equal(t, jobmanagerv1.JobState_JobState_RUNNING, jobStatus.State)
 	assert.Equal(t, int32(jobmanagertest.DefaultPID), jobStatus.Pid)
 	assert.Equal(t, int32(jobmanagertest.DefaultSignalWhileRunning), jobStatus.SignalNumber)
 	assert.Equal(t, "", jobStatus.ErrorMessage)
 }
 
+func Test_jobmanagerServer_Query_StoppedJobUsage(t *testing.T) {
+	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+	server := serverv1.NewJobManagerServerDetailed(jobManager)
+	ctx := serverv1.AttachUserIDToContext(context.Background(), "user1")
+
+	job, err := server.Start(ctx, &jobmanagerv1.JobCreationRequest{
+		Name:        "myJob",
+		ProgramPath: "/bin/ls",
+	})
+	assert.Nil(t, err)
+
+	jobStatus, err := server.Query(ctx, &jobmanagerv1.JobID{Id: job.Id.Id})
+	assert.Nil(t, err)
+	assert.Nil(t, jobStatus.Usage)
+
+	_, err = server.Stop(ctx, job.Id)
+	assert.Nil(t, err)
+
+	jobStatus, err = server.Query(ctx, &jobmanagerv1.JobID{Id: job.Id.Id})
+
+	assert.Nil(t, err)
+	assert.Equal(t, &jobmanagerv1.ResourceUsage{
+		UserTimeNs:      uint64(jobmanagertest.DefaultCpuUsage.Nanoseconds()),
+		PeakMemoryUsage: jobmanagertest.DefaultPeakMemoryUsage,
+		IoReadBytes:     jobmanagertest.DefaultIoReadBytes,
+		IoWriteBytes:    jobmanagertest.DefaultIoWriteBytes,
+		MaxPids:         jobmanagertest.DefaultPids,
+	}, jobStatus.Usage)
+}
+
 func Test_jobmanagerServer_List_NoUserID(t *testing.T) {
 	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
 	server := serverv1.NewJobManagerServerDetailed(jobManager)
@@ -358,6 +737,60 @@ func Test_jobmanagerServer_Stream_ReadSuccessfully(t *testing.T) {
 	assert.Equal(t, []byte(jobmanagertest.DefaultStandardOutput), mockServer.LastJobOutput.Output)
 }
 
+// truncatingJob is a Job whose standard output has discarded all but the
+// last four of the ten bytes written to it.
+type truncatingJob struct {
+	jobmanager.Job
+}
+
+func newTruncatingJob(
+	owner string,
+	jobName string,
+	controllers []cgroup.Controller,
+	programPath string,
+	arguments ...string,
+) jobmanager.Job {
+	return &truncatingJob{
+		Job: jobmanagertest.NewMockJob(owner, jobName, controllers, programPath, arguments...),
+	}
+}
+
+func (j *truncatingJob) StdoutStream() *io.ByteStream {
+	buffer := io.NewRingBuffer(4)
+	_, _ = buffer.Write([]byte("abcdefghij"))
+	buffer.Close()
+
+	return io.NewByteStream(buffer)
+}
+
+func Test_jobmanagerServer_Stream_Truncated(t *testing.T) {
+	ctx := serverv1.AttachUserIDToContext(context.Background(), "user1")
+	mockServer := &testserverv1.MockJobmanagerStreamServer{
+		NextContext: ctx,
+	}
+
+	jobManager := jobmanager.NewManagerDetailed(newTruncatingJob, nil)
+	server := serverv1.NewJobManagerServerDetailed(jobManager)
+	job, err := server.Start(ctx, &jobmanagerv1.JobCreationRequest{
+		Name:        "myJob",
+		ProgramPath: "/bin/ls",
+	})
+	require.Nil(t, err)
+
+	err = server.StreamOutput(&jobmanagerv1.StreamOutputRequest{
+		JobID:        &jobmanagerv1.JobID{Id: job.Id.Id},
+		OutputStream: jobmanagerv1.OutputStream_OutputStream_STDOUT,
+	}, mockServer)
+
+	// The truncation is reported in its own message, not in the output
+	assert.Nil(t, err)
+	require.Equal(t, 2, len(mockServer.JobOutputs))
+	assert.Equal(t, uint64(6), mockServer.JobOutputs[0].TruncatedBytes)
+	assert.Empty(t, mockServer.JobOutputs[0].Output)
+	assert.Equal(t, uint64(0), mockServer.JobOutputs[1].TruncatedBytes)
+	assert.Equal(t, []byte("ghij"), mockServer.JobOutputs[1].Output)
+}
+
 func Test_jobmanagerServer_Multitenant(t *testing.T) {
 	const (
 		jobName     = "myJob"
@@ -543,3 +976,27 @@ func Test_jobmanagerServer_AdministratorCanQueryUser1sJob(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, job.Id.Id, jobStatus.Job.Id.Id)
 }
+
+func Test_jobmanagerServer_Info_NoUserID(t *testing.T) {
+	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, nil)
+	server := serverv1.NewJobManagerServerDetailed(jobManager)
+
+	_, err := server.Info(context.Background(), &jobmanagerv1.NilMessage{})
+
+	assert.ErrorIs(t, err, jobmanager.ErrUnauthenticated)
+}
+
+func Test_jobmanagerServer_Info_WithUserID(t *testing.T) {
+	controllers := []cgroup.Controller{
+		&cgroupv1.CpuController{},
+		&cgroupv1.MemoryController{},
+	}
+	jobManager := jobmanager.NewManagerDetailed(jobmanagertest.NewMockJob, controllers)
+	server := serverv1.NewJobManagerServerDetailed(jobManager)
+	ctx := serverv1.AttachUserIDToContext(context.Background(), "user1")
+
+	info, err := server.Info(ctx, &jobmanagerv1.NilMessage{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"cpu", "memory"}, info.Capabilities)
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testserverv1
+
+import (
+	"context"
+
+	"github.com/adalton/teleport-exercise/service/jobmanager/jobmanagerv1"
+	"google.golang.org/grpc/metadata"
+)
+
+// MockJobmanagerExecServer mocks the APIs used by the JobManager server's
+// Exec handler.
+type MockJobmanagerExecServer struct {
+	Outputs     []*jobmanagerv1.ExecOutput
+	SendError   error
+	NextContext context.Context
+}
+
+func (m *MockJobmanagerExecServer) Send(output *jobmanagerv1.ExecOutput) error {
+	m.Outputs = append(m.Outputs, output)
+	return m.SendError
+}
+
+func (m *MockJobmanagerExecServer) Context() context.Context {
+	return m.NextContext
+}
+
+// SetHeader is not yet implemented; it will panic.
+func (m *MockJobmanagerExecServer) SetHeader(metadata.MD) error {
+	panic("unimplemented")
+}
+
+// SendHeader is not yet implemented; it will panic.
+func (m *MockJobmanagerExecServer) SendHeader(metadata.MD) error {
+	panic("unimplemented")
+}
+
+// SetTrailer is not yet implemented; it will panic.
+func (m *MockJobmanagerExecServer) SetTrailer(metadata.MD) {
+	panic("unimplemented")
+}
+
+// SendMsg is not yet implemented; it will panic.
+func (m *MockJobmanagerExecServer) SendMsg(interface{}) error {
+	panic("unimplemented")
+}
+
+// RecvMsg is not yet implemented; it will panic.
+func (m *MockJobmanagerExecServer) RecvMsg(interface{}) error {
+	panic("unimplemented")
+}
@@ -23,6 +23,7 @@ import (
 // MockJobManagerStreamServer mocks the APIs used by a JobManager server.
 type MockJobmanagerStreamServer struct {
 	LastJobOutput *jobmanagerv1.JobOutput
+	JobOutputs    []*jobmanagerv1.JobOutput
 	SendCount     int
 	SendError     error
 	NextContext   context.Context
@@ -31,6 +32,7 @@ type MockJobmanagerStreamServer struct {
 func (m *MockJobmanagerStreamServer) Send(output *jobmanagerv1.JobOutput) error {
 	m.SendCount++
 	m.LastJobOutput = output
+	m.JobOutputs = append(m.JobOutputs, output)
 	return m.SendError
 }
 
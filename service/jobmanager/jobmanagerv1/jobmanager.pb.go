@@ -26,6 +26,183 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// The OomPolicy enumeration captures what happens to a job that
+// reaches its memory limit.
+type OomPolicy int32
+
+const (
+	// Keep the server's default behavior
+	OomPolicy_OomPolicy_DEFAULT OomPolicy = 0
+	// Kill the job
+	OomPolicy_OomPolicy_KILL OomPolicy = 1
+	// Pause the job until memory is freed
+	OomPolicy_OomPolicy_PAUSE OomPolicy = 2
+)
+
+// Enum value maps for OomPolicy.
+var (
+	OomPolicy_name = map[int32]string{
+		0: "OomPolicy_DEFAULT",
+		1: "OomPolicy_KILL",
+		2: "OomPolicy_PAUSE",
+	}
+	OomPolicy_value = map[string]int32{
+		"OomPolicy_DEFAULT": 0,
+		"OomPolicy_KILL":    1,
+		"OomPolicy_PAUSE":   2,
+	}
+)
+
+func (x OomPolicy) Enum() *OomPolicy {
+	p := new(OomPolicy)
+	*p = x
+	return p
+}
+
+func (x OomPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OomPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_jobmanager_proto_enumTypes[0].Descriptor()
+}
+
+func (OomPolicy) Type() protoreflect.EnumType {
+	return &file_jobmanager_proto_enumTypes[0]
+}
+
+func (x OomPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OomPolicy.Descriptor instead.
+func (OomPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_jobmanager_proto_rawDescGZIP(), []int{0}
+}
+
+// The JobState enumeration captures the stage of its lifecycle that
+// a job has reached.
+type JobState int32
+
+const (
+	// The job has been created but not yet launched
+	JobState_JobState_CREATED JobState = 0
+	// The job's program is running
+	JobState_JobState_RUNNING JobState = 1
+	// The job has terminated, was stopped before it was launched, or
+	// failed to start
+	JobState_JobState_TERMINATED JobState = 2
+)
+
+// Enum value maps for JobState.
+var (
+	JobState_name = map[int32]string{
+		0: "JobState_CREATED",
+		1: "JobState_RUNNING",
+		2: "JobState_TERMINATED",
+	}
+	JobState_value = map[string]int32{
+		"JobState_CREATED":    0,
+		"JobState_RUNNING":    1,
+		"JobState_TERMINATED": 2,
+	}
+)
+
+func (x JobState) Enum() *JobState {
+	p := new(JobState)
+	*p = x
+	return p
+}
+
+func (x JobState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JobState) Descriptor() protoreflect.EnumDescriptor {
+	return file_jobmanager_proto_enumTypes[1].Descriptor()
+}
+
+func (JobState) Type() protoreflect.EnumType {
+	return &file_jobmanager_proto_enumTypes[1]
+}
+
+func (x JobState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JobState.Descriptor instead.
+func (JobState) EnumDescriptor() ([]byte, []int) {
+	return file_jobmanager_proto_rawDescGZIP(), []int{1}
+}
+
+// The TerminationReason enumeration captures why a job terminated.
+type TerminationReason int32
+
+const (
+	// The job has not terminated
+	TerminationReason_TerminationReason_NONE TerminationReason = 0
+	// The job exited on its own
+	TerminationReason_TerminationReason_EXITED TerminationReason = 1
+	// The job was killed by a signal sent neither by the Stop API nor
+	// by the OOM killer
+	TerminationReason_TerminationReason_SIGNALED TerminationReason = 2
+	// The job was killed by the Stop API
+	TerminationReason_TerminationReason_STOPPED TerminationReason = 3
+	// The job was killed by the OOM killer on reaching its memory
+	// limit
+	TerminationReason_TerminationReason_OUT_OF_MEMORY TerminationReason = 4
+	// The job's program was never started (e.g., because it does not
+	// exist)
+	TerminationReason_TerminationReason_FAILED_TO_START TerminationReason = 5
+)
+
+// Enum value maps for TerminationReason.
+var (
+	TerminationReason_name = map[int32]string{
+		0: "TerminationReason_NONE",
+		1: "TerminationReason_EXITED",
+		2: "TerminationReason_SIGNALED",
+		3: "TerminationReason_STOPPED",
+		4: "TerminationReason_OUT_OF_MEMORY",
+		5: "TerminationReason_FAILED_TO_START",
+	}
+	TerminationReason_value = map[string]int32{
+		"TerminationReason_NONE":            0,
+		"TerminationReason_EXITED":          1,
+		"TerminationReason_SIGNALED":        2,
+		"TerminationReason_STOPPED":         3,
+		"TerminationReason_OUT_OF_MEMORY":   4,
+		"TerminationReason_FAILED_TO_START": 5,
+	}
+)
+
+func (x TerminationReason) Enum() *TerminationReason {
+	p := new(TerminationReason)
+	*p = x
+	return p
+}
+
+func (x TerminationReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TerminationReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_jobmanager_proto_enumTypes[2].Descriptor()
+}
+
+func (TerminationReason) Type() protoreflect.EnumType {
+	return &file_jobmanager_proto_enumTypes[2]
+}
+
+func (x TerminationReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TerminationReason.Descriptor instead.
+func (TerminationReason) EnumDescriptor() ([]byte, []int) {
+	return file_jobmanager_proto_rawDescGZIP(), []int{2}
+}
+
 // The OutputStream enumeration captures the set of output stream
 // the JobManager can stream from the process.
 type OutputStream int32
@@ -64,11 +241,11 @@ func (x OutputStream) String() string {
 }
 
 func (OutputStream) Descriptor() protoreflect.EnumDescriptor {
-	return file_jobmanager_proto_enumTypes[0].Descriptor()
+	return file_jobmanager_proto_enumTypes[3].Descriptor()
 }
 
 func (OutputStream) Type() protoreflect.EnumType {
-	return &file_jobmanager_proto_enumTypes[0]
+	return &file_jobmanager_proto_enumTypes[3]
 }
 
 func (x OutputStream) Number() protoreflect.EnumNumber {
@@ -77,7 +254,7 @@ func (x OutputStream) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use OutputStream.Descriptor instead.
 func (OutputStream) EnumDescriptor() ([]byte, []int) {
-	return file_jobmanager_proto_rawDescGZIP(), []int{0}
+	return file_jobmanager_proto_rawDescGZIP(), []int{3}
 }
 
 // A JobCreationRequest is a message that clients use to request
@@ -96,6 +273,9 @@ type JobCreationRequest struct {
 	ProgramPath string `protobuf:"bytes,2,opt,name=programPath,proto3" json:"programPath,omitempty"`
 	// Arguments to pass to the the program
 	Arguments []string `protobuf:"bytes,3,rep,name=arguments,proto3" json:"arguments,omitempty"`
+	// Resource limits for the job.  Limits that are not specified
+	// take the server's default values.
+	Limits *ResourceLimits `protobuf:"bytes,4,opt,name=limits,proto3" json:"limits,omitempty"`
 }
 
 func (x *JobCreationRequest) Reset() {
@@ -151,19 +331,69 @@ func (x *JobCreationRequest) GetArguments() []string {
 	return nil
 }
 
-// A JobID is a message that client use to uniquely identify a job
-// managed by the JobManager.
-type JobID struct {
+func (x *JobCreationRequest) GetLimits() *ResourceLimits {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+// The ResourceLimits message describes the resource limits that a
+// client requests for a job.
+type ResourceLimits struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// A server-assigned UUIDv4 for the job
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-}
-
-func (x *JobID) Reset() {
-	*x = JobID{}
+	// Block IO throttling limits, at most one per block device
+	BlockIO []*BlockIOLimit `protobuf:"bytes,1,rep,name=blockIO,proto3" json:"blockIO,omitempty"`
+	// The maximum number of processes and threads that may exist in
+	// the job at any one time.  Zero keeps the server's default.
+	MaxPids uint64 `protobuf:"varint,2,opt,name=maxPids,proto3" json:"maxPids,omitempty"`
+	// The CPUs to which the job is restricted, as a list in the
+	// kernel's format (e.g., "0-3,8").  The CPUs must be available to
+	// the server.  Empty keeps the server's default.
+	CpusetCpus string `protobuf:"bytes,3,opt,name=cpusetCpus,proto3" json:"cpusetCpus,omitempty"`
+	// The memory nodes to which the job is restricted, in the same
+	// format as cpusetCpus.  Empty keeps the server's default.
+	CpusetMems string `protobuf:"bytes,4,opt,name=cpusetMems,proto3" json:"cpusetMems,omitempty"`
+	// The CPU quota of the job, in CPUs (e.g., 1.5).  Zero keeps the
+	// server's default.
+	Cpus float64 `protobuf:"fixed64,5,opt,name=cpus,proto3" json:"cpus,omitempty"`
+	// If true, the job has no CPU quota and competes for CPU time
+	// according to its cpuWeight.  Cannot be combined with cpus.
+	UnlimitedCpus bool `protobuf:"varint,6,opt,name=unlimitedCpus,proto3" json:"unlimitedCpus,omitempty"`
+	// The relative share of CPU time that the job receives when CPUs
+	// are contended, in the range [1, 10000]; jobs have a weight of
+	// 100 by default.  Zero keeps the server's default.
+	CpuWeight uint64 `protobuf:"varint,7,opt,name=cpuWeight,proto3" json:"cpuWeight,omitempty"`
+	// The period, in microseconds, over which the CPU quota is
+	// enforced, in the range [1000, 1000000].  Zero keeps the
+	// server's default.
+	CpuPeriodUs uint64 `protobuf:"varint,8,opt,name=cpuPeriodUs,proto3" json:"cpuPeriodUs,omitempty"`
+	// The maximum memory usage of the job, in bytes with an optional
+	// K, M, G or T suffix (e.g., "512M").  Empty keeps the server's
+	// default.
+	MemoryLimit string `protobuf:"bytes,9,opt,name=memoryLimit,proto3" json:"memoryLimit,omitempty"`
+	// The maximum combined memory and swap usage of the job, in the
+	// same format as memoryLimit.  It must not be less than the
+	// memory limit; equal limits prevent the job from using swap.
+	// Empty keeps the server's default.
+	MemorySwapLimit string `protobuf:"bytes,10,opt,name=memorySwapLimit,proto3" json:"memorySwapLimit,omitempty"`
+	// The memory usage above which the job's memory is reclaimed
+	// first under memory pressure, in the same format as memoryLimit.
+	// Empty keeps the server's default.
+	MemorySoftLimit string `protobuf:"bytes,11,opt,name=memorySoftLimit,proto3" json:"memorySoftLimit,omitempty"`
+	// What happens when the job reaches its memory limit
+	OomPolicy OomPolicy `protobuf:"varint,12,opt,name=oomPolicy,proto3,enum=jobmanager.v1.OomPolicy" json:"oomPolicy,omitempty"`
+	// Cgroup interface files to write for the job, from among those
+	// that the server allows.  Knobs replace the server's defaults for
+	// the same files.
+	Knobs []*CgroupKnob `protobuf:"bytes,13,rep,name=knobs,proto3" json:"knobs,omitempty"`
+}
+
+func (x *ResourceLimits) Reset() {
+	*x = ResourceLimits{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_jobmanager_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -171,13 +401,13 @@ func (x *JobID) Reset() {
 	}
 }
 
-func (x *JobID) String() string {
+func (x *ResourceLimits) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobID) ProtoMessage() {}
+func (*ResourceLimits) ProtoMessage() {}
 
-func (x *JobID) ProtoReflect() protoreflect.Message {
+func (x *ResourceLimits) ProtoReflect() protoreflect.Message {
 	mi := &file_jobmanager_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -189,33 +419,128 @@ func (x *JobID) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobID.ProtoReflect.Descriptor instead.
-func (*JobID) Descriptor() ([]byte, []int) {
+// Deprecated: Use ResourceLimits.ProtoReflect.Descriptor instead.
+func (*ResourceLimits) Descriptor() ([]byte, []int) {
 	return file_jobmanager_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *JobID) GetId() string {
+func (x *ResourceLimits) GetBlockIO() []*BlockIOLimit {
 	if x != nil {
-		return x.Id
+		return x.BlockIO
+	}
+	return nil
+}
+
+func (x *ResourceLimits) GetMaxPids() uint64 {
+	if x != nil {
+		return x.MaxPids
+	}
+	return 0
+}
+
+func (x *ResourceLimits) GetCpusetCpus() string {
+	if x != nil {
+		return x.CpusetCpus
 	}
 	return ""
 }
 
-// A Job is a message that uniquely identifies one of the Jobs managed
-// by the JobManager service.
-type Job struct {
+func (x *ResourceLimits) GetCpusetMems() string {
+	if x != nil {
+		return x.CpusetMems
+	}
+	return ""
+}
+
+func (x *ResourceLimits) GetCpus() float64 {
+	if x != nil {
+		return x.Cpus
+	}
+	return 0
+}
+
+func (x *ResourceLimits) GetUnlimitedCpus() bool {
+	if x != nil {
+		return x.UnlimitedCpus
+	}
+	return false
+}
+
+func (x *ResourceLimits) GetCpuWeight() uint64 {
+	if x != nil {
+		return x.CpuWeight
+	}
+	return 0
+}
+
+func (x *ResourceLimits) GetCpuPeriodUs() uint64 {
+	if x != nil {
+		return x.CpuPeriodUs
+	}
+	return 0
+}
+
+func (x *ResourceLimits) GetMemoryLimit() string {
+	if x != nil {
+		return x.MemoryLimit
+	}
+	return ""
+}
+
+func (x *ResourceLimits) GetMemorySwapLimit() string {
+	if x != nil {
+		return x.MemorySwapLimit
+	}
+	return ""
+}
+
+func (x *ResourceLimits) GetMemorySoftLimit() string {
+	if x != nil {
+		return x.MemorySoftLimit
+	}
+	return ""
+}
+
+func (x *ResourceLimits) GetOomPolicy() OomPolicy {
+	if x != nil {
+		return x.OomPolicy
+	}
+	return OomPolicy_OomPolicy_DEFAULT
+}
+
+func (x *ResourceLimits) GetKnobs() []*CgroupKnob {
+	if x != nil {
+		return x.Knobs
+	}
+	return nil
+}
+
+// The BlockIOLimit message describes the IO throttling limits for a
+// single block device.  A limit of zero keeps the server's default
+// for that limit.
+type BlockIOLimit struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The server-assigned ID
-	Id *JobID `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	// The client-specified name for the job
-	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-}
-
-func (x *Job) Reset() {
-	*x = Job{}
+	// A block device node, or any path on a filesystem, in which case
+	// the limits apply to the block device that holds the filesystem
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// The maximum number of bytes per second read from the device,
+	// with an optional K, M, G or T suffix and an optional "/s"
+	// (e.g., "40M/s").  Empty keeps the server's default.
+	ReadBps string `protobuf:"bytes,6,opt,name=readBps,proto3" json:"readBps,omitempty"`
+	// The maximum number of bytes per second written to the device,
+	// in the same format as readBps
+	WriteBps string `protobuf:"bytes,7,opt,name=writeBps,proto3" json:"writeBps,omitempty"`
+	// The maximum number of read operations per second
+	ReadIops uint64 `protobuf:"varint,4,opt,name=readIops,proto3" json:"readIops,omitempty"`
+	// The maximum number of write operations per second
+	WriteIops uint64 `protobuf:"varint,5,opt,name=writeIops,proto3" json:"writeIops,omitempty"`
+}
+
+func (x *BlockIOLimit) Reset() {
+	*x = BlockIOLimit{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_jobmanager_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -223,13 +548,13 @@ func (x *Job) Reset() {
 	}
 }
 
-func (x *Job) String() string {
+func (x *BlockIOLimit) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Job) ProtoMessage() {}
+func (*BlockIOLimit) ProtoMessage() {}
 
-func (x *Job) ProtoReflect() protoreflect.Message {
+func (x *BlockIOLimit) ProtoReflect() protoreflect.Message {
 	mi := &file_jobmanager_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -241,53 +566,61 @@ func (x *Job) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Job.ProtoReflect.Descriptor instead.
-func (*Job) Descriptor() ([]byte, []int) {
+// Deprecated: Use BlockIOLimit.ProtoReflect.Descriptor instead.
+func (*BlockIOLimit) Descriptor() ([]byte, []int) {
 	return file_jobmanager_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *Job) GetId() *JobID {
+func (x *BlockIOLimit) GetPath() string {
 	if x != nil {
-		return x.Id
+		return x.Path
 	}
-	return nil
+	return ""
 }
 
-func (x *Job) GetName() string {
+func (x *BlockIOLimit) GetReadBps() string {
 	if x != nil {
-		return x.Name
+		return x.ReadBps
 	}
 	return ""
 }
 
-// The JobStatus message is used to communicate the status of a job.
-type JobStatus struct {
+func (x *BlockIOLimit) GetWriteBps() string {
+	if x != nil {
+		return x.WriteBps
+	}
+	return ""
+}
+
+func (x *BlockIOLimit) GetReadIops() uint64 {
+	if x != nil {
+		return x.ReadIops
+	}
+	return 0
+}
+
+func (x *BlockIOLimit) GetWriteIops() uint64 {
+	if x != nil {
+		return x.WriteIops
+	}
+	return 0
+}
+
+// The CgroupKnob message describes a value to write to a cgroup
+// interface file of a job.
+type CgroupKnob struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The Job with which this status is associated
-	Job *Job `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
-	// The user who started the job
-	Owner string `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
-	// Is the job running?
-	IsRunning bool `protobuf:"varint,3,opt,name=isRunning,proto3" json:"isRunning,omitempty"`
-	// The process ID of the job
-	Pid int32 `protobuf:"varint,4,opt,name=pid,proto3" json:"pid,omitempty"`
-	// If the job is not running and was terminated by a signal, which
-	// signal?  If the process is terminated as a result of the Stop
-	// API, then this will indicate the KILL signal (9).  If the
-	// process was terminated by an extraneous signal, then this field
-	// will indicate the signal number that was received.
-	SignalNumber int32 `protobuf:"varint,5,opt,name=signalNumber,proto3" json:"signalNumber,omitempty"`
-	// If the job is not running, what was its exit code?
-	ExitCode int32 `protobuf:"varint,6,opt,name=exitCode,proto3" json:"exitCode,omitempty"`
-	// If a job failed to start, what was the cause of the failure?
-	ErrorMessage string `protobuf:"bytes,7,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
+	// The name of the interface file (e.g., "memory.swappiness")
+	File string `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	// The value to write to the file
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 }
 
-func (x *JobStatus) Reset() {
-	*x = JobStatus{}
+func (x *CgroupKnob) Reset() {
+	*x = CgroupKnob{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_jobmanager_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -295,13 +628,13 @@ func (x *JobStatus) Reset() {
 	}
 }
 
-func (x *JobStatus) String() string {
+func (x *CgroupKnob) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobStatus) ProtoMessage() {}
+func (*CgroupKnob) ProtoMessage() {}
 
-func (x *JobStatus) ProtoReflect() protoreflect.Message {
+func (x *CgroupKnob) ProtoReflect() protoreflect.Message {
 	mi := &file_jobmanager_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -313,60 +646,631 @@ func (x *JobStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobStatus.ProtoReflect.Descriptor instead.
-func (*JobStatus) Descriptor() ([]byte, []int) {
+// Deprecated: Use CgroupKnob.ProtoReflect.Descriptor instead.
+func (*CgroupKnob) Descriptor() ([]byte, []int) {
 	return file_jobmanager_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *JobStatus) GetJob() *Job {
+func (x *CgroupKnob) GetFile() string {
 	if x != nil {
-		return x.Job
+		return x.File
 	}
-	return nil
+	return ""
 }
 
-func (x *JobStatus) GetOwner() string {
+func (x *CgroupKnob) GetValue() string {
 	if x != nil {
-		return x.Owner
+		return x.Value
 	}
 	return ""
 }
 
-func (x *JobStatus) GetIsRunning() bool {
-	if x != nil {
-		return x.IsRunning
-	}
-	return false
-}
+// A JobID is a message that client use to uniquely identify a job
+// managed by the JobManager.
+type JobID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (x *JobStatus) GetPid() int32 {
-	if x != nil {
-		return x.Pid
-	}
-	return 0
+	// A server-assigned UUIDv4 for the job
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 }
 
-func (x *JobStatus) GetSignalNumber() int32 {
-	if x != nil {
-		return x.SignalNumber
+func (x *JobID) Reset() {
+	*x = JobID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobmanager_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JobID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobID) ProtoMessage() {}
+
+func (x *JobID) ProtoReflect() protoreflect.Message {
+	mi := &file_jobmanager_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobID.ProtoReflect.Descriptor instead.
+func (*JobID) Descriptor() ([]byte, []int) {
+	return file_jobmanager_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *JobID) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// A Job is a message that uniquely identifies one of the Jobs managed
+// by the JobManager service.
+type Job struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The server-assigned ID
+	Id *JobID `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The client-specified name for the job
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *Job) Reset() {
+	*x = Job{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobmanager_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Job) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Job) ProtoMessage() {}
+
+func (x *Job) ProtoReflect() protoreflect.Message {
+	mi := &file_jobmanager_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Job.ProtoReflect.Descriptor instead.
+func (*Job) Descriptor() ([]byte, []int) {
+	return file_jobmanager_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Job) GetId() *JobID {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *Job) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// The JobStatus message is used to communicate the status of a job.
+type JobStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The Job with which this status is associated
+	Job *Job `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	// The user who started the job
+	Owner string `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	// Is the job running?
+	IsRunning bool `protobuf:"varint,3,opt,name=isRunning,proto3" json:"isRunning,omitempty"`
+	// The process ID of the job
+	Pid int32 `protobuf:"varint,4,opt,name=pid,proto3" json:"pid,omitempty"`
+	// If the job is not running and was terminated by a signal, which
+	// signal?  If the process is terminated as a result of the Stop
+	// API, then this will indicate the KILL signal (9).  If the
+	// process was terminated by an extraneous signal, then this field
+	// will indicate the signal number that was received.
+	SignalNumber int32 `protobuf:"varint,5,opt,name=signalNumber,proto3" json:"signalNumber,omitempty"`
+	// If the job is not running, what was its exit code?
+	ExitCode int32 `protobuf:"varint,6,opt,name=exitCode,proto3" json:"exitCode,omitempty"`
+	// If a job failed to start, what was the cause of the failure?
+	ErrorMessage string `protobuf:"bytes,7,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
+	// Did the job try to create more processes than its pids limit
+	// allows?
+	PidsLimitReached bool `protobuf:"varint,8,opt,name=pidsLimitReached,proto3" json:"pidsLimitReached,omitempty"`
+	// If the job is not running, why did it terminate?
+	TerminationReason TerminationReason `protobuf:"varint,9,opt,name=terminationReason,proto3,enum=jobmanager.v1.TerminationReason" json:"terminationReason,omitempty"`
+	// The number of the job's processes that the OOM killer has killed
+	OomKills uint64 `protobuf:"varint,10,opt,name=oomKills,proto3" json:"oomKills,omitempty"`
+	// The maximum memory usage of the job, in bytes
+	PeakMemoryUsage uint64 `protobuf:"varint,11,opt,name=peakMemoryUsage,proto3" json:"peakMemoryUsage,omitempty"`
+	// If the job is not running, the resources that it consumed
+	Usage *ResourceUsage `protobuf:"bytes,12,opt,name=usage,proto3" json:"usage,omitempty"`
+	// The stage of its lifecycle that the job has reached
+	State JobState `protobuf:"varint,13,opt,name=state,proto3,enum=jobmanager.v1.JobState" json:"state,omitempty"`
+}
+
+func (x *JobStatus) Reset() {
+	*x = JobStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobmanager_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JobStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStatus) ProtoMessage() {}
+
+func (x *JobStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_jobmanager_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStatus.ProtoReflect.Descriptor instead.
+func (*JobStatus) Descriptor() ([]byte, []int) {
+	return file_jobmanager_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *JobStatus) GetJob() *Job {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+func (x *JobStatus) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *JobStatus) GetIsRunning() bool {
+	if x != nil {
+		return x.IsRunning
+	}
+	return false
+}
+
+func (x *JobStatus) GetPid() int32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *JobStatus) GetSignalNumber() int32 {
+	if x != nil {
+		return x.SignalNumber
+	}
+	return 0
+}
+
+func (x *JobStatus) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *JobStatus) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *JobStatus) GetPidsLimitReached() bool {
+	if x != nil {
+		return x.PidsLimitReached
+	}
+	return false
+}
+
+func (x *JobStatus) GetTerminationReason() TerminationReason {
+	if x != nil {
+		return x.TerminationReason
+	}
+	return TerminationReason_TerminationReason_NONE
+}
+
+func (x *JobStatus) GetOomKills() uint64 {
+	if x != nil {
+		return x.OomKills
+	}
+	return 0
+}
+
+func (x *JobStatus) GetPeakMemoryUsage() uint64 {
+	if x != nil {
+		return x.PeakMemoryUsage
+	}
+	return 0
+}
+
+func (x *JobStatus) GetUsage() *ResourceUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *JobStatus) GetState() JobState {
+	if x != nil {
+		return x.State
+	}
+	return JobState_JobState_CREATED
+}
+
+// The ResourceUsage message summarizes the resources that a job
+// consumed over its lifetime.
+type ResourceUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The CPU time spent in user mode, in nanoseconds
+	UserTimeNs uint64 `protobuf:"varint,1,opt,name=userTimeNs,proto3" json:"userTimeNs,omitempty"`
+	// The CPU time spent in kernel mode, in nanoseconds
+	SystemTimeNs uint64 `protobuf:"varint,2,opt,name=systemTimeNs,proto3" json:"systemTimeNs,omitempty"`
+	// The maximum memory usage of the job, in bytes
+	PeakMemoryUsage uint64 `protobuf:"varint,3,opt,name=peakMemoryUsage,proto3" json:"peakMemoryUsage,omitempty"`
+	// The number of bytes that the job read from block devices
+	IoReadBytes uint64 `protobuf:"varint,4,opt,name=ioReadBytes,proto3" json:"ioReadBytes,omitempty"`
+	// The number of bytes that the job wrote to block devices
+	IoWriteBytes uint64 `protobuf:"varint,5,opt,name=ioWriteBytes,proto3" json:"ioWriteBytes,omitempty"`
+	// The maximum number of processes and threads in the job at any
+	// one time, or zero if the server's kernel does not report it
+	MaxPids uint64 `protobuf:"varint,6,opt,name=maxPids,proto3" json:"maxPids,omitempty"`
+}
+
+func (x *ResourceUsage) Reset() {
+	*x = ResourceUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobmanager_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResourceUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourceUsage) ProtoMessage() {}
+
+func (x *ResourceUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_jobmanager_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourceUsage.ProtoReflect.Descriptor instead.
+func (*ResourceUsage) Descriptor() ([]byte, []int) {
+	return file_jobmanager_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ResourceUsage) GetUserTimeNs() uint64 {
+	if x != nil {
+		return x.UserTimeNs
+	}
+	return 0
+}
+
+func (x *ResourceUsage) GetSystemTimeNs() uint64 {
+	if x != nil {
+		return x.SystemTimeNs
+	}
+	return 0
+}
+
+func (x *ResourceUsage) GetPeakMemoryUsage() uint64 {
+	if x != nil {
+		return x.PeakMemoryUsage
+	}
+	return 0
+}
+
+func (x *ResourceUsage) GetIoReadBytes() uint64 {
+	if x != nil {
+		return x.IoReadBytes
+	}
+	return 0
+}
+
+func (x *ResourceUsage) GetIoWriteBytes() uint64 {
+	if x != nil {
+		return x.IoWriteBytes
+	}
+	return 0
+}
+
+func (x *ResourceUsage) GetMaxPids() uint64 {
+	if x != nil {
+		return x.MaxPids
+	}
+	return 0
+}
+
+// The JobStats message describes the resource usage of a running
+// job.  Usage that the job's cgroups do not account for is zero.
+type JobStats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The total CPU time consumed by the job, in nanoseconds
+	CpuUsageNs uint64 `protobuf:"varint,1,opt,name=cpuUsageNs,proto3" json:"cpuUsageNs,omitempty"`
+	// The current memory usage of the job, in bytes
+	MemoryUsage uint64 `protobuf:"varint,2,opt,name=memoryUsage,proto3" json:"memoryUsage,omitempty"`
+	// The maximum memory usage of the job, in bytes
+	PeakMemoryUsage uint64 `protobuf:"varint,3,opt,name=peakMemoryUsage,proto3" json:"peakMemoryUsage,omitempty"`
+	// The number of bytes that the job has read from block devices
+	IoReadBytes uint64 `protobuf:"varint,4,opt,name=ioReadBytes,proto3" json:"ioReadBytes,omitempty"`
+	// The number of bytes that the job has written to block devices
+	IoWriteBytes uint64 `protobuf:"varint,5,opt,name=ioWriteBytes,proto3" json:"ioWriteBytes,omitempty"`
+	// The current number of processes and threads in the job
+	Pids uint64 `protobuf:"varint,6,opt,name=pids,proto3" json:"pids,omitempty"`
+}
+
+func (x *JobStats) Reset() {
+	*x = JobStats{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobmanager_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JobStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStats) ProtoMessage() {}
+
+func (x *JobStats) ProtoReflect() protoreflect.Message {
+	mi := &file_jobmanager_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStats.ProtoReflect.Descriptor instead.
+func (*JobStats) Descriptor() ([]byte, []int) {
+	return file_jobmanager_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *JobStats) GetCpuUsageNs() uint64 {
+	if x != nil {
+		return x.CpuUsageNs
+	}
+	return 0
+}
+
+func (x *JobStats) GetMemoryUsage() uint64 {
+	if x != nil {
+		return x.MemoryUsage
 	}
 	return 0
 }
 
-func (x *JobStatus) GetExitCode() int32 {
+func (x *JobStats) GetPeakMemoryUsage() uint64 {
 	if x != nil {
-		return x.ExitCode
+		return x.PeakMemoryUsage
 	}
 	return 0
 }
 
-func (x *JobStatus) GetErrorMessage() string {
+func (x *JobStats) GetIoReadBytes() uint64 {
 	if x != nil {
-		return x.ErrorMessage
+		return x.IoReadBytes
+	}
+	return 0
+}
+
+func (x *JobStats) GetIoWriteBytes() uint64 {
+	if x != nil {
+		return x.IoWriteBytes
+	}
+	return 0
+}
+
+func (x *JobStats) GetPids() uint64 {
+	if x != nil {
+		return x.Pids
+	}
+	return 0
+}
+
+// The Process message describes a process in a job's cgroups.
+type Process struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The ID of the process in the server's PID namespace
+	Pid int32 `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
+	// The ID of the process's parent in the server's PID namespace
+	Ppid int32 `protobuf:"varint,2,opt,name=ppid,proto3" json:"ppid,omitempty"`
+	// The process's command line.  For processes without one (e.g.,
+	// zombies), the process's name in brackets.
+	Command []string `protobuf:"bytes,3,rep,name=command,proto3" json:"command,omitempty"`
+	// The process's state code (e.g., "R" for running); see proc(5)
+	State string `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"`
+	// The process's resident set size, in bytes
+	Rss uint64 `protobuf:"varint,5,opt,name=rss,proto3" json:"rss,omitempty"`
+	// The user and system CPU time consumed by the process, in
+	// nanoseconds
+	CpuTimeNs uint64 `protobuf:"varint,6,opt,name=cpuTimeNs,proto3" json:"cpuTimeNs,omitempty"`
+}
+
+func (x *Process) Reset() {
+	*x = Process{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobmanager_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Process) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Process) ProtoMessage() {}
+
+func (x *Process) ProtoReflect() protoreflect.Message {
+	mi := &file_jobmanager_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Process.ProtoReflect.Descriptor instead.
+func (*Process) Descriptor() ([]byte, []int) {
+	return file_jobmanager_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Process) GetPid() int32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *Process) GetPpid() int32 {
+	if x != nil {
+		return x.Ppid
+	}
+	return 0
+}
+
+func (x *Process) GetCommand() []string {
+	if x != nil {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *Process) GetState() string {
+	if x != nil {
+		return x.State
 	}
 	return ""
 }
 
+func (x *Process) GetRss() uint64 {
+	if x != nil {
+		return x.Rss
+	}
+	return 0
+}
+
+func (x *Process) GetCpuTimeNs() uint64 {
+	if x != nil {
+		return x.CpuTimeNs
+	}
+	return 0
+}
+
+// The ProcessList message contains the processes in a job's cgroups.
+type ProcessList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Processes []*Process `protobuf:"bytes,1,rep,name=processes,proto3" json:"processes,omitempty"`
+}
+
+func (x *ProcessList) Reset() {
+	*x = ProcessList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobmanager_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProcessList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessList) ProtoMessage() {}
+
+func (x *ProcessList) ProtoReflect() protoreflect.Message {
+	mi := &file_jobmanager_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessList.ProtoReflect.Descriptor instead.
+func (*ProcessList) Descriptor() ([]byte, []int) {
+	return file_jobmanager_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ProcessList) GetProcesses() []*Process {
+	if x != nil {
+		return x.Processes
+	}
+	return nil
+}
+
 // The JobOutput message is used to stream the output of the command.
 // This message can be enhanced in the future to include information
 // about the byte offset into the output if this information would
@@ -376,28 +1280,203 @@ type JobOutput struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// An array of bytes corresponding to the next “chunk” of command
-	// output (either stdout or stderr).
-	Output []byte `protobuf:"bytes,1,opt,name=output,proto3" json:"output,omitempty"`
+	// An array of bytes corresponding to the next “chunk” of command
+	// output (either stdout or stderr).
+	Output []byte `protobuf:"bytes,1,opt,name=output,proto3" json:"output,omitempty"`
+	// If non-zero, the number of bytes of output that the server
+	// discarded before they could be streamed (see the server's ring
+	// output buffer).  A message that sets truncatedBytes carries no
+	// output; the output that follows it resumes after the discarded
+	// bytes.
+	TruncatedBytes uint64 `protobuf:"varint,2,opt,name=truncatedBytes,proto3" json:"truncatedBytes,omitempty"`
+}
+
+func (x *JobOutput) Reset() {
+	*x = JobOutput{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobmanager_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JobOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobOutput) ProtoMessage() {}
+
+func (x *JobOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_jobmanager_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobOutput.ProtoReflect.Descriptor instead.
+func (*JobOutput) Descriptor() ([]byte, []int) {
+	return file_jobmanager_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *JobOutput) GetOutput() []byte {
+	if x != nil {
+		return x.Output
+	}
+	return nil
+}
+
+func (x *JobOutput) GetTruncatedBytes() uint64 {
+	if x != nil {
+		return x.TruncatedBytes
+	}
+	return 0
+}
+
+// The JobStatusList message is used to communicate the list of jobs
+// managed by the JobManager and their status.
+type JobStatusList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobStatusList []*JobStatus `protobuf:"bytes,1,rep,name=jobStatusList,proto3" json:"jobStatusList,omitempty"`
+}
+
+func (x *JobStatusList) Reset() {
+	*x = JobStatusList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobmanager_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JobStatusList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStatusList) ProtoMessage() {}
+
+func (x *JobStatusList) ProtoReflect() protoreflect.Message {
+	mi := &file_jobmanager_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStatusList.ProtoReflect.Descriptor instead.
+func (*JobStatusList) Descriptor() ([]byte, []int) {
+	return file_jobmanager_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *JobStatusList) GetJobStatusList() []*JobStatus {
+	if x != nil {
+		return x.JobStatusList
+	}
+	return nil
+}
+
+// The StreamOutputRequest message is used to request that the service
+// stream the output of the job.
+type StreamOutputRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The server-assigned ID
+	JobID *JobID `protobuf:"bytes,1,opt,name=jobID,proto3" json:"jobID,omitempty"`
+	// The output stream to return
+	OutputStream OutputStream `protobuf:"varint,2,opt,name=outputStream,proto3,enum=jobmanager.v1.OutputStream" json:"outputStream,omitempty"`
+}
+
+func (x *StreamOutputRequest) Reset() {
+	*x = StreamOutputRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobmanager_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamOutputRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamOutputRequest) ProtoMessage() {}
+
+func (x *StreamOutputRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jobmanager_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamOutputRequest.ProtoReflect.Descriptor instead.
+func (*StreamOutputRequest) Descriptor() ([]byte, []int) {
+	return file_jobmanager_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StreamOutputRequest) GetJobID() *JobID {
+	if x != nil {
+		return x.JobID
+	}
+	return nil
+}
+
+func (x *StreamOutputRequest) GetOutputStream() OutputStream {
+	if x != nil {
+		return x.OutputStream
+	}
+	return OutputStream_OutputStream_UNSPECIFIED
+}
+
+// The ExecRequest message is used to request that the service run an
+// additional process in a running job.
+type ExecRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The server-assigned ID of the job
+	JobID *JobID `protobuf:"bytes,1,opt,name=jobID,proto3" json:"jobID,omitempty"`
+	// The path of the program to run
+	ProgramPath string `protobuf:"bytes,2,opt,name=programPath,proto3" json:"programPath,omitempty"`
+	// Arguments to pass to the the program
+	Arguments []string `protobuf:"bytes,3,rep,name=arguments,proto3" json:"arguments,omitempty"`
 }
 
-func (x *JobOutput) Reset() {
-	*x = JobOutput{}
+func (x *ExecRequest) Reset() {
+	*x = ExecRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_jobmanager_proto_msgTypes[4]
+		mi := &file_jobmanager_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *JobOutput) String() string {
+func (x *ExecRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobOutput) ProtoMessage() {}
+func (*ExecRequest) ProtoMessage() {}
 
-func (x *JobOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_jobmanager_proto_msgTypes[4]
+func (x *ExecRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jobmanager_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -408,45 +1487,68 @@ func (x *JobOutput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobOutput.ProtoReflect.Descriptor instead.
-func (*JobOutput) Descriptor() ([]byte, []int) {
-	return file_jobmanager_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use ExecRequest.ProtoReflect.Descriptor instead.
+func (*ExecRequest) Descriptor() ([]byte, []int) {
+	return file_jobmanager_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *JobOutput) GetOutput() []byte {
+func (x *ExecRequest) GetJobID() *JobID {
 	if x != nil {
-		return x.Output
+		return x.JobID
 	}
 	return nil
 }
 
-// The JobStatusList message is used to communicate the list of jobs
-// managed by the JobManager and their status.
-type JobStatusList struct {
+func (x *ExecRequest) GetProgramPath() string {
+	if x != nil {
+		return x.ProgramPath
+	}
+	return ""
+}
+
+func (x *ExecRequest) GetArguments() []string {
+	if x != nil {
+		return x.Arguments
+	}
+	return nil
+}
+
+// The ExecOutput message is used to stream the output of a process
+// started by Exec, followed by its exit status.
+type ExecOutput struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	JobStatusList []*JobStatus `protobuf:"bytes,1,rep,name=jobStatusList,proto3" json:"jobStatusList,omitempty"`
+	// The stream from which output was read
+	OutputStream OutputStream `protobuf:"varint,1,opt,name=outputStream,proto3,enum=jobmanager.v1.OutputStream" json:"outputStream,omitempty"`
+	// The next “chunk” of that stream's output
+	Output []byte `protobuf:"bytes,2,opt,name=output,proto3" json:"output,omitempty"`
+	// Set only in the final message, once the process has terminated
+	ExitStatus *ExecExitStatus `protobuf:"bytes,3,opt,name=exitStatus,proto3" json:"exitStatus,omitempty"`
+	// If non-zero, the number of bytes of that stream's output that the
+	// server discarded before they could be streamed.  A message that
+	// sets truncatedBytes carries no output.
+	TruncatedBytes uint64 `protobuf:"varint,4,opt,name=truncatedBytes,proto3" json:"truncatedBytes,omitempty"`
 }
 
-func (x *JobStatusList) Reset() {
-	*x = JobStatusList{}
+func (x *ExecOutput) Reset() {
+	*x = ExecOutput{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_jobmanager_proto_msgTypes[5]
+		mi := &file_jobmanager_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *JobStatusList) String() string {
+func (x *ExecOutput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobStatusList) ProtoMessage() {}
+func (*ExecOutput) ProtoMessage() {}
 
-func (x *JobStatusList) ProtoReflect() protoreflect.Message {
-	mi := &file_jobmanager_proto_msgTypes[5]
+func (x *ExecOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_jobmanager_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -457,48 +1559,70 @@ func (x *JobStatusList) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobStatusList.ProtoReflect.Descriptor instead.
-func (*JobStatusList) Descriptor() ([]byte, []int) {
-	return file_jobmanager_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use ExecOutput.ProtoReflect.Descriptor instead.
+func (*ExecOutput) Descriptor() ([]byte, []int) {
+	return file_jobmanager_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *JobStatusList) GetJobStatusList() []*JobStatus {
+func (x *ExecOutput) GetOutputStream() OutputStream {
 	if x != nil {
-		return x.JobStatusList
+		return x.OutputStream
+	}
+	return OutputStream_OutputStream_UNSPECIFIED
+}
+
+func (x *ExecOutput) GetOutput() []byte {
+	if x != nil {
+		return x.Output
 	}
 	return nil
 }
 
-// The StreamOutputRequest message is used to request that the service
-// stream the output of the job.
-type StreamOutputRequest struct {
+func (x *ExecOutput) GetExitStatus() *ExecExitStatus {
+	if x != nil {
+		return x.ExitStatus
+	}
+	return nil
+}
+
+func (x *ExecOutput) GetTruncatedBytes() uint64 {
+	if x != nil {
+		return x.TruncatedBytes
+	}
+	return 0
+}
+
+// The ExecExitStatus message describes how a process started by Exec
+// terminated.
+type ExecExitStatus struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The server-assigned ID
-	JobID *JobID `protobuf:"bytes,1,opt,name=jobID,proto3" json:"jobID,omitempty"`
-	// The output stream to return
-	OutputStream OutputStream `protobuf:"varint,2,opt,name=outputStream,proto3,enum=jobmanager.v1.OutputStream" json:"outputStream,omitempty"`
+	// The exit status of the process, or 128 plus the number of the
+	// signal that killed it
+	ExitCode int32 `protobuf:"varint,1,opt,name=exitCode,proto3" json:"exitCode,omitempty"`
+	// If the process could not be run, what was the cause?
+	ErrorMessage string `protobuf:"bytes,2,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
 }
 
-func (x *StreamOutputRequest) Reset() {
-	*x = StreamOutputRequest{}
+func (x *ExecExitStatus) Reset() {
+	*x = ExecExitStatus{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_jobmanager_proto_msgTypes[6]
+		mi := &file_jobmanager_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *StreamOutputRequest) String() string {
+func (x *ExecExitStatus) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StreamOutputRequest) ProtoMessage() {}
+func (*ExecExitStatus) ProtoMessage() {}
 
-func (x *StreamOutputRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_jobmanager_proto_msgTypes[6]
+func (x *ExecExitStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_jobmanager_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -509,23 +1633,23 @@ func (x *StreamOutputRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StreamOutputRequest.ProtoReflect.Descriptor instead.
-func (*StreamOutputRequest) Descriptor() ([]byte, []int) {
-	return file_jobmanager_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use ExecExitStatus.ProtoReflect.Descriptor instead.
+func (*ExecExitStatus) Descriptor() ([]byte, []int) {
+	return file_jobmanager_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *StreamOutputRequest) GetJobID() *JobID {
+func (x *ExecExitStatus) GetExitCode() int32 {
 	if x != nil {
-		return x.JobID
+		return x.ExitCode
 	}
-	return nil
+	return 0
 }
 
-func (x *StreamOutputRequest) GetOutputStream() OutputStream {
+func (x *ExecExitStatus) GetErrorMessage() string {
 	if x != nil {
-		return x.OutputStream
+		return x.ErrorMessage
 	}
-	return OutputStream_OutputStream_UNSPECIFIED
+	return ""
 }
 
 // The NilMessage message is used when no other message is needed.
@@ -538,7 +1662,7 @@ type NilMessage struct {
 func (x *NilMessage) Reset() {
 	*x = NilMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_jobmanager_proto_msgTypes[7]
+		mi := &file_jobmanager_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -551,7 +1675,7 @@ func (x *NilMessage) String() string {
 func (*NilMessage) ProtoMessage() {}
 
 func (x *NilMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_jobmanager_proto_msgTypes[7]
+	mi := &file_jobmanager_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -564,7 +1688,69 @@ func (x *NilMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NilMessage.ProtoReflect.Descriptor instead.
 func (*NilMessage) Descriptor() ([]byte, []int) {
-	return file_jobmanager_proto_rawDescGZIP(), []int{7}
+	return file_jobmanager_proto_rawDescGZIP(), []int{17}
+}
+
+// The ServerInfo message describes the server and the resource
+// enforcement that it applies to jobs.
+type ServerInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The version of the cgroup interface used to enforce job limits
+	// (e.g., "v1" or "v2")
+	CgroupVersion string `protobuf:"bytes,1,opt,name=cgroupVersion,proto3" json:"cgroupVersion,omitempty"`
+	// The names of the cgroup controllers that are enforcing limits on
+	// every job (e.g., "cpu", "memory").  Controllers that are not
+	// available on the server's host are omitted.
+	Capabilities []string `protobuf:"bytes,2,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+}
+
+func (x *ServerInfo) Reset() {
+	*x = ServerInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobmanager_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServerInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerInfo) ProtoMessage() {}
+
+func (x *ServerInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_jobmanager_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerInfo.ProtoReflect.Descriptor instead.
+func (*ServerInfo) Descriptor() ([]byte, []int) {
+	return file_jobmanager_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ServerInfo) GetCgroupVersion() string {
+	if x != nil {
+		return x.CgroupVersion
+	}
+	return ""
+}
+
+func (x *ServerInfo) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
 }
 
 var File_jobmanager_proto protoreflect.FileDescriptor
@@ -572,82 +1758,275 @@ var File_jobmanager_proto protoreflect.FileDescriptor
 var file_jobmanager_proto_rawDesc = []byte{
 	0x0a, 0x10, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f,
 	0x74, 0x6f, 0x12, 0x0d, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76,
-	0x31, 0x22, 0x68, 0x0a, 0x12, 0x4a, 0x6f, 0x62, 0x43, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x70,
-	0x72, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x50, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0b, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x50, 0x61, 0x74, 0x68, 0x12, 0x1c, 0x0a,
-	0x09, 0x61, 0x72, 0x67, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09,
-	0x52, 0x09, 0x61, 0x72, 0x67, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x17, 0x0a, 0x05, 0x4a,
-	0x6f, 0x62, 0x49, 0x44, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x02, 0x69, 0x64, 0x22, 0x3f, 0x0a, 0x03, 0x4a, 0x6f, 0x62, 0x12, 0x24, 0x0a, 0x02, 0x69,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e,
-	0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x49, 0x44, 0x52, 0x02, 0x69,
-	0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0xdb, 0x01, 0x0a, 0x09, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x12, 0x24, 0x0a, 0x03, 0x6a, 0x6f, 0x62, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x12, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31,
-	0x2e, 0x4a, 0x6f, 0x62, 0x52, 0x03, 0x6a, 0x6f, 0x62, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x77, 0x6e,
-	0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x12,
-	0x1c, 0x0a, 0x09, 0x69, 0x73, 0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x09, 0x69, 0x73, 0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x10, 0x0a,
-	0x03, 0x70, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x70, 0x69, 0x64, 0x12,
-	0x22, 0x0a, 0x0c, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x4e, 0x75, 0x6d,
-	0x62, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x78, 0x69, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x18,
-	0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x65, 0x78, 0x69, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x12,
-	0x22, 0x0a, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18,
-	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x22, 0x23, 0x0a, 0x09, 0x4a, 0x6f, 0x62, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74,
-	0x12, 0x16, 0x0a, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0x4f, 0x0a, 0x0d, 0x4a, 0x6f, 0x62, 0x53,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x3e, 0x0a, 0x0d, 0x6a, 0x6f, 0x62,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x18, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31,
-	0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x0d, 0x6a, 0x6f, 0x62, 0x53,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x82, 0x01, 0x0a, 0x13, 0x53, 0x74,
-	0x72, 0x65, 0x61, 0x6d, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x2a, 0x0a, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x14, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31,
-	0x2e, 0x4a, 0x6f, 0x62, 0x49, 0x44, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x44, 0x12, 0x3f, 0x0a,
-	0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x18, 0x02, 0x20,
+	0x31, 0x22, 0x9f, 0x01, 0x0a, 0x12, 0x4a, 0x6f, 0x62, 0x43, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b,
+	0x70, 0x72, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x50, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x50, 0x61, 0x74, 0x68, 0x12, 0x1c,
+	0x0a, 0x09, 0x61, 0x72, 0x67, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x09, 0x61, 0x72, 0x67, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x35, 0x0a, 0x06,
+	0x6c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6a,
+	0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x06, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x73, 0x22, 0xfa, 0x03, 0x0a, 0x0e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x12, 0x35, 0x0a, 0x07, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x49,
+	0x4f, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e,
+	0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x4f, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x52, 0x07, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x4f, 0x12, 0x18, 0x0a,
+	0x07, 0x6d, 0x61, 0x78, 0x50, 0x69, 0x64, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07,
+	0x6d, 0x61, 0x78, 0x50, 0x69, 0x64, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x70, 0x75, 0x73, 0x65,
+	0x74, 0x43, 0x70, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x70, 0x75,
+	0x73, 0x65, 0x74, 0x43, 0x70, 0x75, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x70, 0x75, 0x73, 0x65,
+	0x74, 0x4d, 0x65, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x70, 0x75,
+	0x73, 0x65, 0x74, 0x4d, 0x65, 0x6d, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x70, 0x75, 0x73, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x63, 0x70, 0x75, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x75,
+	0x6e, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x65, 0x64, 0x43, 0x70, 0x75, 0x73, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0d, 0x75, 0x6e, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x65, 0x64, 0x43, 0x70, 0x75,
+	0x73, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x70, 0x75, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x63, 0x70, 0x75, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12,
+	0x20, 0x0a, 0x0b, 0x63, 0x70, 0x75, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x55, 0x73, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x63, 0x70, 0x75, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x55,
+	0x73, 0x12, 0x20, 0x0a, 0x0b, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x4c, 0x69,
+	0x6d, 0x69, 0x74, 0x12, 0x28, 0x0a, 0x0f, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x53, 0x77, 0x61,
+	0x70, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x6d, 0x65,
+	0x6d, 0x6f, 0x72, 0x79, 0x53, 0x77, 0x61, 0x70, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x28, 0x0a,
+	0x0f, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x53, 0x6f, 0x66, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x53, 0x6f,
+	0x66, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x36, 0x0a, 0x09, 0x6f, 0x6f, 0x6d, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x6a, 0x6f, 0x62,
+	0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x6f, 0x6d, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x52, 0x09, 0x6f, 0x6f, 0x6d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12,
+	0x2f, 0x0a, 0x05, 0x6b, 0x6e, 0x6f, 0x62, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43,
+	0x67, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x6e, 0x6f, 0x62, 0x52, 0x05, 0x6b, 0x6e, 0x6f, 0x62, 0x73,
+	0x22, 0x9e, 0x01, 0x0a, 0x0c, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x4f, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x61, 0x64, 0x42, 0x70, 0x73,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x72, 0x65, 0x61, 0x64, 0x42, 0x70, 0x73, 0x12,
+	0x1a, 0x0a, 0x08, 0x77, 0x72, 0x69, 0x74, 0x65, 0x42, 0x70, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x77, 0x72, 0x69, 0x74, 0x65, 0x42, 0x70, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x72,
+	0x65, 0x61, 0x64, 0x49, 0x6f, 0x70, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x72,
+	0x65, 0x61, 0x64, 0x49, 0x6f, 0x70, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x77, 0x72, 0x69, 0x74, 0x65,
+	0x49, 0x6f, 0x70, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x77, 0x72, 0x69, 0x74,
+	0x65, 0x49, 0x6f, 0x70, 0x73, 0x4a, 0x04, 0x08, 0x02, 0x10, 0x03, 0x4a, 0x04, 0x08, 0x03, 0x10,
+	0x04, 0x22, 0x36, 0x0a, 0x0a, 0x43, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x6e, 0x6f, 0x62, 0x12,
+	0x12, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66,
+	0x69, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x17, 0x0a, 0x05, 0x4a, 0x6f, 0x62,
+	0x49, 0x44, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x22, 0x3f, 0x0a, 0x03, 0x4a, 0x6f, 0x62, 0x12, 0x24, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x49, 0x44, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x22, 0x80, 0x04, 0x0a, 0x09, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x24, 0x0a, 0x03, 0x6a, 0x6f, 0x62, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4a,
+	0x6f, 0x62, 0x52, 0x03, 0x6a, 0x6f, 0x62, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x1c, 0x0a,
+	0x09, 0x69, 0x73, 0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x09, 0x69, 0x73, 0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x10, 0x0a, 0x03, 0x70,
+	0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x70, 0x69, 0x64, 0x12, 0x22, 0x0a,
+	0x0c, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0c, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x4e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x78, 0x69, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x08, 0x65, 0x78, 0x69, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x22, 0x0a,
+	0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x2a, 0x0a, 0x10, 0x70, 0x69, 0x64, 0x73, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x65,
+	0x61, 0x63, 0x68, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x70, 0x69, 0x64,
+	0x73, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x61, 0x63, 0x68, 0x65, 0x64, 0x12, 0x4e, 0x0a,
+	0x11, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x20, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61,
+	0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52, 0x11, 0x74, 0x65, 0x72, 0x6d,
+	0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1a, 0x0a,
+	0x08, 0x6f, 0x6f, 0x6d, 0x4b, 0x69, 0x6c, 0x6c, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x08, 0x6f, 0x6f, 0x6d, 0x4b, 0x69, 0x6c, 0x6c, 0x73, 0x12, 0x28, 0x0a, 0x0f, 0x70, 0x65, 0x61,
+	0x6b, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0f, 0x70, 0x65, 0x61, 0x6b, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x55, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x32, 0x0a, 0x05, 0x75, 0x73, 0x61, 0x67, 0x65, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65,
+	0x52, 0x05, 0x75, 0x73, 0x61, 0x67, 0x65, 0x12, 0x2d, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x18, 0x0d, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61,
+	0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52,
+	0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0xdd, 0x01, 0x0a, 0x0d, 0x52, 0x65, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x75, 0x73, 0x65, 0x72,
+	0x54, 0x69, 0x6d, 0x65, 0x4e, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x75, 0x73,
+	0x65, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x4e, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x79, 0x73, 0x74,
+	0x65, 0x6d, 0x54, 0x69, 0x6d, 0x65, 0x4e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c,
+	0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x54, 0x69, 0x6d, 0x65, 0x4e, 0x73, 0x12, 0x28, 0x0a, 0x0f,
+	0x70, 0x65, 0x61, 0x6b, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x70, 0x65, 0x61, 0x6b, 0x4d, 0x65, 0x6d, 0x6f, 0x72,
+	0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x69, 0x6f, 0x52, 0x65, 0x61, 0x64,
+	0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x69, 0x6f, 0x52,
+	0x65, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x69, 0x6f, 0x57, 0x72,
+	0x69, 0x74, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c,
+	0x69, 0x6f, 0x57, 0x72, 0x69, 0x74, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x6d, 0x61, 0x78, 0x50, 0x69, 0x64, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6d,
+	0x61, 0x78, 0x50, 0x69, 0x64, 0x73, 0x22, 0xd0, 0x01, 0x0a, 0x08, 0x4a, 0x6f, 0x62, 0x53, 0x74,
+	0x61, 0x74, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x70, 0x75, 0x55, 0x73, 0x61, 0x67, 0x65, 0x4e,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x63, 0x70, 0x75, 0x55, 0x73, 0x61, 0x67,
+	0x65, 0x4e, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x55, 0x73, 0x61,
+	0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79,
+	0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x70, 0x65, 0x61, 0x6b, 0x4d, 0x65, 0x6d,
+	0x6f, 0x72, 0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f,
+	0x70, 0x65, 0x61, 0x6b, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x20, 0x0a, 0x0b, 0x69, 0x6f, 0x52, 0x65, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x69, 0x6f, 0x52, 0x65, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65,
+	0x73, 0x12, 0x22, 0x0a, 0x0c, 0x69, 0x6f, 0x57, 0x72, 0x69, 0x74, 0x65, 0x42, 0x79, 0x74, 0x65,
+	0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x69, 0x6f, 0x57, 0x72, 0x69, 0x74, 0x65,
+	0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x69, 0x64, 0x73, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x04, 0x70, 0x69, 0x64, 0x73, 0x22, 0x8f, 0x01, 0x0a, 0x07, 0x50, 0x72,
+	0x6f, 0x63, 0x65, 0x73, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x03, 0x70, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x70, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x70, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x63,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x72,
+	0x73, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x72, 0x73, 0x73, 0x12, 0x1c, 0x0a,
+	0x09, 0x63, 0x70, 0x75, 0x54, 0x69, 0x6d, 0x65, 0x4e, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x09, 0x63, 0x70, 0x75, 0x54, 0x69, 0x6d, 0x65, 0x4e, 0x73, 0x22, 0x43, 0x0a, 0x0b, 0x50,
+	0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x34, 0x0a, 0x09, 0x70, 0x72,
+	0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e,
+	0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72,
+	0x6f, 0x63, 0x65, 0x73, 0x73, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x73,
+	0x22, 0x4b, 0x0a, 0x09, 0x4a, 0x6f, 0x62, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x26, 0x0a, 0x0e, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74,
+	0x65, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x74,
+	0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x4f, 0x0a,
+	0x0d, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x3e,
+	0x0a, 0x0d, 0x6a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x0d, 0x6a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x82,
+	0x01, 0x0a, 0x13, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2a, 0x0a, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x44, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x49, 0x44, 0x52, 0x05, 0x6a, 0x6f, 0x62,
+	0x49, 0x44, 0x12, 0x3f, 0x0a, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61,
+	0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x22, 0x79, 0x0a, 0x0b, 0x45, 0x78, 0x65, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x2a, 0x0a, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x49, 0x44, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x44, 0x12, 0x20,
+	0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x50, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x50, 0x61, 0x74, 0x68,
+	0x12, 0x1c, 0x0a, 0x09, 0x61, 0x72, 0x67, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x09, 0x61, 0x72, 0x67, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x22, 0xcc,
+	0x01, 0x0a, 0x0a, 0x45, 0x78, 0x65, 0x63, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x3f, 0x0a,
+	0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x18, 0x01, 0x20,
 	0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72,
 	0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
-	0x52, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x22, 0x0c,
-	0x0a, 0x0a, 0x4e, 0x69, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2a, 0x5e, 0x0a, 0x0c,
-	0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x1c, 0x0a, 0x18,
-	0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x5f, 0x55, 0x4e, 0x53,
-	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x4f, 0x75,
-	0x74, 0x70, 0x75, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x5f, 0x53, 0x54, 0x44, 0x4f, 0x55,
-	0x54, 0x10, 0x01, 0x12, 0x17, 0x0a, 0x13, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x74, 0x72,
-	0x65, 0x61, 0x6d, 0x5f, 0x53, 0x54, 0x44, 0x45, 0x52, 0x52, 0x10, 0x02, 0x32, 0xd9, 0x02, 0x0a,
-	0x0a, 0x4a, 0x6f, 0x62, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x12, 0x40, 0x0a, 0x05, 0x53,
-	0x74, 0x61, 0x72, 0x74, 0x12, 0x21, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65,
-	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x43, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e,
-	0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x22, 0x00, 0x12, 0x39, 0x0a,
-	0x04, 0x53, 0x74, 0x6f, 0x70, 0x12, 0x14, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67,
-	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x49, 0x44, 0x1a, 0x19, 0x2e, 0x6a, 0x6f,
-	0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x69, 0x6c, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x05, 0x51, 0x75, 0x65, 0x72,
-	0x79, 0x12, 0x14, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76,
-	0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x49, 0x44, 0x1a, 0x18, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e,
-	0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x22, 0x00, 0x12, 0x41, 0x0a, 0x04, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x19, 0x2e, 0x6a, 0x6f,
-	0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x69, 0x6c, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x1c, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61,
+	0x52, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x16,
+	0x0a, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06,
+	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x3d, 0x0a, 0x0a, 0x65, 0x78, 0x69, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6a, 0x6f, 0x62,
+	0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x45,
+	0x78, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x0a, 0x65, 0x78, 0x69, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74,
+	0x65, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x74,
+	0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x50, 0x0a,
+	0x0e, 0x45, 0x78, 0x65, 0x63, 0x45, 0x78, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x1a, 0x0a, 0x08, 0x65, 0x78, 0x69, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x08, 0x65, 0x78, 0x69, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22,
+	0x0c, 0x0a, 0x0a, 0x4e, 0x69, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x56, 0x0a,
+	0x0a, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x24, 0x0a, 0x0d, 0x63,
+	0x67, 0x72, 0x6f, 0x75, 0x70, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x63, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c,
+	0x69, 0x74, 0x69, 0x65, 0x73, 0x2a, 0x4b, 0x0a, 0x09, 0x4f, 0x6f, 0x6d, 0x50, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x12, 0x15, 0x0a, 0x11, 0x4f, 0x6f, 0x6d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f,
+	0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x4f, 0x6f, 0x6d,
+	0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x4b, 0x49, 0x4c, 0x4c, 0x10, 0x01, 0x12, 0x13, 0x0a,
+	0x0f, 0x4f, 0x6f, 0x6d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x50, 0x41, 0x55, 0x53, 0x45,
+	0x10, 0x02, 0x2a, 0x4f, 0x0a, 0x08, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x14,
+	0x0a, 0x10, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x43, 0x52, 0x45, 0x41, 0x54,
+	0x45, 0x44, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x5f, 0x52, 0x55, 0x4e, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x17, 0x0a, 0x13, 0x4a, 0x6f,
+	0x62, 0x53, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x54, 0x45, 0x52, 0x4d, 0x49, 0x4e, 0x41, 0x54, 0x45,
+	0x44, 0x10, 0x02, 0x2a, 0xd8, 0x01, 0x0a, 0x11, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x16, 0x54, 0x65, 0x72,
+	0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x5f, 0x4e,
+	0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x1c, 0x0a, 0x18, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x5f, 0x45, 0x58, 0x49, 0x54, 0x45,
+	0x44, 0x10, 0x01, 0x12, 0x1e, 0x0a, 0x1a, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x5f, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x4c, 0x45,
+	0x44, 0x10, 0x02, 0x12, 0x1d, 0x0a, 0x19, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x5f, 0x53, 0x54, 0x4f, 0x50, 0x50, 0x45, 0x44,
+	0x10, 0x03, 0x12, 0x23, 0x0a, 0x1f, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x5f, 0x4f, 0x55, 0x54, 0x5f, 0x4f, 0x46, 0x5f, 0x4d,
+	0x45, 0x4d, 0x4f, 0x52, 0x59, 0x10, 0x04, 0x12, 0x25, 0x0a, 0x21, 0x54, 0x65, 0x72, 0x6d, 0x69,
+	0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x5f, 0x46, 0x41, 0x49,
+	0x4c, 0x45, 0x44, 0x5f, 0x54, 0x4f, 0x5f, 0x53, 0x54, 0x41, 0x52, 0x54, 0x10, 0x05, 0x2a, 0x5e,
+	0x0a, 0x0c, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x1c,
+	0x0a, 0x18, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x5f, 0x55,
+	0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x17, 0x0a, 0x13,
+	0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x5f, 0x53, 0x54, 0x44,
+	0x4f, 0x55, 0x54, 0x10, 0x01, 0x12, 0x17, 0x0a, 0x13, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x5f, 0x53, 0x54, 0x44, 0x45, 0x52, 0x52, 0x10, 0x02, 0x32, 0x94,
+	0x06, 0x0a, 0x0a, 0x4a, 0x6f, 0x62, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x12, 0x40, 0x0a,
+	0x05, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x21, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61,
+	0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x43, 0x72, 0x65, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x6a, 0x6f, 0x62, 0x6d,
+	0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x22, 0x00, 0x12,
+	0x41, 0x0a, 0x06, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x21, 0x2e, 0x6a, 0x6f, 0x62, 0x6d,
+	0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x6a,
+	0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62,
+	0x22, 0x00, 0x12, 0x3b, 0x0a, 0x06, 0x4c, 0x61, 0x75, 0x6e, 0x63, 0x68, 0x12, 0x14, 0x2e, 0x6a,
+	0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62,
+	0x49, 0x44, 0x1a, 0x19, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x4e, 0x69, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00, 0x12,
+	0x39, 0x0a, 0x04, 0x53, 0x74, 0x6f, 0x70, 0x12, 0x14, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e,
+	0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x49, 0x44, 0x1a, 0x19, 0x2e,
+	0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x69,
+	0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00, 0x12, 0x3b, 0x0a, 0x06, 0x52, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x12, 0x14, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x49, 0x44, 0x1a, 0x19, 0x2e, 0x6a, 0x6f, 0x62,
+	0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x69, 0x6c, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x05, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x12, 0x14, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x4a, 0x6f, 0x62, 0x49, 0x44, 0x1a, 0x18, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61,
 	0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x4c, 0x69, 0x73, 0x74, 0x22, 0x00, 0x12, 0x50, 0x0a, 0x0c, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
-	0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x22, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61,
-	0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4f, 0x75, 0x74,
-	0x70, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x6a, 0x6f, 0x62,
-	0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x4f, 0x75,
-	0x74, 0x70, 0x75, 0x74, 0x22, 0x00, 0x30, 0x01, 0x42, 0x46, 0x5a, 0x44, 0x67, 0x69, 0x74, 0x68,
-	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x64, 0x61, 0x6c, 0x74, 0x6f, 0x6e, 0x2f, 0x74,
-	0x65, 0x6c, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x2d, 0x65, 0x78, 0x65, 0x72, 0x63, 0x69, 0x73, 0x65,
-	0x2f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61,
-	0x67, 0x65, 0x72, 0x2f, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x76, 0x31,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x22, 0x00, 0x12, 0x38, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x14, 0x2e, 0x6a, 0x6f,
+	0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x49,
+	0x44, 0x1a, 0x17, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x73, 0x22, 0x00, 0x12, 0x3f, 0x0a, 0x09,
+	0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x14, 0x2e, 0x6a, 0x6f, 0x62, 0x6d,
+	0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x49, 0x44, 0x1a,
+	0x1a, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x00, 0x12, 0x41, 0x0a,
+	0x04, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x19, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x69, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x1a, 0x1c, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x00,
+	0x12, 0x41, 0x0a, 0x04, 0x45, 0x78, 0x65, 0x63, 0x12, 0x1a, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61,
+	0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22,
+	0x00, 0x30, 0x01, 0x12, 0x50, 0x0a, 0x0c, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x12, 0x22, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e,
+	0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4a, 0x6f, 0x62, 0x4f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x22, 0x00, 0x30, 0x01, 0x12, 0x3e, 0x0a, 0x04, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x19, 0x2e,
+	0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x69,
+	0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x19, 0x2e, 0x6a, 0x6f, 0x62, 0x6d, 0x61,
+	0x6e, 0x61, 0x67, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x49,
+	0x6e, 0x66, 0x6f, 0x22, 0x00, 0x42, 0x46, 0x5a, 0x44, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x64, 0x61, 0x6c, 0x74, 0x6f, 0x6e, 0x2f, 0x74, 0x65, 0x6c, 0x65,
+	0x70, 0x6f, 0x72, 0x74, 0x2d, 0x65, 0x78, 0x65, 0x72, 0x63, 0x69, 0x73, 0x65, 0x2f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72,
+	0x2f, 0x6a, 0x6f, 0x62, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x76, 0x31, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -662,40 +2041,79 @@ func file_jobmanager_proto_rawDescGZIP() []byte {
 	return file_jobmanager_proto_rawDescData
 }
 
-var file_jobmanager_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_jobmanager_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_jobmanager_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_jobmanager_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
 var file_jobmanager_proto_goTypes = []interface{}{
-	(OutputStream)(0),           // 0: jobmanager.v1.OutputStream
-	(*JobCreationRequest)(nil),  // 1: jobmanager.v1.JobCreationRequest
-	(*JobID)(nil),               // 2: jobmanager.v1.JobID
-	(*Job)(nil),                 // 3: jobmanager.v1.Job
-	(*JobStatus)(nil),           // 4: jobmanager.v1.JobStatus
-	(*JobOutput)(nil),           // 5: jobmanager.v1.JobOutput
-	(*JobStatusList)(nil),       // 6: jobmanager.v1.JobStatusList
-	(*StreamOutputRequest)(nil), // 7: jobmanager.v1.StreamOutputRequest
-	(*NilMessage)(nil),          // 8: jobmanager.v1.NilMessage
+	(OomPolicy)(0),              // 0: jobmanager.v1.OomPolicy
+	(JobState)(0),               // 1: jobmanager.v1.JobState
+	(TerminationReason)(0),      // 2: jobmanager.v1.TerminationReason
+	(OutputStream)(0),           // 3: jobmanager.v1.OutputStream
+	(*JobCreationRequest)(nil),  // 4: jobmanager.v1.JobCreationRequest
+	(*ResourceLimits)(nil),      // 5: jobmanager.v1.ResourceLimits
+	(*BlockIOLimit)(nil),        // 6: jobmanager.v1.BlockIOLimit
+	(*CgroupKnob)(nil),          // 7: jobmanager.v1.CgroupKnob
+	(*JobID)(nil),               // 8: jobmanager.v1.JobID
+	(*Job)(nil),                 // 9: jobmanager.v1.Job
+	(*JobStatus)(nil),           // 10: jobmanager.v1.JobStatus
+	(*ResourceUsage)(nil),       // 11: jobmanager.v1.ResourceUsage
+	(*JobStats)(nil),            // 12: jobmanager.v1.JobStats
+	(*Process)(nil),             // 13: jobmanager.v1.Process
+	(*ProcessList)(nil),         // 14: jobmanager.v1.ProcessList
+	(*JobOutput)(nil),           // 15: jobmanager.v1.JobOutput
+	(*JobStatusList)(nil),       // 16: jobmanager.v1.JobStatusList
+	(*StreamOutputRequest)(nil), // 17: jobmanager.v1.StreamOutputRequest
+	(*ExecRequest)(nil),         // 18: jobmanager.v1.ExecRequest
+	(*ExecOutput)(nil),          // 19: jobmanager.v1.ExecOutput
+	(*ExecExitStatus)(nil),      // 20: jobmanager.v1.ExecExitStatus
+	(*NilMessage)(nil),          // 21: jobmanager.v1.NilMessage
+	(*ServerInfo)(nil),          // 22: jobmanager.v1.ServerInfo
 }
 var file_jobmanager_proto_depIdxs = []int32{
-	2,  // 0: jobmanager.v1.Job.id:type_name -> jobmanager.v1.JobID
-	3,  // 1: jobmanager.v1.JobStatus.job:type_name -> jobmanager.v1.Job
-	4,  // 2: jobmanager.v1.JobStatusList.jobStatusList:type_name -> jobmanager.v1.JobStatus
-	2,  // 3: jobmanager.v1.StreamOutputRequest.jobID:type_name -> jobmanager.v1.JobID
-	0,  // 4: jobmanager.v1.StreamOutputRequest.outputStream:type_name -> jobmanager.v1.OutputStream
-	1,  // 5: jobmanager.v1.JobManager.Start:input_type -> jobmanager.v1.JobCreationRequest
-	2,  // 6: jobmanager.v1.JobManager.Stop:input_type -> jobmanager.v1.JobID
-	2,  // 7: jobmanager.v1.JobManager.Query:input_type -> jobmanager.v1.JobID
-	8,  // 8: jobmanager.v1.JobManager.List:input_type -> jobmanager.v1.NilMessage
-	7,  // 9: jobmanager.v1.JobManager.StreamOutput:input_type -> jobmanager.v1.StreamOutputRequest
-	3,  // 10: jobmanager.v1.JobManager.Start:output_type -> jobmanager.v1.Job
-	8,  // 11: jobmanager.v1.JobManager.Stop:output_type -> jobmanager.v1.NilMessage
-	4,  // 12: jobmanager.v1.JobManager.Query:output_type -> jobmanager.v1.JobStatus
-	6,  // 13: jobmanager.v1.JobManager.List:output_type -> jobmanager.v1.JobStatusList
-	5,  // 14: jobmanager.v1.JobManager.StreamOutput:output_type -> jobmanager.v1.JobOutput
-	10, // [10:15] is the sub-list for method output_type
-	5,  // [5:10] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	5,  // 0: jobmanager.v1.JobCreationRequest.limits:type_name -> jobmanager.v1.ResourceLimits
+	6,  // 1: jobmanager.v1.ResourceLimits.blockIO:type_name -> jobmanager.v1.BlockIOLimit
+	0,  // 2: jobmanager.v1.ResourceLimits.oomPolicy:type_name -> jobmanager.v1.OomPolicy
+	7,  // 3: jobmanager.v1.ResourceLimits.knobs:type_name -> jobmanager.v1.CgroupKnob
+	8,  // 4: jobmanager.v1.Job.id:type_name -> jobmanager.v1.JobID
+	9,  // 5: jobmanager.v1.JobStatus.job:type_name -> jobmanager.v1.Job
+	2,  // 6: jobmanager.v1.JobStatus.terminationReason:type_name -> jobmanager.v1.TerminationReason
+	11, // 7: jobmanager.v1.JobStatus.usage:type_name -> jobmanager.v1.ResourceUsage
+	1,  // 8: jobmanager.v1.JobStatus.state:type_name -> jobmanager.v1.JobState
+	13, // 9: jobmanager.v1.ProcessList.processes:type_name -> jobmanager.v1.Process
+	10, // 10: jobmanager.v1.JobStatusList.jobStatusList:type_name -> jobmanager.v1.JobStatus
+	8,  // 11: jobmanager.v1.StreamOutputRequest.jobID:type_name -> jobmanager.v1.JobID
+	3,  // 12: jobmanager.v1.StreamOutputRequest.outputStream:type_name -> jobmanager.v1.OutputStream
+	8,  // 13: jobmanager.v1.ExecRequest.jobID:type_name -> jobmanager.v1.JobID
+	3,  // 14: jobmanager.v1.ExecOutput.outputStream:type_name -> jobmanager.v1.OutputStream
+	20, // 15: jobmanager.v1.ExecOutput.exitStatus:type_name -> jobmanager.v1.ExecExitStatus
+	4,  // 16: jobmanager.v1.JobManager.Start:input_type -> jobmanager.v1.JobCreationRequest
+	4,  // 17: jobmanager.v1.JobManager.Create:input_type -> jobmanager.v1.JobCreationRequest
+	8,  // 18: jobmanager.v1.JobManager.Launch:input_type -> jobmanager.v1.JobID
+	8,  // 19: jobmanager.v1.JobManager.Stop:input_type -> jobmanager.v1.JobID
+	8,  // 20: jobmanager.v1.JobManager.Remove:input_type -> jobmanager.v1.JobID
+	8,  // 21: jobmanager.v1.JobManager.Query:input_type -> jobmanager.v1.JobID
+	8,  // 22: jobmanager.v1.JobManager.Stats:input_type -> jobmanager.v1.JobID
+	8,  // 23: jobmanager.v1.JobManager.Processes:input_type -> jobmanager.v1.JobID
+	21, // 24: jobmanager.v1.JobManager.List:input_type -> jobmanager.v1.NilMessage
+	18, // 25: jobmanager.v1.JobManager.Exec:input_type -> jobmanager.v1.ExecRequest
+	17, // 26: jobmanager.v1.JobManager.StreamOutput:input_type -> jobmanager.v1.StreamOutputRequest
+	21, // 27: jobmanager.v1.JobManager.Info:input_type -> jobmanager.v1.NilMessage
+	9,  // 28: jobmanager.v1.JobManager.Start:output_type -> jobmanager.v1.Job
+	9,  // 29: jobmanager.v1.JobManager.Create:output_type -> jobmanager.v1.Job
+	21, // 30: jobmanager.v1.JobManager.Launch:output_type -> jobmanager.v1.NilMessage
+	21, // 31: jobmanager.v1.JobManager.Stop:output_type -> jobmanager.v1.NilMessage
+	21, // 32: jobmanager.v1.JobManager.Remove:output_type -> jobmanager.v1.NilMessage
+	10, // 33: jobmanager.v1.JobManager.Query:output_type -> jobmanager.v1.JobStatus
+	12, // 34: jobmanager.v1.JobManager.Stats:output_type -> jobmanager.v1.JobStats
+	14, // 35: jobmanager.v1.JobManager.Processes:output_type -> jobmanager.v1.ProcessList
+	16, // 36: jobmanager.v1.JobManager.List:output_type -> jobmanager.v1.JobStatusList
+	19, // 37: jobmanager.v1.JobManager.Exec:output_type -> jobmanager.v1.ExecOutput
+	15, // 38: jobmanager.v1.JobManager.StreamOutput:output_type -> jobmanager.v1.JobOutput
+	22, // 39: jobmanager.v1.JobManager.Info:output_type -> jobmanager.v1.ServerInfo
+	28, // [28:40] is the sub-list for method output_type
+	16, // [16:28] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_jobmanager_proto_init() }
@@ -717,7 +2135,7 @@ func file_jobmanager_proto_init() {
 			}
 		}
 		file_jobmanager_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*JobID); i {
+			switch v := v.(*ResourceLimits); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -729,7 +2147,7 @@ func file_jobmanager_proto_init() {
 			}
 		}
 		file_jobmanager_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Job); i {
+			switch v := v.(*BlockIOLimit); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -741,7 +2159,7 @@ func file_jobmanager_proto_init() {
 			}
 		}
 		file_jobmanager_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*JobStatus); i {
+			switch v := v.(*CgroupKnob); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -753,7 +2171,7 @@ func file_jobmanager_proto_init() {
 			}
 		}
 		file_jobmanager_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*JobOutput); i {
+			switch v := v.(*JobID); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -765,7 +2183,7 @@ func file_jobmanager_proto_init() {
 			}
 		}
 		file_jobmanager_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*JobStatusList); i {
+			switch v := v.(*Job); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -777,7 +2195,7 @@ func file_jobmanager_proto_init() {
 			}
 		}
 		file_jobmanager_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StreamOutputRequest); i {
+			switch v := v.(*JobStatus); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -789,6 +2207,126 @@ func file_jobmanager_proto_init() {
 			}
 		}
 		file_jobmanager_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResourceUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobmanager_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JobStats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobmanager_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Process); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobmanager_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProcessList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobmanager_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JobOutput); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobmanager_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JobStatusList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobmanager_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamOutputRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobmanager_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobmanager_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecOutput); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobmanager_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecExitStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobmanager_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*NilMessage); i {
 			case 0:
 				return &v.state
@@ -800,14 +2338,26 @@ func file_jobmanager_proto_init() {
 				return nil
 			}
 		}
+		file_jobmanager_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ServerInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_jobmanager_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   8,
+			NumEnums:      4,
+			NumMessages:   19,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -21,22 +21,62 @@ type JobManagerClient interface {
 	// Starts a new job.  The given JobCreationRequest captures the
 	// details needed by the service to create the job.  Returns a
 	// Job, that will enable further operations on the created job.
+	// This is equivalent to Create followed by Launch, except that a
+	// job that fails to start is removed, so its name can be reused.
 	Start(ctx context.Context, in *JobCreationRequest, opts ...grpc.CallOption) (*Job, error)
+	// Creates a new job without starting it.  The job is in the
+	// CREATED state until it is launched with Launch, and can be
+	// queried or stopped in the meantime.  Returns a Job, that will
+	// enable further operations on the created job.
+	Create(ctx context.Context, in *JobCreationRequest, opts ...grpc.CallOption) (*Job, error)
+	// Starts the given created Job.  Fails with FAILED_PRECONDITION if
+	// the job is not in the CREATED state, or if the job's program
+	// fails to start, in which case the job is TERMINATED.
+	//
+	// This is the second phase of the Create-then-Start lifecycle.  It
+	// is named Launch rather than Start because Start already exists
+	// in v1 as a combined create-and-start that takes a
+	// JobCreationRequest; changing it to take a JobID would break
+	// existing clients.
+	Launch(ctx context.Context, in *JobID, opts ...grpc.CallOption) (*NilMessage, error)
 	// Terminates a (potentially running) Job by sending it the
 	// SIGKILL signal.  If the specified job is no longer running,
 	// this function has no effect.
 	Stop(ctx context.Context, in *JobID, opts ...grpc.CallOption) (*NilMessage, error)
+	// Removes the given terminated Job and discards its output, which
+	// frees the resources that hold the output.  The job's name can
+	// then be reused.  Fails with FAILED_PRECONDITION if the job is
+	// running, or has been created and not stopped.
+	Remove(ctx context.Context, in *JobID, opts ...grpc.CallOption) (*NilMessage, error)
 	// Queries the state of the given Job.
 	Query(ctx context.Context, in *JobID, opts ...grpc.CallOption) (*JobStatus, error)
+	// Returns the current resource usage of the given running Job,
+	// as accounted by its cgroups.  Fails with FAILED_PRECONDITION if
+	// the job is not running.
+	Stats(ctx context.Context, in *JobID, opts ...grpc.CallOption) (*JobStats, error)
+	// Lists the processes in the given running Job's cgroups, as
+	// reported by procfs.  Fails with FAILED_PRECONDITION if the job
+	// is not running.
+	Processes(ctx context.Context, in *JobID, opts ...grpc.CallOption) (*ProcessList, error)
 	// List all jobs and their status.  Possible extensions to this
 	// might enable clients to specify a filter to reduce the
 	// resulting set.  Depending on the desired scale of the system,
 	/// paging might also be desired.
 	List(ctx context.Context, in *NilMessage, opts ...grpc.CallOption) (*JobStatusList, error)
+	// Runs an additional process in the namespaces and cgroups of the
+	// given running Job and streams its output to the client.  The
+	// final message reports the process's exit status.  The process is
+	// killed if the client cancels the call.  Fails with
+	// FAILED_PRECONDITION if the job is not running or if the process
+	// could not be started (e.g., if the program does not exist).
+	Exec(ctx context.Context, in *ExecRequest, opts ...grpc.CallOption) (JobManager_ExecClient, error)
 	// Streams the output of the running job to the client.
 	// The stream begins with the initial output generated by the Job
 	// and ends when the Job is finished.
 	StreamOutput(ctx context.Context, in *StreamOutputRequest, opts ...grpc.CallOption) (JobManager_StreamOutputClient, error)
+	// Returns information about the server, including the resource
+	// enforcement that it applies to every job.
+	Info(ctx context.Context, in *NilMessage, opts ...grpc.CallOption) (*ServerInfo, error)
 }
 
 type jobManagerClient struct {
@@ -56,6 +96,24 @@ func (c *jobManagerClient) Start(ctx context.Context, in *JobCreationRequest, op
 	return out, nil
 }
 
+func (c *jobManagerClient) Create(ctx context.Context, in *JobCreationRequest, opts ...grpc.CallOption) (*Job, error) {
+	out := new(Job)
+	err := c.cc.Invoke(ctx, "/jobmanager.v1.JobManager/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobManagerClient) Launch(ctx context.Context, in *JobID, opts ...grpc.CallOption) (*NilMessage, error) {
+	out := new(NilMessage)
+	err := c.cc.Invoke(ctx, "/jobmanager.v1.JobManager/Launch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *jobManagerClient) Stop(ctx context.Context, in *JobID, opts ...grpc.CallOption) (*NilMessage, error) {
 	out := new(NilMessage)
 	err := c.cc.Invoke(ctx, "/jobmanager.v1.JobManager/Stop", in, out, opts...)
@@ -65,6 +123,15 @@ func (c *jobManagerClient) Stop(ctx context.Context, in *JobID, opts ...grpc.Cal
 	return out, nil
 }
 
+func (c *jobManagerClient) Remove(ctx context.Context, in *JobID, opts ...grpc.CallOption) (*NilMessage, error) {
+	out := new(NilMessage)
+	err := c.cc.Invoke(ctx, "/jobmanager.v1.JobManager/Remove", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *jobManagerClient) Query(ctx context.Context, in *JobID, opts ...grpc.CallOption) (*JobStatus, error) {
 	out := new(JobStatus)
 	err := c.cc.Invoke(ctx, "/jobmanager.v1.JobManager/Query", in, out, opts...)
@@ -74,6 +141,24 @@ func (c *jobManagerClient) Query(ctx context.Context, in *JobID, opts ...grpc.Ca
 	return out, nil
 }
 
+func (c *jobManagerClient) Stats(ctx context.Context, in *JobID, opts ...grpc.CallOption) (*JobStats, error) {
+	out := new(JobStats)
+	err := c.cc.Invoke(ctx, "/jobmanager.v1.JobManager/Stats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobManagerClient) Processes(ctx context.Context, in *JobID, opts ...grpc.CallOption) (*ProcessList, error) {
+	out := new(ProcessList)
+	err := c.cc.Invoke(ctx, "/jobmanager.v1.JobManager/Processes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *jobManagerClient) List(ctx context.Context, in *NilMessage, opts ...grpc.CallOption) (*JobStatusList, error) {
 	out := new(JobStatusList)
 	err := c.cc.Invoke(ctx, "/jobmanager.v1.JobManager/List", in, out, opts...)
@@ -83,8 +168,40 @@ func (c *jobManagerClient) List(ctx context.Context, in *NilMessage, opts ...grp
 	return out, nil
 }
 
+func (c *jobManagerClient) Exec(ctx context.Context, in *ExecRequest, opts ...grpc.CallOption) (JobManager_ExecClient, error) {
+	stream, err := c.cc.NewStream(ctx, &JobManager_ServiceDesc.Streams[0], "/jobmanager.v1.JobManager/Exec", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &jobManagerExecClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type JobManager_ExecClient interface {
+	Recv() (*ExecOutput, error)
+	grpc.ClientStream
+}
+
+type jobManagerExecClient struct {
+	grpc.ClientStream
+}
+
+func (x *jobManagerExecClient) Recv() (*ExecOutput, error) {
+	m := new(ExecOutput)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *jobManagerClient) StreamOutput(ctx context.Context, in *StreamOutputRequest, opts ...grpc.CallOption) (JobManager_StreamOutputClient, error) {
-	stream, err := c.cc.NewStream(ctx, &JobManager_ServiceDesc.Streams[0], "/jobmanager.v1.JobManager/StreamOutput", opts...)
+	stream, err := c.cc.NewStream(ctx, &JobManager_ServiceDesc.Streams[1], "/jobmanager.v1.JobManager/StreamOutput", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -115,6 +232,15 @@ func (x *jobManagerStreamOutputClient) Recv() (*JobOutput, error) {
 	return m, nil
 }
 
+func (c *jobManagerClient) Info(ctx context.Context, in *NilMessage, opts ...grpc.CallOption) (*ServerInfo, error) {
+	out := new(ServerInfo)
+	err := c.cc.Invoke(ctx, "/jobmanager.v1.JobManager/Info", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // JobManagerServer is the server API for JobManager service.
 // All implementations must embed UnimplementedJobManagerServer
 // for forward compatibility
@@ -122,22 +248,62 @@ type JobManagerServer interface {
 	// Starts a new job.  The given JobCreationRequest captures the
 	// details needed by the service to create the job.  Returns a
 	// Job, that will enable further operations on the created job.
+	// This is equivalent to Create followed by Launch, except that a
+	// job that fails to start is removed, so its name can be reused.
 	Start(context.Context, *JobCreationRequest) (*Job, error)
+	// Creates a new job without starting it.  The job is in the
+	// CREATED state until it is launched with Launch, and can be
+	// queried or stopped in the meantime.  Returns a Job, that will
+	// enable further operations on the created job.
+	Create(context.Context, *JobCreationRequest) (*Job, error)
+	// Starts the given created Job.  Fails with FAILED_PRECONDITION if
+	// the job is not in the CREATED state, or if the job's program
+	// fails to start, in which case the job is TERMINATED.
+	//
+	// This is the second phase of the Create-then-Start lifecycle.  It
+	// is named Launch rather than Start because Start already exists
+	// in v1 as a combined create-and-start that takes a
+	// JobCreationRequest; changing it to take a JobID would break
+	// existing clients.
+	Launch(context.Context, *JobID) (*NilMessage, error)
 	// Terminates a (potentially running) Job by sending it the
 	// SIGKILL signal.  If the specified job is no longer running,
 	// this function has no effect.
 	Stop(context.Context, *JobID) (*NilMessage, error)
+	// Removes the given terminated Job and discards its output, which
+	// frees the resources that hold the output.  The job's name can
+	// then be reused.  Fails with FAILED_PRECONDITION if the job is
+	// running, or has been created and not stopped.
+	Remove(context.Context, *JobID) (*NilMessage, error)
 	// Queries the state of the given Job.
 	Query(context.Context, *JobID) (*JobStatus, error)
+	// Returns the current resource usage of the given running Job,
+	// as accounted by its cgroups.  Fails with FAILED_PRECONDITION if
+	// the job is not running.
+	Stats(context.Context, *JobID) (*JobStats, error)
+	// Lists the processes in the given running Job's cgroups, as
+	// reported by procfs.  Fails with FAILED_PRECONDITION if the job
+	// is not running.
+	Processes(context.Context, *JobID) (*ProcessList, error)
 	// List all jobs and their status.  Possible extensions to this
 	// might enable clients to specify a filter to reduce the
 	// resulting set.  Depending on the desired scale of the system,
 	/// paging might also be desired.
 	List(context.Context, *NilMessage) (*JobStatusList, error)
+	// Runs an additional process in the namespaces and cgroups of the
+	// given running Job and streams its output to the client.  The
+	// final message reports the process's exit status.  The process is
+	// killed if the client cancels the call.  Fails with
+	// FAILED_PRECONDITION if the job is not running or if the process
+	// could not be started (e.g., if the program does not exist).
+	Exec(*ExecRequest, JobManager_ExecServer) error
 	// Streams the output of the running job to the client.
 	// The stream begins with the initial output generated by the Job
 	// and ends when the Job is finished.
 	StreamOutput(*StreamOutputRequest, JobManager_StreamOutputServer) error
+	// Returns information about the server, including the resource
+	// enforcement that it applies to every job.
+	Info(context.Context, *NilMessage) (*ServerInfo, error)
 	mustEmbedUnimplementedJobManagerServer()
 }
 
@@ -148,18 +314,39 @@ type UnimplementedJobManagerServer struct {
 func (UnimplementedJobManagerServer) Start(context.Context, *JobCreationRequest) (*Job, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Start not implemented")
 }
+func (UnimplementedJobManagerServer) Create(context.Context, *JobCreationRequest) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedJobManagerServer) Launch(context.Context, *JobID) (*NilMessage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Launch not implemented")
+}
 func (UnimplementedJobManagerServer) Stop(context.Context, *JobID) (*NilMessage, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Stop not implemented")
 }
+func (UnimplementedJobManagerServer) Remove(context.Context, *JobID) (*NilMessage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Remove not implemented")
+}
 func (UnimplementedJobManagerServer) Query(context.Context, *JobID) (*JobStatus, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Query not implemented")
 }
+func (UnimplementedJobManagerServer) Stats(context.Context, *JobID) (*JobStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stats not implemented")
+}
+func (UnimplementedJobManagerServer) Processes(context.Context, *JobID) (*ProcessList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Processes not implemented")
+}
 func (UnimplementedJobManagerServer) List(context.Context, *NilMessage) (*JobStatusList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
 }
+func (UnimplementedJobManagerServer) Exec(*ExecRequest, JobManager_ExecServer) error {
+	return status.Errorf(codes.Unimplemented, "method Exec not implemented")
+}
 func (UnimplementedJobManagerServer) StreamOutput(*StreamOutputRequest, JobManager_StreamOutputServer) error {
 	return status.Errorf(codes.Unimplemented, "method StreamOutput not implemented")
 }
+func (UnimplementedJobManagerServer) Info(context.Context, *NilMessage) (*ServerInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Info not implemented")
+}
 func (UnimplementedJobManagerServer) mustEmbedUnimplementedJobManagerServer() {}
 
 // UnsafeJobManagerServer may be embedded to opt out of forward compatibility for this service.
@@ -191,6 +378,42 @@ func _JobManager_Start_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _JobManager_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobCreationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobManagerServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jobmanager.v1.JobManager/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobManagerServer).Create(ctx, req.(*JobCreationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _JobManager_Launch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobManagerServer).Launch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jobmanager.v1.JobManager/Launch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobManagerServer).Launch(ctx, req.(*JobID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _JobManager_Stop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(JobID)
 	if err := dec(in); err != nil {
@@ -209,6 +432,24 @@ func _JobManager_Stop_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _JobManager_Remove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobManagerServer).Remove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jobmanager.v1.JobManager/Remove",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobManagerServer).Remove(ctx, req.(*JobID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _JobManager_Query_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(JobID)
 	if err := dec(in); err != nil {
@@ -227,6 +468,42 @@ func _JobManager_Query_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _JobManager_Stats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobManagerServer).Stats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jobmanager.v1.JobManager/Stats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobManagerServer).Stats(ctx, req.(*JobID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _JobManager_Processes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobManagerServer).Processes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jobmanager.v1.JobManager/Processes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobManagerServer).Processes(ctx, req.(*JobID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _JobManager_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(NilMessage)
 	if err := dec(in); err != nil {
@@ -245,6 +522,27 @@ func _JobManager_List_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _JobManager_Exec_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExecRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(JobManagerServer).Exec(m, &jobManagerExecServer{stream})
+}
+
+type JobManager_ExecServer interface {
+	Send(*ExecOutput) error
+	grpc.ServerStream
+}
+
+type jobManagerExecServer struct {
+	grpc.ServerStream
+}
+
+func (x *jobManagerExecServer) Send(m *ExecOutput) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _JobManager_StreamOutput_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(StreamOutputRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -266,6 +564,24 @@ func (x *jobManagerStreamOutputServer) Send(m *JobOutput) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _JobManager_Info_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NilMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobManagerServer).Info(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jobmanager.v1.JobManager/Info",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobManagerServer).Info(ctx, req.(*NilMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // JobManager_ServiceDesc is the grpc.ServiceDesc for JobManager service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -277,20 +593,49 @@ var JobManager_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Start",
 			Handler:    _JobManager_Start_Handler,
 		},
+		{
+			MethodName: "Create",
+			Handler:    _JobManager_Create_Handler,
+		},
+		{
+			MethodName: "Launch",
+			Handler:    _JobManager_Launch_Handler,
+		},
 		{
 			MethodName: "Stop",
 			Handler:    _JobManager_Stop_Handler,
 		},
+		{
+			MethodName: "Remove",
+			Handler:    _JobManager_Remove_Handler,
+		},
 		{
 			MethodName: "Query",
 			Handler:    _JobManager_Query_Handler,
 		},
+		{
+			MethodName: "Stats",
+			Handler:    _JobManager_Stats_Handler,
+		},
+		{
+			MethodName: "Processes",
+			Handler:    _JobManager_Processes_Handler,
+		},
 		{
 			MethodName: "List",
 			Handler:    _JobManager_List_Handler,
 		},
+		{
+			MethodName: "Info",
+			Handler:    _JobManager_Info_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Exec",
+			Handler:       _JobManager_Exec_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "StreamOutput",
 			Handler:       _JobManager_StreamOutput_Handler,
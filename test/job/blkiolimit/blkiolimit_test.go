@@ -24,7 +24,9 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
 	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
 	"github.com/adalton/teleport-exercise/pkg/jobmanager"
 
@@ -36,18 +38,18 @@ const (
 	// Todo: writing to the root filesystem isn't ideal.  In a real scenario
 	//       this would be configurable.
 	tmpFileDirectory = "/"
-
-	deviceString = "8:16"
 )
 
 func Test_blkiolimit(t *testing.T) {
+	device, err := cgroup.ResolveBlockDevice(nil, nil, tmpFileDirectory)
+	require.Nil(t, err)
+
 	noLimit := runTest(t)
 
-	// The device portion must be a device, not a partition
-	deviceString := fmt.Sprintf("%s %d", deviceString, 1024*1024*20)
+	deviceString := fmt.Sprintf("%s %d", device, 1024*1024*20)
 	withLimit := runTest(t, &cgroupv1.BlockIOController{
-		ReadBpsDevice:  deviceString,
-		WriteBpsDevice: deviceString,
+		ReadBpsDevices:  []string{deviceString},
+		WriteBpsDevices: []string{deviceString},
 	})
 
 	// Give it a little wiggle room.  This might need some additional experimentation
@@ -58,7 +60,56 @@ func Test_blkiolimit(t *testing.T) {
 	assert.Less(t, withLimit, 20.0+limitThreshold)
 }
 
-func runTest(t *testing.T, controllers ...cgroupv1.Controller) float64 {
+func Test_blkiolimit_Iops(t *testing.T) {
+	device, err := cgroup.ResolveBlockDevice(nil, nil, tmpFileDirectory)
+	require.Nil(t, err)
+
+	const (
+		iopsLimit  = 100
+		operations = 500
+	)
+
+	noLimit := runIopsTest(t, operations)
+
+	deviceString := fmt.Sprintf("%s %d", device, iopsLimit)
+	withLimit := runIopsTest(t, operations, &cgroupv1.BlockIOController{
+		ReadIopsDevices:  []string{deviceString},
+		WriteIopsDevices: []string{deviceString},
+	})
+
+	// Give it a little wiggle room, as with the Bps limit.
+	const limitThreshold = 10.0
+
+	assert.Less(t, withLimit, noLimit)
+	assert.Less(t, withLimit, iopsLimit+limitThreshold)
+}
+
+// runIopsTest runs a job that performs the given number of small, direct
+// writes and returns the observed number of operations per second.
+func runIopsTest(t *testing.T, operations int, controllers ...cgroup.Controller) float64 {
+	file, err := ioutil.TempFile(tmpFileDirectory, "blkiolimit-test")
+	require.Nil(t, err)
+	defer os.Remove(file.Name())
+
+	job := jobmanager.NewJob("theOwner", "my-test", controllers,
+		"/bin/dd",
+		"if=/dev/zero",
+		"of="+file.Name(),
+		"bs=512",
+		fmt.Sprintf("count=%d", operations),
+		"oflag=direct",
+	)
+
+	start := time.Now()
+	require.Nil(t, job.Start())
+
+	for range job.StdoutStream().Stream() {
+	}
+
+	return float64(operations) / time.Since(start).Seconds()
+}
+
+func runTest(t *testing.T, controllers ...cgroup.Controller) float64 {
 
 	file, err := ioutil.TempFile(tmpFileDirectory, "blkiolimit-test")
 	require.Nil(t, err)
@@ -23,6 +23,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
 	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
 	"github.com/adalton/teleport-exercise/pkg/jobmanager"
 
@@ -37,7 +38,7 @@ func Test_cpulimit(t *testing.T) {
 	assert.True(t, aboutHalf(oneCpuResult, halfCpuResult))
 }
 
-func runTest(t *testing.T, controllers ...cgroupv1.Controller) float64 {
+func runTest(t *testing.T, controllers ...cgroup.Controller) float64 {
 
 	job := jobmanager.NewJob("theOwner", "my-test", controllers,
 		"/bin/bash",
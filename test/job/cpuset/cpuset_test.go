@@ -0,0 +1,52 @@
+//go:build integration
+// +build integration
+
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuset_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/jobmanager"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_cpuset(t *testing.T) {
+	assert.Equal(t, "0", runTest(t, &cgroupv1.CpusetController{Cpus: "0"}))
+}
+
+// runTest runs a job that reports the list of CPUs on which it may run.
+func runTest(t *testing.T, controllers ...cgroup.Controller) string {
+	job := jobmanager.NewJob("theOwner", "my-test", controllers,
+		"/bin/bash",
+		"-c",
+		"grep Cpus_allowed_list /proc/self/status | cut -f 2",
+	)
+
+	require.Nil(t, job.Start())
+
+	output := bytes.Buffer{}
+	for chunk := range job.StdoutStream().Stream() {
+		output.Write(chunk)
+	}
+
+	return strings.TrimSpace(output.String())
+}
@@ -0,0 +1,106 @@
+//go:build integration
+// +build integration
+
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec_test
+
+import (
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/jobmanager"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test_execJoinsPidNamespace runs a job, execs a process in it, and verifies
+// that the exec'd process sees the job's processes in its PID namespace and
+// that its exit status is reported.
+func Test_execJoinsPidNamespace(t *testing.T) {
+	job := jobmanager.NewJob("theOwner", "my-test",
+		[]cgroup.Controller{&cgroupv1.PidsController{}},
+		"/bin/bash",
+		"-c",
+		"echo started; exec /bin/sleep 1000",
+	)
+
+	require.Nil(t, job.Start())
+	defer func() { _ = job.Stop() }()
+
+	// Wait until the job is running its sleep
+	<-job.StdoutStream().Stream()
+
+	process, err := job.Exec("/bin/bash", []string{"-c", "echo $$; exit 3"})
+	require.Nil(t, err)
+
+	var output []byte
+	for chunk := range process.StdoutStream().Stream() {
+		output = append(output, chunk...)
+	}
+
+	var status *jobmanager.ExecStatus
+	select {
+	case status = <-waitFor(process):
+	case <-time.After(10 * time.Second):
+		t.Fatal("exec'd process did not terminate")
+	}
+
+	assert.Nil(t, status.RunError)
+	assert.Equal(t, 3, status.ExitCode)
+
+	// PIDs in the job's namespace are small, and PID 1 belongs to the job
+	pid, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	require.Nil(t, err)
+	assert.Greater(t, pid, 1)
+	assert.Less(t, pid, 100)
+}
+
+// Test_execMissingProgram verifies that a program that cannot be run is
+// reported by Exec itself, with the reason that cgexec reported.
+func Test_execMissingProgram(t *testing.T) {
+	job := jobmanager.NewJob("theOwner", "my-test",
+		[]cgroup.Controller{&cgroupv1.PidsController{}},
+		"/bin/bash",
+		"-c",
+		"echo started; exec /bin/sleep 1000",
+	)
+
+	require.Nil(t, job.Start())
+	defer func() { _ = job.Stop() }()
+
+	<-job.StdoutStream().Stream()
+
+	process, err := job.Exec("/no/such/program", nil)
+
+	assert.Nil(t, process)
+	assert.ErrorIs(t, err, jobmanager.ErrFailedToStart)
+	assert.ErrorIs(t, err, syscall.ENOENT)
+}
+
+func waitFor(process jobmanager.ExecProcess) <-chan *jobmanager.ExecStatus {
+	statusChannel := make(chan *jobmanager.ExecStatus, 1)
+
+	go func() {
+		statusChannel <- process.Wait()
+	}()
+
+	return statusChannel
+}
@@ -0,0 +1,189 @@
+//go:build integration
+// +build integration
+
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package initprocess_test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/config"
+	"github.com/adalton/teleport-exercise/pkg/jobmanager"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+// Test_initReapsAndForwardsSignals runs a job with cgexec as the init
+// process of its PID namespace.  It verifies that the job's program is not
+// PID 1, that an orphaned process is reaped once it terminates, and that
+// SIGTERM sent to the init process is forwarded to the program, whose exit
+// status becomes the job's.
+func Test_initReapsAndForwardsSignals(t *testing.T) {
+	config.CgexecInit = true
+	defer func() { config.CgexecInit = false }()
+
+	job := jobmanager.NewJob("theOwner", "my-test",
+		[]cgroup.Controller{&cgroupv1.PidsController{}},
+		"/bin/bash",
+		"-c",
+		"trap 'exit 7' TERM; (/bin/sleep 0.2 &); echo $$; while :; do /bin/sleep 0.1; done",
+	)
+
+	require.Nil(t, job.Start())
+	defer func() { _ = job.Stop() }()
+
+	output := <-job.StdoutStream().Stream()
+	assert.NotEqual(t, "1", strings.TrimSpace(string(output)))
+
+	var initPid int
+	assert.Eventually(t, func() bool {
+		processes, err := job.Processes()
+		if err != nil {
+			return false
+		}
+
+		initPid = 0
+		for _, process := range processes {
+			if len(process.Command) > 0 && process.Command[0] == config.CgexecPath {
+				initPid = process.Pid
+			}
+		}
+
+		// The orphaned sleep has been reparented to the init process, which
+		// must reap it once it terminates
+		for _, process := range processes {
+			if strings.Join(process.Command, " ") == "/bin/sleep 0.2" {
+				return false
+			}
+			if process.Ppid == initPid && process.State == "Z" {
+				return false
+			}
+		}
+
+		return initPid != 0
+	}, 10*time.Second, 100*time.Millisecond)
+	require.NotEqual(t, 0, initPid)
+
+	require.Nil(t, syscall.Kill(initPid, syscall.SIGTERM))
+
+	require.Eventually(t, func() bool { return !job.Status().Running },
+		10*time.Second, 100*time.Millisecond)
+	assert.Equal(t, 7, job.Status().ExitCode)
+}
+
+// Test_initSurvivesOom runs a job with cgexec as the init process of its
+// PID namespace and a program that exceeds the job's memory limit.  The
+// program's memory is in a tmpfs file, so it is not part of the program's
+// resident set, and the init process has the largest resident set in the
+// job.  It verifies that the OOM killer kills the program nonetheless, and
+// that the init process reports the program's death as the job's exit
+// status.
+func Test_initSurvivesOom(t *testing.T) {
+	if !hasCapability(unix.CAP_SYS_RESOURCE) {
+		t.Skip("exempting the init process from the OOM killer requires CAP_SYS_RESOURCE")
+	}
+
+	config.CgexecInit = true
+	defer func() { config.CgexecInit = false }()
+
+	file := fmt.Sprintf("/dev/shm/initprocess-%d", os.Getpid())
+	defer os.Remove(file)
+
+	job := jobmanager.NewJob("theOwner", "my-test",
+		[]cgroup.Controller{&cgroupv1.MemoryController{Limit: "16M"}},
+		"/bin/bash",
+		"-c",
+		fmt.Sprintf("exec /usr/bin/head -c %d /dev/zero > %s", 64*1024*1024, file))
+
+	require.Nil(t, job.Start())
+
+	for range job.StdoutStream().Stream() {
+	}
+
+	status := job.Status()
+
+	assert.False(t, status.Running)
+	assert.Equal(t, jobmanager.TerminationReasonOutOfMemory, status.TerminationReason)
+
+	// Had the init process been killed, the job would have no exit status
+	assert.Equal(t, 128+int(syscall.SIGKILL), status.ExitCode)
+}
+
+// Test_initProgramOomScore runs a job with cgexec as the init process of its
+// PID namespace.  It verifies that the job's program has the OOM score of the
+// process that started the job, and not the init process's exemption from the
+// OOM killer, which the init process has if it is privileged to give it to
+// itself.
+func Test_initProgramOomScore(t *testing.T) {
+	config.CgexecInit = true
+	defer func() { config.CgexecInit = false }()
+
+	score, err := os.ReadFile("/proc/self/oom_score_adj")
+	require.Nil(t, err)
+
+	job := jobmanager.NewJob("theOwner", "my-test",
+		[]cgroup.Controller{&cgroupv1.PidsController{}},
+		"/bin/bash",
+		"-c",
+		"read score < /proc/self/oom_score_adj; echo $score; exec /bin/sleep 10",
+	)
+
+	require.Nil(t, job.Start())
+	defer func() { _ = job.Stop() }()
+
+	output := <-job.StdoutStream().Stream()
+	assert.Equal(t, strings.TrimSpace(string(score)), strings.TrimSpace(string(output)))
+
+	if !hasCapability(unix.CAP_SYS_RESOURCE) {
+		return
+	}
+
+	processes, err := job.Processes()
+	require.Nil(t, err)
+
+	initPid := 0
+	for _, process := range processes {
+		if len(process.Command) > 0 && process.Command[0] == config.CgexecPath {
+			initPid = process.Pid
+		}
+	}
+	require.NotEqual(t, 0, initPid)
+
+	initScore, err := os.ReadFile(fmt.Sprintf("/proc/%d/oom_score_adj", initPid))
+	require.Nil(t, err)
+	assert.Equal(t, "-1000", strings.TrimSpace(string(initScore)))
+}
+
+// hasCapability returns true if this process has the given capability in its
+// effective set.
+func hasCapability(capability int) bool {
+	header := unix.CapUserHeader{Version: unix.LINUX_CAPABILITY_VERSION_3}
+	var data [2]unix.CapUserData
+
+	if err := unix.Capget(&header, &data[0]); err != nil {
+		return false
+	}
+
+	return data[capability/32].Effective&(1<<(capability%32)) != 0
+}
@@ -0,0 +1,87 @@
+//go:build integration
+// +build integration
+
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/jobmanager"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test_createThenStart creates a job, verifies that it does not run until it
+// is started, and then verifies that it runs to completion.
+func Test_createThenStart(t *testing.T) {
+	job := jobmanager.NewJob("theOwner", "my-test",
+		[]cgroup.Controller{&cgroupv1.PidsController{}},
+		"/bin/echo",
+		"hello",
+	)
+
+	require.Nil(t, job.Create())
+
+	status := job.Status()
+	assert.Equal(t, jobmanager.JobStateCreated, status.State)
+	assert.False(t, status.Running)
+	assert.Equal(t, -1, status.Pid)
+
+	assert.ErrorIs(t, job.Create(), jobmanager.ErrInvalidJobState)
+
+	require.Nil(t, job.Start())
+
+	output := ""
+	for chunk := range job.StdoutStream().Stream() {
+		output += string(chunk)
+	}
+	assert.Equal(t, "hello\n", output)
+
+	assert.Eventually(t, func() bool { return job.Status().State == jobmanager.JobStateTerminated },
+		10*time.Second, 100*time.Millisecond)
+	assert.Equal(t, 0, job.Status().ExitCode)
+
+	assert.ErrorIs(t, job.Start(), jobmanager.ErrInvalidJobState)
+}
+
+// Test_stopCreatedJob stops a job that was created but never started and
+// verifies that it can no longer be started.
+func Test_stopCreatedJob(t *testing.T) {
+	job := jobmanager.NewJob("theOwner", "my-test",
+		[]cgroup.Controller{&cgroupv1.PidsController{}},
+		"/bin/echo",
+		"hello",
+	)
+
+	require.Nil(t, job.Create())
+	require.Nil(t, job.Stop())
+
+	status := job.Status()
+	assert.Equal(t, jobmanager.JobStateTerminated, status.State)
+	assert.Equal(t, jobmanager.TerminationReasonStopped, status.TerminationReason)
+	assert.False(t, status.Running)
+
+	assert.ErrorIs(t, job.Start(), jobmanager.ErrInvalidJobState)
+
+	// The job's output is closed without the program having run
+	for chunk := range job.StdoutStream().Stream() {
+		assert.Equal(t, "", string(chunk))
+	}
+}
@@ -21,7 +21,9 @@ import (
 	"fmt"
 	"io"
 	"testing"
+	"time"
 
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
 	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
 	"github.com/adalton/teleport-exercise/pkg/jobmanager"
 
@@ -36,8 +38,33 @@ func Test_memorylimit(t *testing.T) {
 	limitCount := runTest(t, &cgroupv1.MemoryController{Limit: "1M"})
 	require.Greater(t, limitCount, 0)
 }
+func Test_memorylimit_OomKilled(t *testing.T) {
+	// Bash holds the entire output of the command substitution in memory,
+	// so it is the process that the OOM killer selects.
+	job := jobmanager.NewJob("theOwner", "my-test",
+		[]cgroup.Controller{&cgroupv1.MemoryController{Limit: "16M"}},
+		"/bin/bash",
+		"-c",
+		fmt.Sprintf("x=$(head -c %d /dev/zero | tr '\\0' x); echo done", 64*1024*1024))
+
+	require.Nil(t, job.Start())
+
+	for range job.StdoutStream().Stream() {
+	}
+
+	status := job.Status()
+
+	assert.False(t, status.Running)
+	assert.Equal(t, jobmanager.TerminationReasonOutOfMemory, status.TerminationReason)
+	assert.Greater(t, status.OomKills, uint64(0))
+	assert.Greater(t, status.PeakMemoryUsage, uint64(0))
+
+	require.NotNil(t, status.Usage)
+	assert.Equal(t, status.PeakMemoryUsage, status.Usage.PeakMemoryUsage)
+	assert.Greater(t, status.Usage.UserTime+status.Usage.SystemTime, time.Duration(0))
+}
 
-func runTest(t *testing.T, controllers ...cgroupv1.Controller) int {
+func runTest(t *testing.T, controllers ...cgroup.Controller) int {
 
 	cmd := fmt.Sprintf("/usr/bin/stress-ng --vm 1 --vm-bytes %d --timeout 10 --oomable -v 2>&1 | grep 'OOM killer'", 1024*1024*1024)
 
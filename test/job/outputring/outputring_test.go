@@ -0,0 +1,72 @@
+//go:build integration
+// +build integration
+
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package outputring_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/config"
+	"github.com/adalton/teleport-exercise/pkg/io"
+	"github.com/adalton/teleport-exercise/pkg/jobmanager"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test_outputRetainsMostRecent runs a job whose output is kept in ring
+// buffers that are smaller than the output, and verifies that a client that
+// streams the output after the job has terminated receives a truncation
+// marker followed by the most recent output.
+func Test_outputRetainsMostRecent(t *testing.T) {
+	const capacity = 64
+
+	savedType, savedCapacity := config.OutputBufferType, config.OutputRingBufferCapacity
+	config.OutputBufferType, config.OutputRingBufferCapacity = config.OutputBufferRing, capacity
+	defer func() { config.OutputBufferType, config.OutputRingBufferCapacity = savedType, savedCapacity }()
+
+	job := jobmanager.NewJob("theOwner", "my-test",
+		[]cgroup.Controller{&cgroupv1.PidsController{}},
+		"/bin/bash",
+		"-c",
+		"for i in $(seq 1000); do echo line $i; done",
+	)
+
+	require.Nil(t, job.Start())
+
+	require.Eventually(t, func() bool { return job.Status().State == jobmanager.JobStateTerminated },
+		10*time.Second, 100*time.Millisecond)
+
+	// The whole output is "line 1\n" through "line 1000\n"
+	var expected strings.Builder
+	for i := 1; i <= 1000; i++ {
+		fmt.Fprintf(&expected, "line %d\n", i)
+	}
+	tail := expected.String()[expected.Len()-capacity:]
+	truncated := int64(expected.Len() - capacity)
+
+	var chunks []io.Chunk
+	for chunk := range job.StdoutStream().Chunks() {
+		chunks = append(chunks, chunk)
+	}
+
+	assert.Equal(t, []io.Chunk{{Truncated: truncated}, {Data: []byte(tail)}}, chunks)
+}
@@ -0,0 +1,97 @@
+//go:build integration
+// +build integration
+
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package outputspool_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/config"
+	"github.com/adalton/teleport-exercise/pkg/jobmanager"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test_outputSpooledToFile runs a job whose output is spooled to files and
+// verifies that the output can be streamed, including after the job has
+// terminated, and that the spool files do not appear in the spool directory.
+func Test_outputSpooledToFile(t *testing.T) {
+	spoolDir := t.TempDir()
+
+	savedType, savedDir := config.OutputBufferType, config.OutputSpoolDir
+	config.OutputBufferType, config.OutputSpoolDir = config.OutputBufferFile, spoolDir
+	defer func() { config.OutputBufferType, config.OutputSpoolDir = savedType, savedDir }()
+
+	require.Nil(t, jobmanager.PrepareOutputBuffers(config.OutputBufferType, config.OutputSpoolDir,
+		config.OutputRingBufferCapacity))
+
+	job := jobmanager.NewJob("theOwner", "my-test",
+		[]cgroup.Controller{&cgroupv1.PidsController{}},
+		"/bin/bash",
+		"-c",
+		"for i in $(seq 1000); do echo line $i; done; echo oops >&2",
+	)
+
+	require.Nil(t, job.Start())
+
+	stdout := readAll(job.StdoutStream().Stream())
+	assert.Equal(t, 1000, strings.Count(stdout, "\n"))
+	assert.Equal(t, "oops\n", readAll(job.StderrStream().Stream()))
+
+	// A new reader gets the whole output again once the job has terminated
+	assert.Equal(t, stdout, readAll(job.StdoutStream().Stream()))
+
+	entries, err := os.ReadDir(spoolDir)
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+
+	// Releasing the job closes its spool files, which frees their space
+	assert.Equal(t, 2, openSpoolFiles(t, spoolDir))
+	require.Nil(t, job.Release())
+	assert.Equal(t, 0, openSpoolFiles(t, spoolDir))
+}
+
+// openSpoolFiles returns the number of files in the given spool directory
+// that this process has open.
+func openSpoolFiles(t *testing.T, spoolDir string) int {
+	fds, err := os.ReadDir("/proc/self/fd")
+	require.Nil(t, err)
+
+	count := 0
+	for _, fd := range fds {
+		target, err := os.Readlink(filepath.Join("/proc/self/fd", fd.Name()))
+		if err == nil && strings.HasPrefix(target, spoolDir+"/") {
+			count++
+		}
+	}
+
+	return count
+}
+
+func readAll(stream <-chan []byte) string {
+	output := ""
+	for chunk := range stream {
+		output += string(chunk)
+	}
+
+	return output
+}
@@ -0,0 +1,50 @@
+//go:build integration
+// +build integration
+
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pidslimit_test
+
+import (
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/jobmanager"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_pidslimit(t *testing.T) {
+	assert.False(t, runTest(t))
+	assert.True(t, runTest(t, &cgroupv1.PidsController{Limit: 8}))
+}
+
+// runTest runs a job that tries to run 16 processes concurrently and returns
+// whether the job reported reaching its pids limit.
+func runTest(t *testing.T, controllers ...cgroup.Controller) bool {
+	job := jobmanager.NewJob("theOwner", "my-test", controllers,
+		"/bin/bash",
+		"-c",
+		"for i in $(seq 16); do /bin/sleep 1 & done; wait",
+	)
+
+	require.Nil(t, job.Start())
+
+	for range job.StdoutStream().Stream() {
+	}
+
+	return job.Status().PidsLimitReached
+}
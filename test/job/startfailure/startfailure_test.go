@@ -0,0 +1,60 @@
+//go:build integration
+// +build integration
+
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package startfailure_test
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/jobmanager"
+	"github.com/adalton/teleport-exercise/pkg/startstatus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_missingProgramFailsToStart runs a job whose program does not exist and
+// verifies that Start reports cgexec's failure and that the job ends up in
+// the FailedToStart state.
+func Test_missingProgramFailsToStart(t *testing.T) {
+	job := jobmanager.NewJob("theOwner", "my-test",
+		[]cgroup.Controller{&cgroupv1.PidsController{}},
+		"/no/such/program",
+	)
+
+	err := job.Start()
+
+	assert.ErrorIs(t, err, jobmanager.ErrFailedToStart)
+	assert.ErrorIs(t, err, syscall.ENOENT)
+
+	var startErr *startstatus.Error
+	if assert.ErrorAs(t, err, &startErr) {
+		assert.Equal(t, "exec", startErr.Op)
+		assert.Equal(t, "/no/such/program", startErr.Path)
+	}
+
+	status := job.Status()
+	assert.False(t, status.Running)
+	assert.Equal(t, jobmanager.TerminationReasonFailedToStart, status.TerminationReason)
+	assert.NotNil(t, status.RunError)
+
+	// The job produced no output of its own
+	for output := range job.StdoutStream().Stream() {
+		assert.Equal(t, "", string(output))
+	}
+}
@@ -0,0 +1,71 @@
+//go:build integration
+// +build integration
+
+/*
+Copyright 2021 Andy Dalton
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stop_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adalton/teleport-exercise/pkg/cgroup"
+	"github.com/adalton/teleport-exercise/pkg/cgroup/cgroupv1"
+	"github.com/adalton/teleport-exercise/pkg/jobmanager"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test_stopKillsDescendants runs a job whose children outlive the job's
+// top-level process and ignore SIGTERM and SIGHUP, and verifies that Stop
+// kills all of them.
+func Test_stopKillsDescendants(t *testing.T) {
+	job := jobmanager.NewJob("theOwner", "my-test",
+		[]cgroup.Controller{&cgroupv1.PidsController{}},
+		"/bin/bash",
+		"-c",
+		"trap '' TERM HUP; for i in $(seq 4); do /bin/sleep 1000 & done; echo started; wait",
+	)
+
+	require.Nil(t, job.Start())
+
+	// Wait until the children have been started
+	stream := job.StdoutStream().Stream()
+	<-stream
+
+	require.Nil(t, job.Stop())
+
+	// The job's output is complete once every process has exited
+	select {
+	case <-drain(stream):
+	case <-time.After(10 * time.Second):
+		t.Fatal("job's processes survived Stop")
+	}
+
+	assert.Eventually(t, func() bool { return !job.Status().Running },
+		10*time.Second, 100*time.Millisecond)
+}
+
+func drain(stream <-chan []byte) <-chan struct{} {
+	done := make(chan struct{})
+
+	go func() {
+		for range stream {
+		}
+		close(done)
+	}()
+
+	return done
+}
@@ -157,7 +157,7 @@ func Test_clientServer_Multitenant(t *testing.T) {
 	require.Nil(t, err)
 	defer user1Client.Close()
 
-	_, err = user1Client.Start(context.Background(), "myjob", "/bin/true")
+	_, err = user1Client.Start(context.Background(), "myjob", nil, "/bin/true")
 	assert.Nil(t, err)
 
 	user2Client, err := jobmanager.NewClient("client2", hostPort)
@@ -185,7 +185,7 @@ func Test_clientServer_AdministratorCanSeeAllJobs(t *testing.T) {
 	require.Nil(t, err)
 	defer user1Client.Close()
 
-	_, err = user1Client.Start(context.Background(), "myjob", "/bin/true")
+	_, err = user1Client.Start(context.Background(), "myjob", nil, "/bin/true")
 	assert.Nil(t, err)
 
 	adminClient, err := jobmanager.NewClient(jobmanager.Superuser, hostPort)